@@ -725,3 +725,21 @@ get_version(name) = version {
 	splited := split(val, ".")
 	version := concat(".", [splited[0],splited[1]])
 }
+
+# removal_remediation returns the remediation of the type "removal", the attribute to remove is the last item of the path
+# the result searchKey should address the attribute
+# Example:
+# "remediation": common_lib.removal_remediation(["spec", "containers", "securityContext", "privileged"]),
+# "remediationType": "removal",
+removal_remediation(path) = attribute {
+	attribute := sprintf("%v", [path[count(path) - 1]])
+}
+
+# block_remediation returns the remediation of the type "block", the block is added to the element addressed by the result searchKey
+# nested objects are written as nested blocks (or mappings) and the remaining values as attributes
+# Example:
+# "remediation": common_lib.block_remediation("versioning", {"enabled": true}),
+# "remediationType": "block",
+block_remediation(name, block) = remediation {
+	remediation := json.marshal({name: block})
+}
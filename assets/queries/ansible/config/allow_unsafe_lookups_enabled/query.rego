@@ -15,5 +15,10 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "allow_unsafe_lookups should be set to 'False'",
 		"keyActualValue": "allow_unsafe_lookups is set to 'True'",
+		"remediation": json.marshal({
+			"before": "True",
+			"after": "False"
+		}),
+		"remediationType": "replacement",
 	}
 }
@@ -17,5 +17,10 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "'server' from galaxy group should be accessed via the HTTPS protocol",
 		"keyActualValue": "'server' from galaxy group is accessed via the HTTP protocol'",
+		"remediation": json.marshal({
+			"before": "http://",
+			"after": "https://"
+		}),
+		"remediationType": "replacement",
 	}
 }
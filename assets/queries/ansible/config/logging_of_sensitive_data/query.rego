@@ -15,6 +15,8 @@ CxPolicy[result] {
 		"resourceName": "n/a",
 		"keyExpectedValue": "no_log should be defined and set to 'true'",
 		"keyActualValue": "no_log is not defined",
+		"remediation": "no_log = True",
+		"remediationType": "addition",
 	}
 }
 
@@ -31,5 +33,10 @@ CxPolicy[result] {
 		"resourceName": "n/a",
 		"keyExpectedValue": "no_log should be set to 'true'",
 		"keyActualValue": "no_log is set to 'false'",
+		"remediation": json.marshal({
+			"before": "False",
+			"after": "True"
+		}),
+		"remediationType": "replacement",
 	}
 }
\ No newline at end of file
@@ -17,6 +17,8 @@ CxPolicy[result] {
 		"issueType": "MissingAttribute",
 		"keyExpectedValue": "'become' should be defined and set to 'true'",
 		"keyActualValue": "'become' is not defined",
+		"remediation": "become = True",
+		"remediationType": "addition",
 	}
 }
 
@@ -34,5 +36,10 @@ CxPolicy[result] {
 		"issueType": "IncorrectValue",
 		"keyExpectedValue": "'become' should be set to 'true'",
 		"keyActualValue": "'become' is set to 'false'",
+		"remediation": json.marshal({
+			"before": "False",
+			"after": "True"
+		}),
+		"remediationType": "replacement",
 	}
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package Cx
+
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	resource := input.document[i]
+	service_parameters := resource.services[name]
+
+	bom_output = {
+		"resource_type": "docker_compose_service",
+		"resource_name": get_name(service_parameters, name),
+		"resource_accessibility": get_accessibility(service_parameters),
+		"resource_encryption": "unknown",
+		"resource_vendor": "Docker",
+		"resource_category": "Compute",
+		"resource_engine": get_image(service_parameters),
+	}
+
+	result := {
+		"documentId": sprintf("%s", [resource.id]),
+		"searchKey": sprintf("services.%s", [name]),
+		"issueType": "BillOfMaterials",
+		"keyExpectedValue": "",
+		"keyActualValue": "",
+		"searchLine": common_lib.build_search_line(["services", name], []),
+		"value": json.marshal(bom_output),
+	}
+}
+
+get_name(service_parameters, name) = service_name {
+	service_name := service_parameters.container_name
+} else = service_name {
+	service_name := name
+}
+
+get_image(service_parameters) = image {
+	image := service_parameters.image
+} else = image {
+	image := "unknown"
+}
+
+# the ports not bound to a host interface are published on all the host addresses
+get_accessibility(service_parameters) = accessibility {
+	port := service_parameters.ports[_]
+	published_on_all_interfaces(port)
+	accessibility := "public"
+} else = accessibility {
+	service_parameters.network_mode == "host"
+	accessibility := "public"
+} else = accessibility {
+	accessibility := "private"
+}
+
+published_on_all_interfaces(port) {
+	is_object(port)
+	not common_lib.valid_key(port, "host_ip")
+} else {
+	not is_object(port)
+	count(split(sprintf("%v", [port]), ":")) < 3
+}
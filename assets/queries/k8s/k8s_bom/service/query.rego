@@ -0,0 +1,41 @@
+package Cx
+
+import data.generic.k8s as k8s
+
+CxPolicy[result] {
+	document := input.document[i]
+	metadata := document.metadata
+	k8s.checkKind(document.kind, ["Service"])
+
+	bom_output = {
+		"resource_type": document.kind,
+		"resource_name": metadata.name,
+		"resource_accessibility": get_accessibility(document.spec),
+		"resource_encryption": "unknown",
+		"resource_vendor": "Kubernetes",
+		"resource_category": "Networking",
+	}
+
+	result := {
+		"documentId": input.document[i].id,
+		"resourceType": document.kind,
+		"resourceName": metadata.name,
+		"searchKey": sprintf("metadata.name={{%s}}", [metadata.name]),
+		"issueType": "BillOfMaterials",
+		"keyExpectedValue": "",
+		"keyActualValue": "",
+		"value": json.marshal(bom_output),
+	}
+}
+
+# the load balancer and node port services are reachable from outside the cluster, the default type is ClusterIP
+get_accessibility(spec) = accessibility {
+	exposed := {"loadbalancer", "nodeport"}
+	exposed[lower(spec.type)]
+	accessibility := "public"
+} else = accessibility {
+	lower(spec.type) == "externalname"
+	accessibility := "unknown"
+} else = accessibility {
+	accessibility := "private"
+}
@@ -0,0 +1,38 @@
+package Cx
+
+import data.generic.k8s as k8s
+
+CxPolicy[result] {
+	document := input.document[i]
+	metadata := document.metadata
+	k8s.checkKind(document.kind, ["Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob"])
+	specInfo := k8s.getSpecInfo(document)
+
+	bom_output = {
+		"resource_type": document.kind,
+		"resource_name": metadata.name,
+		"resource_accessibility": get_accessibility(specInfo.spec),
+		"resource_encryption": "unknown",
+		"resource_vendor": "Kubernetes",
+		"resource_category": "Compute",
+	}
+
+	result := {
+		"documentId": input.document[i].id,
+		"resourceType": document.kind,
+		"resourceName": metadata.name,
+		"searchKey": sprintf("metadata.name={{%s}}", [metadata.name]),
+		"issueType": "BillOfMaterials",
+		"keyExpectedValue": "",
+		"keyActualValue": "",
+		"value": json.marshal(bom_output),
+	}
+}
+
+# the pods sharing the host network are reachable on the node addresses, otherwise through their services
+get_accessibility(spec) = accessibility {
+	spec.hostNetwork == true
+	accessibility := "public"
+} else = accessibility {
+	accessibility := "unknown"
+}
@@ -63,6 +63,8 @@ CxPolicy[result] {
 		"keyExpectedValue": "'kms_master_key_id' should be null when algorithm is 'AES256'",
 		"keyActualValue": "'kms_master_key_id'is not null when algorithm is 'AES256'",
 		"searchLine": common_lib.build_search_line(["resource", "aws_s3_bucket", name, "server_side_encryption_configuration", "rule", "apply_server_side_encryption_by_default", "kms_master_key_id"], []),
+		"remediation": common_lib.removal_remediation(["server_side_encryption_configuration", "rule", "apply_server_side_encryption_by_default", "kms_master_key_id"]),
+		"remediationType": "removal",
 	}
 }
 
@@ -122,6 +124,8 @@ CxPolicy[result] {
 		"keyExpectedValue": "'aws_s3_bucket' to have 'server_side_encryption_configuration' associated",
 		"keyActualValue": "'aws_s3_bucket' does not have 'server_side_encryption_configuration' associated",
 		"searchLine": common_lib.build_search_line(["resource", "aws_s3_bucket", bucketName], []),
+		"remediation": common_lib.block_remediation("server_side_encryption_configuration", {"rule": {"apply_server_side_encryption_by_default": {"sse_algorithm": "AES256"}}}),
+		"remediationType": "block",
 	}
 }
 
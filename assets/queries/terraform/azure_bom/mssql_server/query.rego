@@ -0,0 +1,36 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.terraform as tf_lib
+
+CxPolicy[result] {
+	mssql_server := input.document[i].resource.azurerm_mssql_server[name]
+
+	bom_output = {
+		"resource_type": "azurerm_mssql_server",
+		"resource_name": tf_lib.get_resource_name(mssql_server, name),
+		"resource_accessibility": get_accessibility(mssql_server),
+		"resource_encryption": "encrypted",
+		"resource_vendor": "Azure",
+		"resource_category": "Storage",
+		"resource_engine": "sqlserver",
+	}
+
+	result := {
+		"documentId": input.document[i].id,
+		"searchKey": sprintf("azurerm_mssql_server[%s]", [name]),
+		"issueType": "BillOfMaterials",
+		"keyExpectedValue": "",
+		"keyActualValue": "",
+		"searchLine": common_lib.build_search_line(["resource", "azurerm_mssql_server", name], []),
+		"value": json.marshal(bom_output),
+	}
+}
+
+# the public network access of the server is enabled by default
+get_accessibility(mssql_server) = accessibility {
+	mssql_server.public_network_access_enabled == false
+	accessibility := "private"
+} else = accessibility {
+	accessibility := "public"
+}
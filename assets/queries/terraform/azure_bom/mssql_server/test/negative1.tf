@@ -0,0 +1,4 @@
+resource "azurerm_mssql_database" "negative1" {
+  name      = "example-db"
+  server_id = azurerm_mssql_server.example.id
+}
@@ -0,0 +1,8 @@
+resource "azurerm_mssql_server" "positive1" {
+  name                         = "mssqlserver"
+  resource_group_name          = azurerm_resource_group.example.name
+  location                     = azurerm_resource_group.example.location
+  version                      = "12.0"
+  administrator_login          = "missadministrator"
+  administrator_login_password = var.administrator_password
+}
@@ -0,0 +1,7 @@
+resource "azurerm_mssql_server" "positive2" {
+  name                          = "privatemssqlserver"
+  resource_group_name           = azurerm_resource_group.example.name
+  location                      = azurerm_resource_group.example.location
+  version                       = "12.0"
+  public_network_access_enabled = false
+}
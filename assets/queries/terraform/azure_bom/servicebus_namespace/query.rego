@@ -0,0 +1,35 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.terraform as tf_lib
+
+CxPolicy[result] {
+	namespace := input.document[i].resource.azurerm_servicebus_namespace[name]
+
+	bom_output = {
+		"resource_type": "azurerm_servicebus_namespace",
+		"resource_name": tf_lib.get_resource_name(namespace, name),
+		"resource_accessibility": get_accessibility(namespace),
+		"resource_encryption": "encrypted",
+		"resource_vendor": "Azure",
+		"resource_category": "Messaging",
+	}
+
+	result := {
+		"documentId": input.document[i].id,
+		"searchKey": sprintf("azurerm_servicebus_namespace[%s]", [name]),
+		"issueType": "BillOfMaterials",
+		"keyExpectedValue": "",
+		"keyActualValue": "",
+		"searchLine": common_lib.build_search_line(["resource", "azurerm_servicebus_namespace", name], []),
+		"value": json.marshal(bom_output),
+	}
+}
+
+# the public network access of the namespace is enabled by default
+get_accessibility(namespace) = accessibility {
+	namespace.public_network_access_enabled == false
+	accessibility := "private"
+} else = accessibility {
+	accessibility := "public"
+}
@@ -0,0 +1,4 @@
+resource "azurerm_servicebus_queue" "negative1" {
+  name         = "tfex_servicebus_queue"
+  namespace_id = azurerm_servicebus_namespace.example.id
+}
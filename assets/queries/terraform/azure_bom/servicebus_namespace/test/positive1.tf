@@ -0,0 +1,6 @@
+resource "azurerm_servicebus_namespace" "positive1" {
+  name                = "tfex-servicebus-namespace"
+  location            = azurerm_resource_group.example.location
+  resource_group_name = azurerm_resource_group.example.name
+  sku                 = "Standard"
+}
@@ -0,0 +1,52 @@
+package Cx
+
+import data.generic.common as common_lib
+import data.generic.terraform as tf_lib
+
+CxPolicy[result] {
+	storage_account := input.document[i].resource.azurerm_storage_account[name]
+
+	bom_output = {
+		"resource_type": "azurerm_storage_account",
+		"resource_name": tf_lib.get_resource_name(storage_account, name),
+		"resource_accessibility": get_accessibility(storage_account),
+		"resource_encryption": get_encryption(storage_account),
+		"resource_vendor": "Azure",
+		"resource_category": "Storage",
+	}
+
+	result := {
+		"documentId": input.document[i].id,
+		"searchKey": sprintf("azurerm_storage_account[%s]", [name]),
+		"issueType": "BillOfMaterials",
+		"keyExpectedValue": "",
+		"keyActualValue": "",
+		"searchLine": common_lib.build_search_line(["resource", "azurerm_storage_account", name], []),
+		"value": json.marshal(bom_output),
+	}
+}
+
+get_accessibility(storage_account) = accessibility {
+	public_fields := {"allow_nested_items_to_be_public", "allow_blob_public_access"}
+	storage_account[public_fields[_]] == true
+	accessibility := "public"
+} else = accessibility {
+	storage_account.public_network_access_enabled == false
+	accessibility := "private"
+} else = accessibility {
+	storage_account.network_rules.default_action == "Deny"
+	accessibility := "private"
+} else = accessibility {
+	accessibility := "unknown"
+}
+
+# storage accounts are encrypted at rest, the encryption is reported as missing when the traffic is allowed over http
+get_encryption(storage_account) = encryption {
+	storage_account.enable_https_traffic_only == false
+	encryption := "unencrypted"
+} else = encryption {
+	storage_account.https_traffic_only_enabled == false
+	encryption := "unencrypted"
+} else = encryption {
+	encryption := "encrypted"
+}
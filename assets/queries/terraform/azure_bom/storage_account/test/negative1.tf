@@ -0,0 +1,5 @@
+resource "azurerm_storage_container" "negative1" {
+  name                  = "content"
+  storage_account_name  = "examplestoraccount"
+  container_access_type = "private"
+}
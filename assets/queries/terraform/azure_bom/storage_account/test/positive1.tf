@@ -0,0 +1,8 @@
+resource "azurerm_storage_account" "positive1" {
+  name                     = "examplestoraccount"
+  resource_group_name      = azurerm_resource_group.example.name
+  location                 = azurerm_resource_group.example.location
+  account_tier             = "Standard"
+  account_replication_type = "GRS"
+  allow_nested_items_to_be_public = true
+}
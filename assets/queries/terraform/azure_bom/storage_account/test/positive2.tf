@@ -0,0 +1,8 @@
+resource "azurerm_storage_account" "positive2" {
+  name                          = "privatestoraccount"
+  resource_group_name           = azurerm_resource_group.example.name
+  location                      = azurerm_resource_group.example.location
+  account_tier                  = "Standard"
+  account_replication_type      = "LRS"
+  public_network_access_enabled = false
+}
@@ -2,22 +2,41 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/Checkmarx/kics/pkg/builder/engine"
+	build "github.com/Checkmarx/kics/pkg/builder/model"
 	"github.com/Checkmarx/kics/pkg/builder/writer"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
+type options struct {
+	inPath    string
+	outPath   string
+	platform  string
+	queryDir  string
+	queryName string
+	severity  string
+	category  string
+	provider  string
+}
+
+type expectedResult struct {
+	QueryName string `json:"queryName"`
+	Severity  string `json:"severity"`
+	Line      int    `json:"line"`
+	Filename  string `json:"filename"`
+}
+
 func main() {
-	var (
-		inPath  string
-		outPath string
-	)
+	var opts options
 
 	ctx := context.Background()
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout})
@@ -27,45 +46,166 @@ func main() {
 		Use:   "inspect",
 		Short: "Tool to build new query from example file",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			content, err := os.ReadFile(filepath.Clean(inPath))
-			if err != nil {
-				return err
+			if opts.outPath == "" && opts.queryDir == "" {
+				return errors.New("one of --out or --query-dir is required")
 			}
 
-			rules, err := engine.Run(content, path.Base(inPath))
+			content, err := os.ReadFile(filepath.Clean(opts.inPath))
 			if err != nil {
 				return err
 			}
 
-			regoWriter, err := writer.NewRegoWriter()
-			if err != nil {
-				return err
+			platform := opts.platform
+			if platform == "" && strings.EqualFold(filepath.Ext(opts.inPath), ".tf") {
+				platform = build.PlatformTerraform
 			}
-
-			outContent, err := regoWriter.Render(rules)
+			platform, err = engine.LookupPlatform(platform)
 			if err != nil {
 				return err
 			}
 
-			return saveFile(outPath, outContent)
+			if platform == build.PlatformTerraform {
+				return buildTerraform(content, platform, &opts)
+			}
+			return buildYAML(content, platform, &opts)
 		},
 	}
 
-	rootCmd.Flags().StringVarP(&inPath, "in", "i", "", "path for in file")
-	rootCmd.Flags().StringVarP(&outPath, "out", "o", "", "path for out path")
+	rootCmd.Flags().StringVarP(&opts.inPath, "in", "i", "", "path for in file")
+	rootCmd.Flags().StringVarP(&opts.outPath, "out", "o", "", "path for out path")
+	rootCmd.Flags().StringVarP(&opts.platform, "platform", "p", "",
+		"platform of the in file, one of "+strings.Join(build.Platforms(), ", ")+" (Terraform for .tf files)")
+	rootCmd.Flags().StringVar(&opts.queryDir, "query-dir", "",
+		"directory to write the query, its metadata and test samples built from the in file")
+	rootCmd.Flags().StringVar(&opts.queryName, "name", "Generated Query", "name of the query in the metadata")
+	rootCmd.Flags().StringVar(&opts.severity, "severity", "MEDIUM", "severity of the query in the metadata")
+	rootCmd.Flags().StringVar(&opts.category, "category", "Insecure Configurations", "category of the query in the metadata")
+	rootCmd.Flags().StringVar(&opts.provider, "cloud-provider", "",
+		"cloud provider of the query in the metadata, common by default for Terraform, CloudFormation and Ansible")
 
 	if err := rootCmd.MarkFlagRequired("in"); err != nil {
 		log.Err(err).Msg("Failed to add command required flags")
 	}
-	if err := rootCmd.MarkFlagRequired("out"); err != nil {
-		log.Err(err).Msg("Failed to add command required flags")
-	}
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		os.Exit(-1)
 	}
 }
 
+func buildTerraform(content []byte, platform string, opts *options) error {
+	example, err := engine.RunExample(content, path.Base(opts.inPath))
+	if err != nil {
+		return err
+	}
+
+	regoWriter, err := writer.NewRegoWriter()
+	if err != nil {
+		return err
+	}
+
+	outContent, err := regoWriter.Render(example.Rules)
+	if err != nil {
+		return err
+	}
+
+	if err := saveQuery(outContent, platform, opts); err != nil {
+		return err
+	}
+	if opts.queryDir == "" {
+		return nil
+	}
+	return saveSamples(example, opts)
+}
+
+func buildYAML(content []byte, platform string, opts *options) error {
+	example, err := engine.RunYAML(content, path.Base(opts.inPath), platform)
+	if err != nil {
+		return err
+	}
+	if len(example.Rules) == 0 {
+		return errors.New("no tagged attributes found in " + opts.inPath)
+	}
+
+	yamlWriter, err := writer.NewYAMLWriter()
+	if err != nil {
+		return err
+	}
+
+	outContent, err := yamlWriter.Render(platform, example.Rules)
+	if err != nil {
+		return err
+	}
+
+	if err := saveQuery(outContent, platform, opts); err != nil {
+		return err
+	}
+	if opts.queryDir == "" {
+		return nil
+	}
+	return saveSamples(example, opts)
+}
+
+// saveQuery writes the query to the out path and, with a query directory, the query and its metadata
+func saveQuery(query []byte, platform string, opts *options) error {
+	if opts.outPath != "" {
+		if err := saveFile(opts.outPath, query); err != nil {
+			return err
+		}
+	}
+	if opts.queryDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(opts.queryDir, os.ModePerm); err != nil {
+		return err
+	}
+	if err := saveFile(filepath.Join(opts.queryDir, "query.rego"), query); err != nil {
+		return err
+	}
+	provider := opts.provider
+	if provider == "" && platform != build.PlatformKubernetes && platform != build.PlatformDockerCompose {
+		provider = "common"
+	}
+	metadata, err := writer.Metadata(opts.queryName, opts.severity, opts.category, platform, provider)
+	if err != nil {
+		return err
+	}
+	return saveFile(filepath.Join(opts.queryDir, "metadata.json"), metadata)
+}
+
+// saveSamples writes the positive and negative samples of the example and the expected results
+func saveSamples(example *engine.Example, opts *options) error {
+	ext := filepath.Ext(opts.inPath)
+	testDir := filepath.Join(opts.queryDir, "test")
+	if err := os.MkdirAll(testDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	positive := example.Positive()
+	positiveName := "positive1" + ext
+	if err := saveFile(filepath.Join(testDir, positiveName), positive.Content); err != nil {
+		return err
+	}
+	if err := saveFile(filepath.Join(testDir, "negative1"+ext), example.Negative().Content); err != nil {
+		return err
+	}
+
+	results := make([]expectedResult, 0, len(positive.ExpectedLines))
+	for _, line := range positive.ExpectedLines {
+		results = append(results, expectedResult{
+			QueryName: opts.queryName,
+			Severity:  opts.severity,
+			Line:      line,
+			Filename:  positiveName,
+		})
+	}
+	content, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return saveFile(filepath.Join(testDir, "positive_expected_result.json"), content)
+}
+
 func saveFile(filePath string, content []byte) error {
 	f, err := os.OpenFile(filepath.Clean(filePath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 	if err != nil {
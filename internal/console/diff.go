@@ -0,0 +1,126 @@
+package console
+
+import (
+	_ "embed" // Embed diff flags
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/results"
+	"github.com/spf13/cobra"
+)
+
+var (
+	//go:embed assets/diff-flags.json
+	diffFlagsListContent string
+)
+
+// NewDiffCmd creates a new instance of the diff Command
+func NewDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "diff <old.json> <new.json>",
+		Short:        "Compares the results of two JSON reports",
+		Args:         cobra.ExactArgs(2), //nolint:gomnd
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.Validate(); err != nil {
+				return err
+			}
+			return validateReportFlags(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diff(cmd, args[0], args[1])
+		},
+	}
+}
+
+func initDiffCmd(diffCmd *cobra.Command) error {
+	if err := flags.InitJSONFlags(
+		diffCmd,
+		diffFlagsListContent,
+		false,
+		source.ListSupportedPlatforms(),
+		source.ListSupportedCloudProviders()); err != nil {
+		return err
+	}
+
+	bindReportFlags(diffCmd, "directory path to store the reports of the added, removed and changed results",
+		"diff", "name used on report creations, followed by -added, -removed and -changed")
+	return nil
+}
+
+func diff(cmd *cobra.Command, oldPath, newPath string) error {
+	oldSummary, err := results.Load(oldPath)
+	if err != nil {
+		return err
+	}
+	newSummary, err := results.Load(newPath)
+	if err != nil {
+		return err
+	}
+
+	scanDiff := results.Compare(oldSummary, newSummary)
+	added := scanDiff.AddedSummary()
+	printDiff(scanDiff)
+
+	if err := writeReports(cmd, "-added", &added); err != nil {
+		return err
+	}
+	removed := scanDiff.RemovedSummary()
+	if err := writeReports(cmd, "-removed", &removed); err != nil {
+		return err
+	}
+	changed := scanDiff.ChangedSummary()
+	if err := writeReports(cmd, "-changed", &changed); err != nil {
+		return err
+	}
+
+	failOnNew := flags.GetStrFlag(flags.FailOnNewFlag)
+	if failOnNew == "" {
+		return nil
+	}
+	severities, err := severitiesFrom(failOnNew)
+	if err != nil {
+		return err
+	}
+	if err := consoleHelpers.InitShouldFailArg(severities); err != nil {
+		return err
+	}
+	if exitCode := consoleHelpers.ResultsExitCode(&added); exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// severitiesFrom returns the severity and the higher ones
+func severitiesFrom(severity string) ([]string, error) {
+	severities := make([]string, 0, len(constants.AvailableFailOnNewSeverities))
+	for _, s := range constants.AvailableFailOnNewSeverities {
+		severities = append(severities, s)
+		if strings.EqualFold(s, severity) {
+			return severities, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown argument for --%s: %s", flags.FailOnNewFlag, severity)
+}
+
+func printDiff(scanDiff *results.Diff) {
+	fmt.Printf("Added results: %d\n", len(scanDiff.Added))
+	for _, finding := range scanDiff.Added {
+		fmt.Printf("  + %-6s %s %s:%d\n", finding.Query.Severity, finding.Query.QueryName, finding.File.FileName, finding.File.Line)
+	}
+	fmt.Printf("Removed results: %d\n", len(scanDiff.Removed))
+	for _, finding := range scanDiff.Removed {
+		fmt.Printf("  - %-6s %s %s:%d\n", finding.Query.Severity, finding.Query.QueryName, finding.File.FileName, finding.File.Line)
+	}
+	fmt.Printf("Changed severity results: %d\n", len(scanDiff.Changed))
+	for _, change := range scanDiff.Changed {
+		fmt.Printf("  ~ %s -> %s %s %s:%d\n", change.Old.Query.Severity, change.New.Query.Severity,
+			change.New.Query.QueryName, change.New.File.FileName, change.New.File.Line)
+	}
+	fmt.Printf("Unchanged results: %d\n", scanDiff.Unchanged)
+}
@@ -0,0 +1,93 @@
+package console
+
+import (
+	_ "embed" // Embed export-input flags
+	"fmt"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	sentryReport "github.com/Checkmarx/kics/internal/sentry"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/inputexport"
+	"github.com/Checkmarx/kics/pkg/scan"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	//go:embed assets/export-input-flags.json
+	exportInputFlagsListContent string
+)
+
+// NewExportInputCmd creates a new instance of the export-input Command
+func NewExportInputCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "export-input",
+		Short:        "Writes the rego input the queries of each platform see, with an opa test bundle of the KICS libraries",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return flags.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportInput(cmd)
+		},
+	}
+}
+
+func initExportInputCmd(exportInputCmd *cobra.Command) error {
+	if err := flags.InitJSONFlags(
+		exportInputCmd,
+		exportInputFlagsListContent,
+		false,
+		source.ListSupportedPlatforms(),
+		source.ListSupportedCloudProviders()); err != nil {
+		return err
+	}
+
+	if err := exportInputCmd.MarkFlagRequired(flags.ExportInputPathFlag); err != nil {
+		sentryReport.ReportSentry(&sentryReport.Report{
+			Message:  "Failed to add command required flags",
+			Err:      err,
+			Location: "func initExportInputCmd()",
+		}, true)
+		log.Err(err).Msg("Failed to add command required flags")
+	}
+	return nil
+}
+
+func exportInput(cmd *cobra.Command) error {
+	console := newConsole()
+	console.preScan()
+
+	librariesPath := flags.GetStrFlag(flags.ExportInputLibrariesPathFlag)
+	client, err := scan.NewClient(&scan.Parameters{
+		Path:                        flags.GetMultiStrFlag(flags.ExportInputPathFlag),
+		Platform:                    flags.GetMultiStrFlag(flags.ExportInputTypeFlag),
+		ExcludePlatform:             []string{""},
+		CloudProvider:               []string{""},
+		QueriesPath:                 []string{"./assets/queries"},
+		LibrariesPath:               librariesPath,
+		ChangedDefaultLibrariesPath: cmd.Flags().Changed(flags.ExportInputLibrariesPathFlag),
+		ScanID:                      uuid.New().String(),
+		PreviewLines:                3,
+	}, console.ProBarBuilder, console.Printer)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := client.ExportInputs(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	output := flags.GetStrFlag(flags.ExportInputOutputFlag)
+	if err := inputexport.Write(output, librariesPath, inputs); err != nil {
+		return err
+	}
+
+	for i := range inputs {
+		fmt.Printf("%s (%s): %d documents\n", inputs[i].Platform, inputs[i].Kind, len(inputs[i].Input.Documents))
+	}
+	fmt.Printf("Inputs written to %s, run the bundle tests with: opa test %s/%s\n", output, output, inputexport.BundleDir)
+	return nil
+}
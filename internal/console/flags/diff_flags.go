@@ -0,0 +1,6 @@
+package flags
+
+// Flags constants for diff
+const (
+	FailOnNewFlag = "fail-on-new"
+)
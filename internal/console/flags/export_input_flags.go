@@ -0,0 +1,9 @@
+package flags
+
+// Flags constants for export-input
+const (
+	ExportInputPathFlag          = "export-input-path"
+	ExportInputOutputFlag        = "export-input-output"
+	ExportInputTypeFlag          = "export-input-type"
+	ExportInputLibrariesPathFlag = "export-input-libraries-path"
+)
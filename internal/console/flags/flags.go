@@ -92,22 +92,26 @@ func InitJSONFlags(
 	for flagName, flagProps := range flagsList {
 		flagProps.Usage = evalUsage(flagProps.Usage, supportedPlatforms, supportedCloudProviders)
 
+		// the commands declaring a flag with the same name and type share its value, as a single command runs
 		switch flagProps.FlagType {
 		case "multiStr":
-			var flag []string
-			flagsMultiStrReferences[flagName] = &flag
+			if _, ok := flagsMultiStrReferences[flagName]; !ok {
+				flagsMultiStrReferences[flagName] = &[]string{}
+			}
 			defaultValues := make([]string, 0)
 			if flagProps.DefaultValue != nil {
 				defaultValues = strings.Split(*flagProps.DefaultValue, ",")
 			}
 			flagSet.StringSliceVarP(flagsMultiStrReferences[flagName], flagName, flagProps.ShorthandFlag, defaultValues, flagProps.Usage)
 		case "str":
-			var flag string
-			flagsStrReferences[flagName] = &flag
+			if _, ok := flagsStrReferences[flagName]; !ok {
+				flagsStrReferences[flagName] = new(string)
+			}
 			flagSet.StringVarP(flagsStrReferences[flagName], flagName, flagProps.ShorthandFlag, *flagProps.DefaultValue, flagProps.Usage)
 		case "bool":
-			var flag bool
-			flagsBoolReferences[flagName] = &flag
+			if _, ok := flagsBoolReferences[flagName]; !ok {
+				flagsBoolReferences[flagName] = new(bool)
+			}
 			defaultValue, err := strconv.ParseBool(*flagProps.DefaultValue)
 			if err != nil {
 				log.Err(err).Msg("Loading flags: could not convert default values")
@@ -115,8 +119,9 @@ func InitJSONFlags(
 			}
 			flagSet.BoolVarP(flagsBoolReferences[flagName], flagName, flagProps.ShorthandFlag, defaultValue, flagProps.Usage)
 		case "int":
-			var flag int
-			flagsIntReferences[flagName] = &flag
+			if _, ok := flagsIntReferences[flagName]; !ok {
+				flagsIntReferences[flagName] = new(int)
+			}
 			defaultValue, err := strconv.Atoi(*flagProps.DefaultValue)
 			if err != nil {
 				log.Err(err).Msg("Loading flags: could not convert default values")
@@ -150,6 +150,19 @@ func TestFlags_InitJSONFlags(t *testing.T) {
 	}
 }
 
+func TestFlags_InitJSONFlagsSharedName(t *testing.T) {
+	flagsListContent := `{"shared-output": {"flagType": "str", "shorthandFlag": "", "defaultValue": "", "usage": "output"}}`
+	firstCmd := &cobra.Command{Use: "first"}
+	secondCmd := &cobra.Command{Use: "second"}
+	require.NoError(t, InitJSONFlags(firstCmd, flagsListContent, false, []string{}, []string{}))
+	require.NoError(t, InitJSONFlags(secondCmd, flagsListContent, false, []string{}, []string{}))
+
+	require.NoError(t, firstCmd.Flags().Set("shared-output", "first.json"))
+	require.Equal(t, "first.json", GetStrFlag("shared-output"))
+	require.NoError(t, secondCmd.Flags().Set("shared-output", "second.json"))
+	require.Equal(t, "second.json", GetStrFlag("shared-output"))
+}
+
 func TestFlags_GetStrFlag(t *testing.T) {
 	tests := []struct {
 		name     string
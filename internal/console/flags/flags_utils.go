@@ -2,6 +2,7 @@ package flags
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -77,6 +78,16 @@ func setBoundFlags(flagName string, val interface{}, cmd *cobra.Command) {
 		if err := cmd.Flags().Set(flagName, valStr); err != nil {
 			log.Err(err).Msg("Failed to set Viper flags")
 		}
+	case map[string]interface{}:
+		// maps, like the risk weights, are set as name=value pairs
+		paramSlice := make([]string, 0, len(t))
+		for key, param := range t {
+			paramSlice = append(paramSlice, fmt.Sprintf("%s=%v", key, param))
+		}
+		sort.Strings(paramSlice)
+		if err := cmd.Flags().Set(flagName, strings.Join(paramSlice, ",")); err != nil {
+			log.Err(err).Msg("Failed to set Viper flags")
+		}
 	default:
 		if err := cmd.Flags().Set(flagName, fmt.Sprintf("%v", val)); err != nil {
 			log.Err(err).Msg("Failed to set Viper flags")
@@ -87,6 +87,7 @@ func TestFlags_BindFlags(t *testing.T) {
 	v.AutomaticEnv()
 	v.Set("queries-path", []interface{}{"./assets/queries", "./test"})
 	v.Set("preview-lines", 3)
+	v.Set("risk-weights", map[string]interface{}{"public-exposure": 3, "high": 8.5})
 
 	tests := []struct {
 		name                    string
@@ -116,6 +117,11 @@ func TestFlags_BindFlags(t *testing.T) {
 				"shorthandFlag": "q",
 				"defaultValue": "./assets/queries",
 				"usage": "paths to directory with queries"
+			},"risk-weights": {
+				"flagType": "multiStr",
+				"shorthandFlag": "",
+				"defaultValue": null,
+				"usage": "weights of the risk score of the results"
 			}}`,
 			persistentFlag:          false,
 			supportedPlatforms:      []string{"terraform"},
@@ -130,6 +136,7 @@ func TestFlags_BindFlags(t *testing.T) {
 			got := BindFlags(test.cmd, v)
 			if !test.wantErr {
 				require.NoError(t, got)
+				require.Equal(t, []string{"high=8.5", "public-exposure=3"}, GetMultiStrFlag(RiskWeightsFlag))
 			} else {
 				require.Error(t, got)
 			}
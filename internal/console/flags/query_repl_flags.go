@@ -0,0 +1,8 @@
+package flags
+
+// Flags constants for query-repl
+const (
+	QueryReplPathFlag          = "path"
+	QueryReplPlatformFlag      = "platform"
+	QueryReplLibrariesPathFlag = "repl-libraries-path"
+)
@@ -2,6 +2,10 @@ package flags
 
 // Flags constants for remediate
 const (
-	Results    = "results"
-	IncludeIds = "include-ids"
+	Results       = "results"
+	IncludeIds    = "include-ids"
+	DryRun        = "dry-run"
+	PatchOut      = "patch-out"
+	Interactive   = "interactive"
+	SelectionFile = "selection-file"
 )
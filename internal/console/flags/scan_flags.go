@@ -15,17 +15,25 @@ const (
 	IncludeQueriesFlag      = "include-queries"
 	InputDataFlag           = "input-data"
 	FailOnFlag              = "fail-on"
+	GateFileFlag            = "gate-file"
+	HTMLHistoryFlag         = "html-history"
 	IgnoreOnExitFlag        = "ignore-on-exit"
 	MinimalUIFlag           = "minimal-ui"
 	NoProgressFlag          = "no-progress"
 	OutputNameFlag          = "output-name"
 	OutputPathFlag          = "output-path"
+	OwnerFlag               = "owner"
+	OwnershipFlag           = "ownership"
 	PathFlag                = "path"
 	PayloadPathFlag         = "payload-path"
 	PreviewLinesFlag        = "preview-lines"
 	QueriesPath             = "queries-path"
+	QueryCoverageFlag       = "query-coverage"
 	LibrariesPath           = "libraries-path"
+	MarkdownMaxLengthFlag   = "markdown-max-length"
 	ReportFormatsFlag       = "report-formats"
+	RiskWeightsFlag         = "risk-weights"
+	SarifBaselineFlag       = "sarif-baseline"
 	TypeFlag                = "type"
 	ExcludeTypeFlag         = "exclude-type"
 	TerraformVarsPathFlag   = "terraform-vars-path"
@@ -33,6 +41,9 @@ const (
 	LineInfoPayloadFlag     = "payload-lines"
 	DisableSecretsFlag      = "disable-secrets"
 	SecretsRegexesPathFlag  = "secrets-regexes-path" //nolint:gosec
+	SecretsRulesPacksFlag   = "secrets-rules-packs"  //nolint:gosec
+	SecretsConfidenceFlag   = "secrets-min-confidence"
 	ExcludeGitIgnore        = "exclude-gitignore"
+	WaiversFileFlag         = "waivers-file"
 	GptFlag                = "gpt"
 )
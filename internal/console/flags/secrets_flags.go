@@ -0,0 +1,7 @@
+package flags
+
+// Flags constants for secrets test
+const (
+	BaseRulesPathFlag = "base-rules-path"
+	RulesPacksFlag    = "rules-packs"
+)
@@ -0,0 +1,7 @@
+package flags
+
+// Flags constants for test-queries
+const (
+	JUnitOutputFlag       = "junit-output"
+	TestLibrariesPathFlag = "test-libraries-path"
+)
@@ -7,6 +7,9 @@ type flagValidationFuncsMap map[string]func(flagName string) error
 var flagValidationFuncs = flagValidationFuncsMap{
 	"sliceFlagsShouldNotStartWithFlags": sliceFlagsShouldNotStartWithFlags,
 	"validateMultiStrEnum":              validateMultiStrEnum,
+	"validateReportFormats":             validateReportFormats,
+	"validateFailOn":                    validateFailOn,
+	"validateRiskWeights":               validateRiskWeights,
 	"validateStrEnum":                   validateStrEnum,
 	"allQueriesID":                      allQueriesID,
 }
@@ -7,6 +7,8 @@ import (
 
 	"github.com/Checkmarx/kics/internal/console/helpers"
 	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/report"
+	"github.com/Checkmarx/kics/pkg/risk"
 	"github.com/Checkmarx/kics/pkg/utils"
 )
 
@@ -18,6 +20,7 @@ var validMultiStrEnums = map[string]map[string]string{
 	ReportFormatsFlag:     convertSliceToDummyMap(append([]string{"all"}, helpers.ListReportFormats()...)),
 	TypeFlag:              constants.AvailablePlatforms,
 	ExcludeTypeFlag:       constants.AvailablePlatforms,
+	ExportInputTypeFlag:   constants.AvailablePlatforms,
 }
 
 func sliceFlagsShouldNotStartWithFlags(flagName string) error {
@@ -43,10 +46,52 @@ func allQueriesID(flagName string) error {
 }
 
 func validateMultiStrEnum(flagName string) error {
-	enums := GetMultiStrFlag(flagName)
+	return validateEnums(flagName, GetMultiStrFlag(flagName), validMultiStrEnums[flagName])
+}
+
+// validateFailOn validates the severities of a flag, which can also have a risk score threshold
+func validateFailOn(flagName string) error {
+	severities := make([]string, 0)
+	for _, value := range GetMultiStrFlag(flagName) {
+		_, isScore, err := helpers.ParseRiskScore(value)
+		if err != nil {
+			return fmt.Errorf("invalid argument --%s: %s", flagName, err)
+		}
+		if !isScore {
+			severities = append(severities, value)
+		}
+	}
+	return validateEnums(flagName, severities, validMultiStrEnums[flagName])
+}
+
+func validateRiskWeights(flagName string) error {
+	_, err := risk.ParseWeights(GetMultiStrFlag(flagName))
+	return err
+}
+
+func validateReportFormats(flagName string) error {
+	return ValidateReportFormats(flagName, GetMultiStrFlag(flagName))
+}
+
+// ValidateReportFormats validates the report formats of a flag, the user templates must exist and parse
+func ValidateReportFormats(flagName string, reportFormats []string) error {
+	formats := make([]string, 0)
+	for _, format := range reportFormats {
+		if !report.IsTemplateFormat(format) {
+			formats = append(formats, format)
+			continue
+		}
+		if _, err := report.ParseTemplateFormat(format); err != nil {
+			return fmt.Errorf("invalid argument --%s: %s", flagName, err)
+		}
+	}
+	return validateEnums(flagName, formats, validMultiStrEnums[ReportFormatsFlag])
+}
+
+func validateEnums(flagName string, enums []string, validEnums map[string]string) error {
 	invalidEnum := make([]string, 0)
 	caseInsensitiveMap := make(map[string]string)
-	for key, value := range validMultiStrEnums[flagName] {
+	for key, value := range validEnums {
 		caseInsensitiveMap[strings.ToLower(key)] = value
 	}
 	for _, enum := range enums {
@@ -54,7 +99,7 @@ func validateMultiStrEnum(flagName string) error {
 			invalidEnum = append(invalidEnum, enum)
 		}
 	}
-	validEnumsValues := utils.SortedKeys(validMultiStrEnums[flagName])
+	validEnumsValues := utils.SortedKeys(validEnums)
 	if len(invalidEnum) > 0 {
 		return fmt.Errorf(
 			"unknown argument(s) for --%s: %s\nvalid arguments:\n  %s",
@@ -2,6 +2,8 @@
 package flags
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -91,6 +93,51 @@ func TestFlags_validateMultiStrEnum(t *testing.T) {
 	}
 }
 
+func TestFlags_validateFailOn(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagValue *[]string
+		wantErr   bool
+	}{
+		{
+			name:      "should execute fine with severities and a risk score",
+			flagValue: &[]string{"high", "7.5"},
+			wantErr:   false,
+		},
+		{
+			name:      "should return an error when an invalid severity",
+			flagValue: &[]string{"critical"},
+			wantErr:   true,
+		},
+		{
+			name:      "should return an error when the risk score is not a number",
+			flagValue: &[]string{"NaN"},
+			wantErr:   true,
+		},
+		{
+			name:      "should return an error when the risk score is infinite",
+			flagValue: &[]string{"+Inf"},
+			wantErr:   true,
+		},
+		{
+			name:      "should return an error when the risk score is negative",
+			flagValue: &[]string{"-1"},
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		flagsMultiStrReferences[FailOnFlag] = test.flagValue
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := validateFailOn(FailOnFlag)
+			if !test.wantErr {
+				require.NoError(t, gotErr)
+			} else {
+				require.Error(t, gotErr)
+			}
+		})
+	}
+}
+
 func TestFlags_allQueriesID(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -123,3 +170,42 @@ func TestFlags_allQueriesID(t *testing.T) {
 		})
 	}
 }
+
+func TestFlags_validateReportFormats(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "report.md.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("{{range .Queries}}{{.QueryName}}{{end}}"), os.ModePerm))
+
+	tests := []struct {
+		name      string
+		flagValue *[]string
+		wantErr   bool
+	}{
+		{
+			name:      "should accept report formats and templates",
+			flagValue: &[]string{"json", "template:" + templatePath},
+			wantErr:   false,
+		},
+		{
+			name:      "should return an error when the template does not exist",
+			flagValue: &[]string{"template:" + filepath.Join(dir, "missing.tmpl")},
+			wantErr:   true,
+		},
+		{
+			name:      "should return an error when an invalid report format",
+			flagValue: &[]string{"template:" + templatePath, "xml"},
+			wantErr:   true,
+		},
+	}
+	for _, test := range tests {
+		flagsMultiStrReferences[ReportFormatsFlag] = test.flagValue
+		t.Run(test.name, func(t *testing.T) {
+			gotErr := validateReportFormats(ReportFormatsFlag)
+			if !test.wantErr {
+				require.NoError(t, gotErr)
+			} else {
+				require.Error(t, gotErr)
+			}
+		})
+	}
+}
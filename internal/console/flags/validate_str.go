@@ -5,11 +5,14 @@ import (
 	"strings"
 
 	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/utils"
 )
 
 var validStrEnums = map[string]map[string]string{
-	LogLevelFlag: convertSliceToDummyMap(constants.AvailableLogLevels),
+	LogLevelFlag:          convertSliceToDummyMap(constants.AvailableLogLevels),
+	SecretsConfidenceFlag: convertSliceToDummyMap(model.AllSecretConfidences),
+	FailOnNewFlag:         convertSliceToDummyMap(constants.AvailableFailOnNewSeverities),
 }
 
 func validateStrEnum(flagName string) error {
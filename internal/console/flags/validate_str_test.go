@@ -25,6 +25,18 @@ func TestFlags_validateStrEnum(t *testing.T) {
 			flagValue: "Undefined",
 			wantErr:   true,
 		},
+		{
+			name:      "should accept a severity for fail-on-new",
+			flagName:  "fail-on-new",
+			flagValue: "Medium",
+			wantErr:   false,
+		},
+		{
+			name:      "should return an error when fail-on-new is trace",
+			flagName:  "fail-on-new",
+			flagValue: "trace",
+			wantErr:   true,
+		},
 	}
 	for _, test := range tests {
 		flagsStrReferences[test.flagName] = &test.flagValue
@@ -2,6 +2,8 @@ package helpers
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/Checkmarx/kics/pkg/model"
@@ -9,24 +11,60 @@ import (
 
 var shouldIgnore string
 var shouldFail map[string]struct{}
+var shouldFailScore *float64
 
-// ResultsExitCode calculate exit code base on severity of results, returns 0 if no results was reported
+// ResultsExitCode calculate exit code base on severity of results, returns 0 if no results was reported, the
+// rules of the policy gate replace --fail-on when the scan has one
 func ResultsExitCode(summary *model.Summary) int {
 	// severityArr is needed to make sure 'for' cycle is made in an ordered fashion
 	severityArr := []model.Severity{"HIGH", "MEDIUM", "LOW", "INFO", "TRACE"}
 	codeMap := map[model.Severity]int{"HIGH": 50, "MEDIUM": 40, "LOW": 30, "INFO": 20, "TRACE": 0}
+	if summary.Gate != nil {
+		return gateExitCode(summary.Gate, severityArr, codeMap)
+	}
 	exitMap := summary.SeveritySummary.SeverityCounters
 	for _, severity := range severityArr {
-		if _, reportSeverity := shouldFail[strings.ToLower(string(severity))]; !reportSeverity {
-			continue
+		if _, reportSeverity := shouldFail[strings.ToLower(string(severity))]; reportSeverity && exitMap[severity] > 0 {
+			return codeMap[severity]
 		}
-		if exitMap[severity] > 0 {
+		if reachesFailScore(summary, severity) {
 			return codeMap[severity]
 		}
 	}
 	return 0
 }
 
+// gateExitCode returns the exit code of the highest severity among the results of the violated fail rules, a failed
+// gate whose results have no severity with an exit code returns the one of high severity
+func gateExitCode(gate *model.GateResult, severityArr []model.Severity, codeMap map[model.Severity]int) int {
+	if gate.Passed {
+		return 0
+	}
+	for _, severity := range severityArr {
+		for i := range gate.Rules {
+			rule := &gate.Rules[i]
+			if rule.Violated && rule.Action == model.GateActionFail && rule.HighestSeverity == severity &&
+				codeMap[severity] != 0 {
+				return codeMap[severity]
+			}
+		}
+	}
+	return codeMap[model.SeverityHigh]
+}
+
+// reachesFailScore tells if a result of the severity has a risk score reaching the --fail-on threshold
+func reachesFailScore(summary *model.Summary, severity model.Severity) bool {
+	if shouldFailScore == nil {
+		return false
+	}
+	for i := range summary.Queries {
+		if summary.Queries[i].Severity == severity && summary.Queries[i].RiskScore >= *shouldFailScore {
+			return true
+		}
+	}
+	return false
+}
+
 // InitShouldIgnoreArg initializes what kind of errors should be used on exit codes
 func InitShouldIgnoreArg(arg string) error {
 	validArgs := []string{"none", "all", "results", "errors"}
@@ -39,8 +77,10 @@ func InitShouldIgnoreArg(arg string) error {
 	return fmt.Errorf("unknown argument for --ignore-on-exit: %s\nvalid arguments:\n  %s", arg, strings.Join(validArgs, "\n  "))
 }
 
-// InitShouldFailArg initializes which kind of vulnerability severity should changes exit code
+// InitShouldFailArg initializes which kind of vulnerability severity should changes exit code, a number being
+// a risk score threshold
 func InitShouldFailArg(args []string) error {
+	shouldFailScore = nil
 	possibleArgs := map[string]struct{}{
 		"high":   {},
 		"medium": {},
@@ -54,8 +94,18 @@ func InitShouldFailArg(args []string) error {
 
 	argsConverted := make(map[string]struct{})
 	for _, arg := range args {
+		score, isScore, err := ParseRiskScore(arg)
+		if err != nil {
+			return fmt.Errorf("invalid argument for --fail-on: %w", err)
+		}
+		if isScore {
+			if shouldFailScore == nil || score < *shouldFailScore {
+				shouldFailScore = &score
+			}
+			continue
+		}
 		if _, ok := possibleArgs[strings.ToLower(arg)]; !ok {
-			validArgs := []string{"high", "medium", "low", "info"}
+			validArgs := []string{"high", "medium", "low", "info", "<risk score>"}
 			return fmt.Errorf("unknown argument for --fail-on: %s\nvalid arguments:\n  %s", arg, strings.Join(validArgs, "\n  "))
 		}
 		argsConverted[strings.ToLower(arg)] = struct{}{}
@@ -80,3 +130,16 @@ func RemediateExitCode(selectedRemediationNumber, actualRemediationDoneNumber in
 
 	return 0
 }
+
+// ParseRiskScore returns the risk score threshold of a --fail-on argument, isScore is false for the arguments that
+// are not numbers, such as severities, and numbers that are not finite and positive are an error
+func ParseRiskScore(arg string) (score float64, isScore bool, err error) {
+	score, err = strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	if math.IsNaN(score) || math.IsInf(score, 0) || score < 0 {
+		return 0, true, fmt.Errorf("risk score %s is not a finite positive number", arg)
+	}
+	return score, true, nil
+}
@@ -72,6 +72,68 @@ func TestExitHandler_ResultsExitCode(t *testing.T) {
 	}
 }
 
+func TestExitHandler_ResultsExitCodeRiskScore(t *testing.T) {
+	defer func() { shouldFailScore = nil }()
+	summary := model.Summary{
+		Queries: model.QueryResultSlice{
+			{Severity: model.SeverityHigh, RiskScore: 7},
+			{Severity: model.SeverityMedium, RiskScore: 8.5},
+		},
+		SeveritySummary: model.SeveritySummary{
+			SeverityCounters: map[model.Severity]int{model.SeverityHigh: 1, model.SeverityMedium: 1},
+		},
+	}
+
+	require.NoError(t, InitShouldFailArg([]string{"8"}))
+	require.Equal(t, 40, ResultsExitCode(&summary))
+
+	require.NoError(t, InitShouldFailArg([]string{"9", "medium"}))
+	require.Equal(t, 40, ResultsExitCode(&summary))
+
+	require.NoError(t, InitShouldFailArg([]string{"6.5", "9"}))
+	require.Equal(t, 50, ResultsExitCode(&summary))
+
+	require.NoError(t, InitShouldFailArg([]string{"9"}))
+	require.Equal(t, 0, ResultsExitCode(&summary))
+}
+
+func TestExitHandler_ResultsExitCodeGate(t *testing.T) {
+	require.NoError(t, InitShouldFailArg([]string{"high"}))
+	summary := model.Summary{
+		SeveritySummary: model.SeveritySummary{
+			SeverityCounters: map[model.Severity]int{model.SeverityHigh: 1, model.SeverityMedium: 6},
+		},
+		Gate: &model.GateResult{
+			Passed: false,
+			Rules: []model.GateRuleResult{
+				{
+					GateRule:        model.GateRule{Name: "high in prod", Action: model.GateActionWarn},
+					Count:           1,
+					Violated:        true,
+					HighestSeverity: model.SeverityHigh,
+				},
+				{
+					GateRule:        model.GateRule{Name: "medium secrets", Action: model.GateActionFail, MaxCount: 5},
+					Count:           6,
+					Violated:        true,
+					HighestSeverity: model.SeverityMedium,
+				},
+			},
+		},
+	}
+	require.Equal(t, 40, ResultsExitCode(&summary))
+
+	summary.Gate.Rules[1].HighestSeverity = model.SeverityTrace
+	require.Equal(t, 50, ResultsExitCode(&summary))
+
+	summary.Gate.Rules[1].HighestSeverity = ""
+	require.Equal(t, 50, ResultsExitCode(&summary))
+
+	summary.Gate.Rules[1].Violated = false
+	summary.Gate.Passed = true
+	require.Equal(t, 0, ResultsExitCode(&summary))
+}
+
 type initIgnoreResult struct {
 	wantErr bool
 	want    string
@@ -196,6 +258,27 @@ var initShouldFailTests = []struct {
 			want:    map[string]struct{}{},
 		},
 	},
+	{
+		caseTest: []string{"high", "NaN"},
+		expectedResult: initFail{
+			wantErr: true,
+			want:    map[string]struct{}{},
+		},
+	},
+	{
+		caseTest: []string{"high", "Inf"},
+		expectedResult: initFail{
+			wantErr: true,
+			want:    map[string]struct{}{},
+		},
+	},
+	{
+		caseTest: []string{"high", "-1"},
+		expectedResult: initFail{
+			wantErr: true,
+			want:    map[string]struct{}{},
+		},
+	},
 }
 
 func TestExitHandler_InitShouldFailArg(t *testing.T) {
@@ -37,6 +37,10 @@ var reportGenerators = map[string]func(path, filename string, body interface{})
 	"asff":        report.PrintASFFReport,
 	"csv":         report.PrintCSVReport,
 	"codeclimate": report.PrintCodeClimateReport,
+	"markdown":    report.PrintMarkdownReport,
+
+	"inventory-cyclonedx": report.PrintInventoryCycloneDxReport,
+	"inventory-csv":       report.PrintInventoryCSVReport,
 }
 
 // CustomConsoleWriter creates an output to print log in a files
@@ -98,7 +102,8 @@ func FileAnalyzer(path string) (string, error) {
 }
 
 // GenerateReport execute each report function to generate report
-func GenerateReport(path, filename string, body interface{}, formats []string, proBarBuilder progress.PbBuilder) error {
+func GenerateReport(path, filename string, body interface{}, formats []string, options *report.Options,
+	proBarBuilder progress.PbBuilder) error {
 	log.Debug().Msgf("helpers.GenerateReport()")
 	metrics.Metric.Start("generate_report")
 
@@ -109,8 +114,30 @@ func GenerateReport(path, filename string, body interface{}, formats []string, p
 	defer progressBar.Close()
 
 	for _, format := range formats {
+		if report.IsTemplateFormat(format) {
+			if err = report.PrintTemplateReport(path, filename, format, body); err != nil {
+				log.Error().Msgf("Failed to generate %s report", format)
+				break
+			}
+			continue
+		}
 		format = strings.ToLower(format)
-		if err = reportGenerators[format](path, filename, body); err != nil {
+		generator := reportGenerators[format]
+		switch format {
+		case "markdown":
+			generator = func(path, filename string, body interface{}) error {
+				return report.PrintMarkdownReportWithOptions(path, filename, body, options)
+			}
+		case "sarif":
+			generator = func(path, filename string, body interface{}) error {
+				return report.PrintSarifReportWithOptions(path, filename, body, options)
+			}
+		case "html":
+			generator = func(path, filename string, body interface{}) error {
+				return report.PrintHTMLReportWithOptions(path, filename, body, options)
+			}
+		}
+		if err = generator(path, filename, body); err != nil {
 			log.Error().Msgf("Failed to generate %s report", format)
 			break
 		}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/Checkmarx/kics/pkg/progress"
+	"github.com/Checkmarx/kics/pkg/report"
 	"github.com/Checkmarx/kics/test"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -172,7 +173,8 @@ func TestHelpers_GenerateReport(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := GenerateReport(tt.args.path, tt.args.filename, tt.args.body, tt.args.formats, progress.PbBuilder{})
+			err := GenerateReport(tt.args.path, tt.args.filename, tt.args.body, tt.args.formats, &report.Options{},
+				progress.PbBuilder{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenerateReport() = %v, wantErr = %v", err, tt.wantErr)
 			}
@@ -45,6 +45,12 @@ func initialize(rootCmd *cobra.Command) error {
 	remediateCmd := NewRemediateCmd()
 	gptCmd := NewGptCmd()
 	analyzeCmd := NewAnalyzeCmd()
+	secretsCmd := NewSecretsCmd()
+	testQueriesCmd := NewTestQueriesCmd()
+	queryReplCmd := NewQueryReplCmd()
+	exportInputCmd := NewExportInputCmd()
+	diffCmd := NewDiffCmd()
+	mergeCmd := NewMergeCmd()
 	rootCmd.AddCommand(NewVersionCmd())
 	rootCmd.AddCommand(NewGenerateIDCmd())
 	rootCmd.AddCommand(scanCmd)
@@ -52,6 +58,12 @@ func initialize(rootCmd *cobra.Command) error {
 	rootCmd.AddCommand(NewListPlatformsCmd())
 	rootCmd.AddCommand(remediateCmd)
 	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(testQueriesCmd)
+	rootCmd.AddCommand(queryReplCmd)
+	rootCmd.AddCommand(exportInputCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(mergeCmd)
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	if err := flags.InitJSONFlags(
@@ -77,6 +89,27 @@ func initialize(rootCmd *cobra.Command) error {
 	if err := initAnalyzeCmd(analyzeCmd); err != nil {
 		return err
 	}
+	if err := initSecretsCmd(secretsCmd); err != nil {
+		return err
+	}
+	if err := initTestQueriesCmd(testQueriesCmd); err != nil {
+		return err
+	}
+	if err := initQueryReplCmd(queryReplCmd); err != nil {
+		return err
+	}
+
+	if err := initExportInputCmd(exportInputCmd); err != nil {
+		return err
+	}
+
+	if err := initDiffCmd(diffCmd); err != nil {
+		return err
+	}
+
+	if err := initMergeCmd(mergeCmd); err != nil {
+		return err
+	}
 
 	return initScanCmd(scanCmd)
 }
@@ -0,0 +1,69 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	sentryReport "github.com/Checkmarx/kics/internal/sentry"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/results"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewMergeCmd creates a new instance of the merge Command
+func NewMergeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "merge <results.json>...",
+		Short:        "Merges the results of JSON reports into one report",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.Validate(); err != nil {
+				return err
+			}
+			return validateReportFlags(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return merge(cmd, args)
+		},
+	}
+}
+
+func initMergeCmd(mergeCmd *cobra.Command) error {
+	bindReportFlags(mergeCmd, "directory path to store the merged reports", "merged", "name used on report creations")
+
+	if err := mergeCmd.MarkFlagRequired(flags.OutputPathFlag); err != nil {
+		sentryReport.ReportSentry(&sentryReport.Report{
+			Message:  "Failed to add command required flags",
+			Err:      err,
+			Location: "func initMergeCmd()",
+		}, true)
+		log.Err(err).Msg("Failed to add command required flags")
+	}
+	return nil
+}
+
+func merge(cmd *cobra.Command, paths []string) error {
+	summaries := make([]*model.Summary, 0, len(paths))
+	for _, path := range paths {
+		summary, err := results.Load(path)
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	merged := results.Merge(summaries)
+
+	counters := make([]string, 0, len(model.AllSeverities))
+	for _, severity := range model.AllSeverities {
+		if severity != model.SeverityTrace {
+			counters = append(counters, fmt.Sprintf("%s: %d", severity, merged.SeverityCounters[severity]))
+		}
+	}
+	fmt.Printf("Merged %d reports: %d results (%s)\n", len(paths), merged.TotalCounter, strings.Join(counters, ", "))
+
+	return writeReports(cmd, "", &merged)
+}
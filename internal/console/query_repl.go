@@ -0,0 +1,200 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	_ "embed" // Embed query-repl flags
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	sentryReport "github.com/Checkmarx/kics/internal/sentry"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/queryrepl"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	//go:embed assets/query-repl-flags.json
+	queryReplFlagsListContent string
+)
+
+const (
+	queryReplTimeout = 60
+	queryReplPrompt  = "> "
+	queryReplHelp    = `Rego expressions are evaluated with input bound to the documents of the file
+and the libraries available as data.generic.common and data.generic.<platform>.
+Rules and assignments to variables are defined in the repl package, replacing the
+rules with the same name.
+
+Commands:
+  :run <query dir>   run the queries of the directory and show their results
+  :input             show the documents bound to input
+  :lineinfo          toggle input between the scanned documents and the documents with lines information
+  :help              show this help
+  :exit              leave the repl`
+)
+
+// NewQueryReplCmd creates a new instance of the query-repl Command
+func NewQueryReplCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "query-repl",
+		Short:        "Evaluates rego against a file parsed as the scan does, to debug queries",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return flags.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queryRepl(cmd)
+		},
+	}
+}
+
+func initQueryReplCmd(queryReplCmd *cobra.Command) error {
+	if err := flags.InitJSONFlags(
+		queryReplCmd,
+		queryReplFlagsListContent,
+		false,
+		source.ListSupportedPlatforms(),
+		source.ListSupportedCloudProviders()); err != nil {
+		return err
+	}
+
+	for _, flag := range []string{flags.QueryReplPathFlag, flags.QueryReplPlatformFlag} {
+		if err := queryReplCmd.MarkFlagRequired(flag); err != nil {
+			sentryReport.ReportSentry(&sentryReport.Report{
+				Message:  "Failed to add command required flags",
+				Err:      err,
+				Location: "func initQueryReplCmd()",
+			}, true)
+			log.Err(err).Msg("Failed to add command required flags")
+		}
+	}
+	return nil
+}
+
+func queryRepl(cmd *cobra.Command) error {
+	session, err := queryrepl.NewSession(
+		flags.GetStrFlag(flags.QueryReplPathFlag),
+		flags.GetStrFlag(flags.QueryReplPlatformFlag),
+		flags.GetStrFlag(flags.QueryReplLibrariesPathFlag),
+		queryReplTimeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s parsed as %s, type :help for the commands\n", session.Path, session.Platform)
+	return runQueryRepl(cmd.Context(), session, os.Stdin, os.Stdout)
+}
+
+// runQueryRepl reads statements and commands until :exit or the end of the input
+func runQueryRepl(ctx context.Context, session *queryrepl.Session, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+
+	for {
+		fmt.Fprint(out, queryReplPrompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		command, argument, _ := strings.Cut(line, " ")
+		switch command {
+		case "":
+		case ":exit", ":quit":
+			return nil
+		case ":help":
+			fmt.Fprintln(out, queryReplHelp)
+		case ":input":
+			printReplValue(out, session.Input())
+		case ":lineinfo":
+			session.LineInfo = !session.LineInfo
+			fmt.Fprintf(out, "lines information in input: %t\n", session.LineInfo)
+		case ":run":
+			runReplQueries(ctx, session, strings.TrimSpace(argument), out)
+		default:
+			evalReplStatement(ctx, session, line, out)
+		}
+	}
+}
+
+func evalReplStatement(ctx context.Context, session *queryrepl.Session, statement string, out io.Writer) {
+	defined, ok, err := session.Define(statement)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return
+	}
+	if ok {
+		fmt.Fprintf(out, "defined %s in package %s\n", strings.Join(defined, ", "), queryrepl.Package)
+		return
+	}
+
+	results, err := session.Eval(ctx, statement)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return
+	}
+	printReplResults(out, results)
+}
+
+// printReplResults prints the bindings of the variables of each result, or the values of the expressions
+// when the statement binds no variables
+func printReplResults(out io.Writer, results rego.ResultSet) {
+	if len(results) == 0 {
+		fmt.Fprintln(out, "undefined")
+		return
+	}
+
+	for i := range results {
+		if len(results[i].Bindings) > 0 {
+			printReplValue(out, results[i].Bindings)
+			continue
+		}
+		for _, expression := range results[i].Expressions {
+			printReplValue(out, expression.Value)
+		}
+	}
+}
+
+func printReplValue(out io.Writer, value interface{}) {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return
+	}
+	fmt.Fprintln(out, string(content))
+}
+
+func runReplQueries(ctx context.Context, session *queryrepl.Session, queryDir string, out io.Writer) {
+	if queryDir == "" {
+		fmt.Fprintln(out, "usage: :run <query dir>")
+		return
+	}
+
+	vulnerabilities, err := session.Run(ctx, queryDir)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return
+	}
+
+	fmt.Fprintf(out, "%d results\n", len(vulnerabilities))
+	for i := range vulnerabilities {
+		printReplVulnerability(out, &vulnerabilities[i])
+	}
+}
+
+func printReplVulnerability(out io.Writer, vulnerability *model.Vulnerability) {
+	fmt.Fprintf(out, "[%s] %s\n", vulnerability.Severity, vulnerability.QueryName)
+	fmt.Fprintf(out, "\t%s:%d %s\n", filepath.Base(vulnerability.FileName), vulnerability.Line, vulnerability.SearchKey)
+	fmt.Fprintf(out, "\texpected: %s\n", vulnerability.KeyExpectedValue)
+	fmt.Fprintf(out, "\tactual: %s\n", vulnerability.KeyActualValue)
+}
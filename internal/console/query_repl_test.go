@@ -0,0 +1,43 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/queryrepl"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunQueryRepl(t *testing.T) {
+	queryDir := filepath.FromSlash("../../assets/queries/terraform/aws/s3_bucket_acl_allows_read_or_write_to_all_users")
+	session, err := queryrepl.NewSession(filepath.Join(queryDir, "test", "positive1.tf"), "terraform", "./assets/libraries", 60)
+	require.NoError(t, err)
+
+	in := strings.Join([]string{
+		`acl := input.document[0].resource.aws_s3_bucket.positive1.acl`,
+		`acl`,
+		`input.document[0].missing`,
+		`:run ` + queryDir,
+		`:run`,
+		`:exit`,
+		`acl`,
+	}, "\n")
+	var out bytes.Buffer
+	require.NoError(t, runQueryRepl(context.Background(), session, strings.NewReader(in), &out))
+
+	require.Equal(t, strings.Join([]string{
+		`> defined acl in package repl`,
+		`> "public-read"`,
+		`> undefined`,
+		`> 1 results`,
+		`[HIGH] S3 Bucket ACL Allows Read Or Write to All Users`,
+		"\tpositive1.tf:15 aws_s3_bucket[positive1].acl=public-read",
+		"\texpected: 'acl' should equal to 'private'",
+		"\tactual: 'acl' is equal 'public-read'",
+		`> usage: :run <query dir>`,
+		`> `,
+	}, "\n"), out.String())
+}
@@ -78,6 +78,7 @@ func preRemediate(cmd *cobra.Command) error {
 func remediate() error {
 	resultsPath := flags.GetStrFlag(flags.Results)
 	include := flags.GetMultiStrFlag(flags.IncludeIds)
+	patchOut := flags.GetStrFlag(flags.PatchOut)
 
 	filepath.Clean(resultsPath)
 
@@ -95,9 +96,20 @@ func remediate() error {
 		return err
 	}
 
+	include, err = selectedRemediations(&results, resultsPath, include)
+	if err != nil {
+		return err
+	}
+	if len(include) == 0 {
+		fmt.Println("No remediation selected")
+		return nil
+	}
+
 	summary := &remediation.Summary{
 		SelectedRemediationNumber:   0,
 		ActualRemediationDoneNumber: 0,
+		DryRun:                      flags.GetBoolFlag(flags.DryRun),
+		ScannedPaths:                results.ScannedPaths,
 	}
 
 	// get all the remediationSets related to each filePath
@@ -111,8 +123,19 @@ func remediate() error {
 		}
 	}
 
-	fmt.Printf("\nSelected remediation: %d\n", summary.SelectedRemediationNumber)
-	fmt.Printf("Remediation done: %d\n", summary.ActualRemediationDoneNumber)
+	if summary.DryRun {
+		fmt.Print(summary.Patch(true))
+	}
+
+	if patchOut != "" {
+		if err = os.WriteFile(filepath.Clean(patchOut), []byte(summary.Patch(false)), os.ModePerm); err != nil {
+			log.Error().Msgf("failed to write patch: %s", err)
+			return err
+		}
+		log.Info().Msgf("patch written to '%s'", patchOut)
+	}
+
+	printRemediationSummary(summary)
 
 	exitCode := consoleHelpers.RemediateExitCode(summary.SelectedRemediationNumber, summary.ActualRemediationDoneNumber)
 	if exitCode != 0 {
@@ -121,3 +144,58 @@ func remediate() error {
 
 	return nil
 }
+
+// selectedRemediations returns the similarity ids to remediate, chosen in the interactive mode or read from the
+// selection file, the remediations edited in the selection replace the ones of the results
+func selectedRemediations(results *remediation.Report, resultsPath string, include []string) ([]string, error) {
+	selectionFile := flags.GetStrFlag(flags.SelectionFile)
+	interactive := flags.GetBoolFlag(flags.Interactive)
+	if interactive && !isTerminal(os.Stdout) {
+		log.Warn().Msg("stdout is not a terminal, remediating without prompts")
+		interactive = false
+	}
+
+	var selection *remediation.Selection
+	var err error
+
+	switch {
+	case interactive:
+		selection, err = selectRemediations(results, include, newInteractivePrompt())
+		if err != nil {
+			return nil, err
+		}
+		selection.Results = resultsPath
+		if selectionFile != "" {
+			if err = selection.Save(selectionFile); err != nil {
+				log.Error().Msgf("failed to write selection file: %s", err)
+				return nil, err
+			}
+			log.Info().Msgf("selection written to '%s'", selectionFile)
+		}
+	case selectionFile != "":
+		selection, err = remediation.LoadSelection(selectionFile)
+		if err != nil {
+			log.Error().Msgf("failed to read selection file: %s", err)
+			return nil, err
+		}
+	default:
+		return include, nil
+	}
+
+	selection.Apply(results)
+	return selection.IDs(), nil
+}
+
+// printRemediationSummary prints the number of remediations per file and the total
+func printRemediationSummary(summary *remediation.Summary) {
+	action := "Remediations done"
+	if summary.DryRun {
+		action = "Remediations to apply"
+	}
+
+	fmt.Println()
+	for _, patch := range summary.FilePatches() {
+		fmt.Printf("%s: %d\n", patch.FilePath, len(patch.Remediations))
+	}
+	fmt.Printf("%s: %d of %d selected\n", action, summary.ActualRemediationDoneNumber, summary.SelectedRemediationNumber)
+}
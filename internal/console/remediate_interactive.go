@@ -0,0 +1,228 @@
+package console
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/detector"
+	"github.com/Checkmarx/kics/pkg/model"
+	internalPrinter "github.com/Checkmarx/kics/pkg/printer"
+	"github.com/Checkmarx/kics/pkg/remediation"
+	"github.com/Checkmarx/kics/pkg/utils"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	interactiveContextLines = 5
+	defaultEditor           = "vi"
+)
+
+// interactivePrompt walks the results with remediation, asking which ones should be applied
+// preview returns the diff of a remediation and edit lets the user change a text
+type interactivePrompt struct {
+	in      *bufio.Reader
+	out     io.Writer
+	printer *internalPrinter.Printer
+	preview func(vuln *model.Vulnerability) string
+	edit    func(text string) (string, error)
+}
+
+func newInteractivePrompt() *interactivePrompt {
+	return &interactivePrompt{
+		in:      bufio.NewReader(os.Stdin),
+		out:     os.Stdout,
+		printer: internalPrinter.NewPrinter(false),
+		preview: func(vuln *model.Vulnerability) string {
+			return remediation.Preview(vuln, true)
+		},
+		edit: editInEditor,
+	}
+}
+
+// isTerminal returns true when the file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// interactiveFinding is a result with remediation along with its query
+type interactiveFinding struct {
+	query *remediation.Query
+	file  remediation.File
+}
+
+// selectRemediations prompts for each result with remediation included, the selection holds the accepted ones
+func selectRemediations(report *remediation.Report, include []string, p *interactivePrompt) (*remediation.Selection, error) {
+	findings := make([]interactiveFinding, 0)
+	for i := range report.Queries {
+		for _, file := range report.Queries[i].Files {
+			if file.Remediation == "" || file.RemediationType == "" ||
+				(include[0] != "all" && !utils.Contains(file.SimilarityID, include)) {
+				continue
+			}
+			findings = append(findings, interactiveFinding{query: &report.Queries[i], file: file})
+		}
+	}
+
+	selection := &remediation.Selection{}
+	acceptAll := make(map[string]bool)
+
+	for idx := range findings {
+		finding := &findings[idx]
+		if acceptAll[finding.query.QueryID] {
+			selection.Add(finding.query, &finding.file, "")
+			continue
+		}
+
+		answer, edited, err := p.ask(finding, idx+1, len(findings))
+		if err != nil {
+			return selection, err
+		}
+
+		switch answer {
+		case "a":
+			selection.Add(finding.query, &finding.file, edited)
+		case "A":
+			selection.Add(finding.query, &finding.file, edited)
+			acceptAll[finding.query.QueryID] = true
+		case "q":
+			return selection, nil
+		}
+	}
+
+	return selection, nil
+}
+
+// ask shows the finding and its proposed diff until it is accepted, skipped or the prompt is quit
+// edited is the remediation changed by the user or an empty string
+func (p *interactivePrompt) ask(finding *interactiveFinding, position, total int) (answer, edited string, err error) {
+	file := finding.file
+	p.showFinding(finding, position, total)
+
+	for {
+		vuln := finding.query.Vulnerability(&file)
+		if diff := p.preview(&vuln); diff != "" {
+			fmt.Fprintf(p.out, "%s\n\n", diff)
+		} else {
+			fmt.Fprintf(p.out, "The remediation can not be applied or does not remove the result\n\n")
+		}
+
+		fmt.Fprint(p.out, "Apply this remediation? [a]ccept, [s]kip, [e]dit, accept [A]ll for this query, [q]uit: ")
+		line, readErr := p.in.ReadString('\n')
+		answer = strings.TrimSpace(line)
+		if readErr != nil && answer == "" {
+			if errors.Is(readErr, io.EOF) {
+				fmt.Fprintln(p.out)
+				return "q", edited, nil
+			}
+			return "", edited, readErr
+		}
+
+		switch answer {
+		case "a", "A", "s", "q":
+			return answer, edited, nil
+		case "e":
+			changed, err := editRemediation(&file, p.edit)
+			if err != nil {
+				fmt.Fprintf(p.out, "failed to edit the remediation: %s\n", err)
+				continue
+			}
+			file.Remediation = changed
+			edited = changed
+		default:
+			fmt.Fprintf(p.out, "unknown option '%s'\n", answer)
+		}
+	}
+}
+
+// showFinding prints the query, severity and the code of the result
+func (p *interactivePrompt) showFinding(finding *interactiveFinding, position, total int) {
+	header := fmt.Sprintf("[%d/%d] %s", position, total, finding.query.QueryName)
+	fmt.Fprintf(p.out, "\n%s\n", p.printer.Bold(header))
+	fmt.Fprintf(p.out, "%s %s:%d\n", p.printer.PrintBySev(strings.ToUpper(finding.query.Severity), finding.query.Severity),
+		finding.file.FilePath, finding.file.Line)
+	fmt.Fprintf(p.out, "Similarity ID: %s\n\n", finding.file.SimilarityID)
+
+	content, err := os.ReadFile(filepath.Clean(finding.file.FilePath))
+	if err != nil || finding.file.Line < 1 {
+		return
+	}
+	lines := strings.Split(string(content), "\n")
+	if finding.file.Line > len(lines) {
+		return
+	}
+
+	for _, line := range *detector.GetAdjacentVulnLines(finding.file.Line-1, interactiveContextLines, lines) {
+		if line.Position == finding.file.Line {
+			fmt.Fprintf(p.out, "\t%s\n", p.printer.Line.Sprintf("%03d: %s", line.Position, line.Line))
+		} else {
+			fmt.Fprintf(p.out, "\t%03d: %s\n", line.Position, line.Line)
+		}
+	}
+	fmt.Fprintln(p.out)
+}
+
+// editRemediation lets the user edit the remediation, only the value after the change for replacements
+func editRemediation(file *remediation.File, edit func(text string) (string, error)) (string, error) {
+	if file.RemediationType != remediation.ReplacementType {
+		return edit(file.Remediation)
+	}
+
+	var info remediation.ReplacementInfo
+	if err := json.Unmarshal([]byte(file.Remediation), &info); err != nil {
+		return "", err
+	}
+	after, err := edit(info.After)
+	if err != nil {
+		return "", err
+	}
+	info.After = after
+
+	changed, err := json.Marshal(info)
+	return string(changed), err
+}
+
+// editInEditor opens the text in the editor of the VISUAL or EDITOR environment variables
+func editInEditor(text string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "kics-remediation-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err = tmpFile.WriteString(text + "\n"); err != nil {
+		return "", err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	args := append(strings.Fields(editor), tmpFile.Name())
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\n"), nil
+}
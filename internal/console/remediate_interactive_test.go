@@ -0,0 +1,129 @@
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	internalPrinter "github.com/Checkmarx/kics/pkg/printer"
+	"github.com/Checkmarx/kics/pkg/remediation"
+	"github.com/stretchr/testify/require"
+)
+
+func interactiveReport() *remediation.Report {
+	return &remediation.Report{
+		Queries: []remediation.Query{
+			{
+				QueryID:   "query-1",
+				QueryName: "Query One",
+				Severity:  "HIGH",
+				Files: []remediation.File{
+					{FilePath: "a.tf", Line: 1, SimilarityID: "id-1", Remediation: "{\"before\":\"false\",\"after\":\"true\"}", RemediationType: "replacement"},
+					{FilePath: "b.tf", Line: 2, SimilarityID: "id-2", Remediation: "{\"before\":\"false\",\"after\":\"true\"}", RemediationType: "replacement"},
+					{FilePath: "c.tf", Line: 3, SimilarityID: "id-3"},
+				},
+			},
+			{
+				QueryID:   "query-2",
+				QueryName: "Query Two",
+				Severity:  "LOW",
+				Files: []remediation.File{
+					{FilePath: "a.tf", Line: 4, SimilarityID: "id-4", Remediation: "enabled = true", RemediationType: "addition"},
+					{FilePath: "b.tf", Line: 5, SimilarityID: "id-5", Remediation: "enabled = true", RemediationType: "addition"},
+				},
+			},
+		},
+	}
+}
+
+func testPrompt(input string, previews *[]string) (*interactivePrompt, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &interactivePrompt{
+		in:      bufio.NewReader(strings.NewReader(input)),
+		out:     out,
+		printer: internalPrinter.NewPrinter(false),
+		preview: func(vuln *model.Vulnerability) string {
+			*previews = append(*previews, vuln.Remediation)
+			return "diff " + vuln.SimilarityID
+		},
+		edit: func(text string) (string, error) {
+			return "edited " + text, nil
+		},
+	}, out
+}
+
+func Test_SelectRemediations(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		include  []string
+		wantIDs  []string
+		wantEdit map[string]string
+	}{
+		{
+			name:    "accept and skip",
+			input:   "a\ns\ns\na\n",
+			include: []string{"all"},
+			wantIDs: []string{"id-1", "id-5"},
+		},
+		{
+			name:    "accept all for query",
+			input:   "A\ns\nx\na\n",
+			include: []string{"all"},
+			wantIDs: []string{"id-1", "id-2", "id-5"},
+		},
+		{
+			name:    "quit keeps the accepted remediations",
+			input:   "a\nq\n",
+			include: []string{"all"},
+			wantIDs: []string{"id-1"},
+		},
+		{
+			name:    "end of input quits",
+			input:   "a\n",
+			include: []string{"all"},
+			wantIDs: []string{"id-1"},
+		},
+		{
+			name:     "edit the remediation",
+			input:    "e\na\n",
+			include:  []string{"id-4"},
+			wantIDs:  []string{"id-4"},
+			wantEdit: map[string]string{"id-4": "edited enabled = true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previews := make([]string, 0)
+			prompt, out := testPrompt(tt.input, &previews)
+
+			selection, err := selectRemediations(interactiveReport(), tt.include, prompt)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantIDs, selection.IDs())
+			require.Contains(t, out.String(), "diff id-")
+
+			for _, selected := range selection.Remediations {
+				require.Equal(t, tt.wantEdit[selected.SimilarityID], selected.Remediation)
+			}
+		})
+	}
+}
+
+func Test_EditRemediation(t *testing.T) {
+	edit := func(text string) (string, error) {
+		return strings.ToUpper(text), nil
+	}
+
+	replacement := &remediation.File{Remediation: "{\"before\":\"false\",\"after\":\"true\"}", RemediationType: "replacement"}
+	edited, err := editRemediation(replacement, edit)
+	require.NoError(t, err)
+	require.JSONEq(t, "{\"before\":\"false\",\"after\":\"TRUE\"}", edited)
+
+	addition := &remediation.File{Remediation: "enabled = true", RemediationType: "addition"}
+	edited, err = editRemediation(addition, edit)
+	require.NoError(t, err)
+	require.Equal(t, "ENABLED = TRUE", edited)
+}
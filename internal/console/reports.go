@@ -0,0 +1,46 @@
+package console
+
+import (
+	"os"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/progress"
+	"github.com/Checkmarx/kics/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+// bindReportFlags binds the report flags of the commands reading JSON reports, the JSON flags are global
+// by name, so the flags shared with scan are bound to the command only
+func bindReportFlags(cmd *cobra.Command, outputPathUsage, outputName, outputNameUsage string) {
+	cmd.Flags().StringP(flags.OutputPathFlag, "o", "", outputPathUsage)
+	cmd.Flags().String(flags.OutputNameFlag, outputName, outputNameUsage)
+	cmd.Flags().StringSlice(flags.ReportFormatsFlag, []string{"json"},
+		"formats in which the results will be exported, as the scan report formats")
+}
+
+func validateReportFlags(cmd *cobra.Command) error {
+	reportFormats, err := cmd.Flags().GetStringSlice(flags.ReportFormatsFlag)
+	if err != nil {
+		return err
+	}
+	return flags.ValidateReportFormats(flags.ReportFormatsFlag, reportFormats)
+}
+
+// writeReports writes the summary in the report formats of the command, nothing without an output path
+func writeReports(cmd *cobra.Command, nameSuffix string, summary *model.Summary) error {
+	outputPath, _ := cmd.Flags().GetString(flags.OutputPathFlag)
+	if outputPath == "" {
+		return nil
+	}
+	outputName, _ := cmd.Flags().GetString(flags.OutputNameFlag)
+	reportFormats, _ := cmd.Flags().GetStringSlice(flags.ReportFormatsFlag)
+
+	if err := os.MkdirAll(outputPath, os.ModePerm); err != nil {
+		return err
+	}
+	return consoleHelpers.GenerateReport(outputPath, outputName+nameSuffix, summary, expandReportFormats(reportFormats),
+		&report.Options{MarkdownMaxLength: report.DefaultMarkdownMaxLength},
+		*progress.InitializePbBuilder(true, false, true))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/Checkmarx/kics/internal/constants"
 	sentryReport "github.com/Checkmarx/kics/internal/sentry"
 	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/report"
 	"github.com/Checkmarx/kics/pkg/scan"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -90,6 +91,11 @@ func run(cmd *cobra.Command) error {
 			return err
 		}
 	}
+	if flags.GetStrFlag(flags.QueryCoverageFlag) != "" && filepath.Dir(flags.GetStrFlag(flags.QueryCoverageFlag)) != "." {
+		if err := os.MkdirAll(filepath.Dir(flags.GetStrFlag(flags.QueryCoverageFlag)), os.ModePerm); err != nil {
+			return err
+		}
+	}
 	gracefulShutdown()
 
 	// save the scan parameters into the ScanParameters struct
@@ -99,12 +105,23 @@ func run(cmd *cobra.Command) error {
 }
 
 func updateReportFormats() {
-	for _, format := range flags.GetMultiStrFlag(flags.ReportFormatsFlag) {
+	flags.SetMultiStrFlag(flags.ReportFormatsFlag, expandReportFormats(flags.GetMultiStrFlag(flags.ReportFormatsFlag)))
+}
+
+// expandReportFormats replaces "all" by all the report formats, keeping the user templates
+func expandReportFormats(formats []string) []string {
+	for _, format := range formats {
 		if strings.EqualFold(format, "all") {
-			flags.SetMultiStrFlag(flags.ReportFormatsFlag, consoleHelpers.ListReportFormats())
-			break
+			allFormats := consoleHelpers.ListReportFormats()
+			for _, templateFormat := range formats {
+				if report.IsTemplateFormat(templateFormat) {
+					allFormats = append(allFormats, templateFormat)
+				}
+			}
+			return allFormats
 		}
 	}
+	return formats
 }
 
 func getScanParameters(changedDefaultQueryPath, changedDefaultLibrariesPath bool) *scan.Parameters {
@@ -121,12 +138,19 @@ func getScanParameters(changedDefaultQueryPath, changedDefaultLibrariesPath bool
 		InputData:                   flags.GetStrFlag(flags.InputDataFlag),
 		OutputName:                  flags.GetStrFlag(flags.OutputNameFlag),
 		OutputPath:                  flags.GetStrFlag(flags.OutputPathFlag),
+		Ownership:                   flags.GetBoolFlag(flags.OwnershipFlag),
+		Owners:                      flags.GetMultiStrFlag(flags.OwnerFlag),
 		Path:                        flags.GetMultiStrFlag(flags.PathFlag),
 		PayloadPath:                 flags.GetStrFlag(flags.PayloadPathFlag),
 		PreviewLines:                flags.GetIntFlag(flags.PreviewLinesFlag),
+		QueryCoverage:               flags.GetStrFlag(flags.QueryCoverageFlag),
 		QueriesPath:                 flags.GetMultiStrFlag(flags.QueriesPath),
 		LibrariesPath:               flags.GetStrFlag(flags.LibrariesPath),
 		ReportFormats:               flags.GetMultiStrFlag(flags.ReportFormatsFlag),
+		MarkdownMaxLength:           flags.GetIntFlag(flags.MarkdownMaxLengthFlag),
+		SarifBaseline:               flags.GetStrFlag(flags.SarifBaselineFlag),
+		HTMLHistory:                 flags.GetStrFlag(flags.HTMLHistoryFlag),
+		RiskWeights:                 flags.GetMultiStrFlag(flags.RiskWeightsFlag),
 		Platform:                    flags.GetMultiStrFlag(flags.TypeFlag),
 		ExcludePlatform:             flags.GetMultiStrFlag(flags.ExcludeTypeFlag),
 		TerraformVarsPath:           flags.GetStrFlag(flags.TerraformVarsPathFlag),
@@ -134,6 +158,10 @@ func getScanParameters(changedDefaultQueryPath, changedDefaultLibrariesPath bool
 		LineInfoPayload:             flags.GetBoolFlag(flags.LineInfoPayloadFlag),
 		DisableSecrets:              flags.GetBoolFlag(flags.DisableSecretsFlag),
 		SecretsRegexesPath:          flags.GetStrFlag(flags.SecretsRegexesPathFlag),
+		SecretsRulesPacks:           flags.GetMultiStrFlag(flags.SecretsRulesPacksFlag),
+		WaiversPath:                 flags.GetStrFlag(flags.WaiversFileFlag),
+		GatePath:                    flags.GetStrFlag(flags.GateFileFlag),
+		SecretsMinConfidence:        flags.GetStrFlag(flags.SecretsConfidenceFlag),
 		ScanID:                      scanID,
 		ChangedDefaultLibrariesPath: changedDefaultLibrariesPath,
 		ChangedDefaultQueryPath:     changedDefaultQueryPath,
@@ -175,7 +203,7 @@ func executeScan(scanParams *scan.Parameters) error {
 
 // gracefulShutdown catches signal interrupt and returns the appropriate exit code
 func gracefulShutdown() {
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	// This line should not be lint, since golangci-lint has an issue about it (https://github.com/golang/go/issues/45043)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM) //nolint
 	showErrors := consoleHelpers.ShowError("errors")
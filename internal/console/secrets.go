@@ -0,0 +1,98 @@
+package console
+
+import (
+	_ "embed" // Embed secrets test flags
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/assets"
+	"github.com/Checkmarx/kics/internal/console/flags"
+	"github.com/Checkmarx/kics/pkg/engine/secrets"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	//go:embed assets/secrets-test-flags.json
+	secretsTestFlagsListContent string
+)
+
+// NewSecretsCmd creates a new instance of the secrets Command
+func NewSecretsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "secrets",
+		Short: "Manages the passwords and secrets regex rules",
+	}
+}
+
+// NewSecretsTestCmd creates a new instance of the secrets test Command
+func NewSecretsTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "test",
+		Short:        "Validates the secrets regex rules packs and tests them against their samples",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return flags.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return secretsTest()
+		},
+	}
+}
+
+func initSecretsCmd(secretsCmd *cobra.Command) error {
+	secretsTestCmd := NewSecretsTestCmd()
+	secretsCmd.AddCommand(secretsTestCmd)
+	return flags.InitJSONFlags(
+		secretsTestCmd,
+		secretsTestFlagsListContent,
+		false,
+		source.ListSupportedPlatforms(),
+		source.ListSupportedCloudProviders())
+}
+
+func secretsTest() error {
+	regexRulesContent := assets.SecretsQueryRegexRulesJSON
+	if basePath := flags.GetStrFlag(flags.BaseRulesPathFlag); basePath != "" {
+		content, err := os.ReadFile(filepath.Clean(basePath))
+		if err != nil {
+			return err
+		}
+		regexRulesContent = string(content)
+	}
+
+	results, errs := secrets.TestRulesPacks(regexRulesContent, flags.GetMultiStrFlag(flags.RulesPacksFlag))
+	if len(errs) > 0 {
+		fmt.Printf("Invalid secrets regex rules:\n")
+		for _, err := range errs {
+			fmt.Printf("\t- %s\n", err)
+		}
+		return errors.New("secrets regex rules validation failed")
+	}
+
+	failed := 0
+	for i := range results {
+		status := "PASS"
+		if !results[i].Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s %s (%s)\n", status, results[i].Name, results[i].ID)
+		for _, message := range results[i].Errors {
+			fmt.Printf("\tERROR: %s\n", message)
+		}
+		for _, message := range results[i].Warnings {
+			fmt.Printf("\tWARNING: %s\n", message)
+		}
+	}
+
+	fmt.Printf("\nRules tested: %d\n", len(results))
+	fmt.Printf("Rules failed: %d\n", failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d secrets regex rules failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package console
+
+import (
+	_ "embed" // Embed test-queries flags
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Checkmarx/kics/internal/console/flags"
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/querytest"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	//go:embed assets/test-queries-flags.json
+	testQueriesFlagsListContent string
+)
+
+const testQueriesTimeout = 60
+
+// NewTestQueriesCmd creates a new instance of the test-queries Command
+func NewTestQueriesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "test-queries <path>...",
+		Short:        "Runs the positive and negative samples of queries against their expected results",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return flags.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return testQueries(cmd, args)
+		},
+	}
+}
+
+func initTestQueriesCmd(testQueriesCmd *cobra.Command) error {
+	if err := flags.InitJSONFlags(
+		testQueriesCmd,
+		testQueriesFlagsListContent,
+		false,
+		source.ListSupportedPlatforms(),
+		source.ListSupportedCloudProviders()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func testQueries(cmd *cobra.Command, paths []string) error {
+	queries, err := querytest.Discover(paths)
+	if err != nil {
+		return err
+	}
+	if len(queries) == 0 {
+		return fmt.Errorf("no queries found in %v", paths)
+	}
+
+	runner, err := querytest.NewRunner(flags.GetStrFlag(flags.TestLibrariesPathFlag), testQueriesTimeout)
+	if err != nil {
+		return err
+	}
+
+	queryCoverage := flags.GetStrFlag(flags.QueryCoverageFlag)
+	if queryCoverage != "" {
+		runner.Coverage = coverage.NewCollector()
+	}
+
+	results := make([]querytest.Result, 0, len(queries))
+	for i := range queries {
+		result := runner.Run(cmd.Context(), &queries[i])
+		results = append(results, result)
+
+		status := "PASS"
+		if !result.Passed() {
+			status = "FAIL"
+		}
+		fmt.Printf("%s %s\n", status, queries[i].String())
+		for _, check := range result.Checks {
+			for _, failure := range check.Failures {
+				fmt.Printf("\t%s: %s\n", check.Name, failure)
+			}
+		}
+	}
+
+	if junitOutput := flags.GetStrFlag(flags.JUnitOutputFlag); junitOutput != "" {
+		content, err := querytest.JUnit(results)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Clean(junitOutput), content, os.ModePerm); err != nil {
+			return errors.Wrap(err, "failed to write JUnit report")
+		}
+		log.Info().Msgf("JUnit report written to '%s'", junitOutput)
+	}
+
+	if runner.Coverage != nil {
+		report := runner.Coverage.Report()
+		if err := coverage.Write(queryCoverage, report); err != nil {
+			return errors.Wrap(err, "failed to write queries coverage")
+		}
+		log.Info().Msgf("Queries coverage written to '%s'", queryCoverage)
+		fmt.Printf("\nQueries coverage: %.2f%%\n", report.Coverage)
+	}
+
+	tested, failed := querytest.Summary(results)
+	fmt.Printf("\nQueries tested: %d\n", tested)
+	fmt.Printf("Queries failed: %d\n", failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d queries failed", failed)
+	}
+	return nil
+}
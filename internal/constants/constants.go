@@ -67,6 +67,14 @@ var (
 		"trace",
 	}
 
+	// AvailableFailOnNewSeverities - Severities accepted by the diff fail-on-new flag, trace results never fail it
+	AvailableFailOnNewSeverities = []string{
+		"high",
+		"medium",
+		"low",
+		"info",
+	}
+
 	// AvailableLogLevels - All log levels available
 	AvailableLogLevels = []string{
 		"TRACE",
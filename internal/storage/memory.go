@@ -46,12 +46,18 @@ func (m *MemoryStorage) GetVulnerabilities(_ context.Context, _ string) ([]model
 func (m *MemoryStorage) getUniqueVulnerabilities() []model.Vulnerability {
 	vulnDictionary := make(map[string]model.Vulnerability)
 	for i := range m.vulnerabilities {
+		// the search key of a result mapped back to an included file is the one of the file including it,
+		// so the result of a file included several times is kept once
+		searchKey := m.vulnerabilities[i].SearchKey
+		if m.vulnerabilities[i].ResolvedFrom != "" {
+			searchKey = ""
+		}
 		key := fmt.Sprintf("%s:%s:%d:%s:%s:%s",
 			m.vulnerabilities[i].QueryID,
 			m.vulnerabilities[i].FileName,
 			m.vulnerabilities[i].Line,
 			m.vulnerabilities[i].SimilarityID,
-			m.vulnerabilities[i].SearchKey,
+			searchKey,
 			m.vulnerabilities[i].KeyActualValue,
 		)
 		vulnDictionary[key] = m.vulnerabilities[i]
@@ -238,6 +238,27 @@ func TestMemoryStorage_SaveVulnerabilities(t *testing.T) {
 	}
 }
 
+// TestMemoryStorage_GetVulnerabilitiesResolved tests that the results of a file included by several files are kept once
+func TestMemoryStorage_GetVulnerabilitiesResolved(t *testing.T) {
+	m := NewMemoryStorage()
+	included := model.Vulnerability{
+		QueryID:      "query_id",
+		FileName:     "tasks/extra.yml",
+		Line:         2,
+		SimilarityID: "similarity_id",
+		SearchKey:    "name={{site}}.{{install nginx}}",
+		ResolvedFrom: "site.yml",
+	}
+	other := included
+	other.SearchKey = "name={{other}}.{{install nginx}}"
+	other.ResolvedFrom = "other.yml"
+	require.NoError(t, m.SaveVulnerabilities(context.Background(), []model.Vulnerability{included, other}))
+
+	got, err := m.GetVulnerabilities(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+}
+
 // TestNewMemoryStorage tests the functions [NewMemoryStorage()]
 func TestNewMemoryStorage(t *testing.T) {
 	tests := []struct {
@@ -409,7 +409,8 @@ func (a *analyzerInfo) worker(results chan<- model.FileAndType, unwanted chan<-
 	// It could be Ansible Config or Ansible Inventory
 	case ".cfg", ".conf", ".ini":
 		if a.isAvailableType(ansible) {
-			results <- ansible
+			fileAndType.Type = ansible
+			results <- fileAndType
 			locCount <- linesCount
 		}
 	/* It could be Ansible, Buildah, CICD, CloudFormation, Crossplane, OpenAPI, Azure Resource Manager
@@ -18,36 +18,68 @@ import (
 const resourceLabelsCount = 2
 
 // Engine contains the conditions of rules and comments positions
+// attributes are the tagged attributes by the line of their tag comment and blocks the lines of the blocks
+// holding the node being walked
 type Engine struct {
 	commentParser *commentParser.Parser
 	conditions    []build.Condition
+	attributes    map[int]attribute
+	blocks        []int
 }
 
 // Run parses files and execute engine.Run
 func Run(src []byte, filename string) ([]build.Rule, error) {
-	cp, err := commentParser.NewParser(src, filename)
+	e, body, err := newEngine(src, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Run(body)
+}
+
+// RunExample parses a Terraform example tagged with comments and returns its rules, the lines of the tagged
+// attributes are kept to build the test samples of the query
+func RunExample(src []byte, filename string) (*Example, error) {
+	e, body, err := newEngine(src, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := e.Run(body)
 	if err != nil {
 		return nil, err
 	}
 
+	return &Example{
+		Platform:   build.PlatformTerraform,
+		Rules:      rules,
+		lines:      strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n"),
+		attributes: e.attributes,
+	}, nil
+}
+
+func newEngine(src []byte, filename string) (*Engine, *hclsyntax.Body, error) {
+	cp, err := commentParser.NewParser(src, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Byte: 0, Line: 1, Column: 1})
 	if diags != nil && diags.HasErrors() {
-		return nil, diags.Errs()[0]
+		return nil, nil, diags.Errs()[0]
 	}
 	if file == nil {
-		return nil, fmt.Errorf("invalid parse result")
-	}
-
-	e := &Engine{
-		commentParser: cp,
+		return nil, nil, fmt.Errorf("invalid parse result")
 	}
 
-	return e.Run(file.Body.(*hclsyntax.Body))
+	return &Engine{commentParser: cp}, file.Body.(*hclsyntax.Body), nil
 }
 
 // Run initializes rules for Engine and returns it
 func (e *Engine) Run(body *hclsyntax.Body) ([]build.Rule, error) {
 	e.conditions = make([]build.Condition, 0)
+	e.attributes = make(map[int]attribute)
+	e.blocks = make([]int, 0)
 	if err := e.walkBody(body, []build.PathItem{}); err != nil {
 		return nil, err
 	}
@@ -103,6 +135,10 @@ func (e *Engine) walkBlock(block *hclsyntax.Block, walkHistory []build.PathItem)
 
 	e.checkComment(block.Range(), walkHistory, nil)
 
+	e.blocks = append(e.blocks, block.TypeRange.Start.Line)
+	defer func() {
+		e.blocks = e.blocks[:len(e.blocks)-1]
+	}()
 	return e.walkBody(block.Body, walkHistory)
 }
 
@@ -211,14 +247,14 @@ func (e *Engine) walkConstantItem(item hclsyntax.ObjectConsItem, walkHistory []b
 func (e *Engine) checkComment(rg hcl.Range, walkHistory []build.PathItem, actualValue *string) {
 	leadComment, endLineComment := e.commentParser.ParseCommentsForNode(rg)
 	if !leadComment.IsEmpty() {
-		e.addRule(walkHistory, leadComment, actualValue)
+		e.addRule(rg, walkHistory, leadComment, actualValue)
 	}
 	if !endLineComment.IsEmpty() {
-		e.addRule(walkHistory, endLineComment, actualValue)
+		e.addRule(rg, walkHistory, endLineComment, actualValue)
 	}
 }
 
-func (e *Engine) addRule(walkHistory []build.PathItem, comment commentParser.Comment, actualValue *string) {
+func (e *Engine) addRule(rg hcl.Range, walkHistory []build.PathItem, comment commentParser.Comment, actualValue *string) {
 	tags, err := tagParser.Parse(comment.Value(), model.AllIssueTypesAsString)
 	if err != nil {
 		log.Err(err).Msgf("Line %d: failed to parse comment '%s'", comment.Line(), comment.Value())
@@ -233,14 +269,32 @@ func (e *Engine) addRule(walkHistory []build.PathItem, comment commentParser.Com
 	copy(cp, walkHistory)
 
 	for _, t := range tags {
+		issueType := model.IssueType(t.Name)
 		e.conditions = append(e.conditions, build.Condition{
 			Line:       comment.Line(),
-			IssueType:  model.IssueType(t.Name),
+			IssueType:  issueType,
 			Path:       cp,
 			Value:      actualValue,
 			Attributes: t.Attributes,
 		})
+		e.attributes[comment.Line()] = attribute{
+			issueType:  issueType,
+			start:      rg.Start.Line,
+			end:        rg.End.Line,
+			resultLine: e.resultLine(rg, issueType),
+		}
+	}
+}
+
+// resultLine returns the line the query reports, the line of the block holding a missing attribute
+func (e *Engine) resultLine(rg hcl.Range, issueType model.IssueType) int {
+	if issueType != model.IssueTypeMissingAttribute {
+		return rg.Start.Line
+	}
+	if len(e.blocks) == 0 {
+		return 1
 	}
+	return e.blocks[len(e.blocks)-1]
 }
 
 func evaluateScopeTraversalExpr(t hcl.Traversal) []string {
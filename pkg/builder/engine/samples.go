@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	build "github.com/Checkmarx/kics/pkg/builder/model"
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+var (
+	tagCommentRegex = regexp.MustCompile(`\s*#\s*(` + strings.Join(model.AllIssueTypesAsString, "|") + `)\b.*$`)
+	// hclTagCommentRegex matches the tags of Terraform examples, written in # or // comments
+	hclTagCommentRegex = regexp.MustCompile(`\s*(#|//)\s*(` + strings.Join(model.AllIssueTypesAsString, "|") + `)\b.*$`)
+	trailingComma      = regexp.MustCompile(`,(\s*[}\]])`)
+)
+
+// Sample is a test sample built from the example, ExpectedLines are the lines of the results of each rule
+type Sample struct {
+	Content       []byte
+	ExpectedLines []int
+}
+
+// Positive returns the example without the missing attributes and the tags, with the lines the rules report
+func (e *Example) Positive() Sample {
+	content, lines := e.sample(func(issueType model.IssueType) bool {
+		return issueType == model.IssueTypeMissingAttribute
+	})
+
+	expected := make([]int, 0, len(e.Rules))
+	for _, rule := range e.Rules {
+		last := rule.Conditions[len(rule.Conditions)-1]
+		if line, ok := lines[e.attributes[last.Line].resultLine]; ok {
+			expected = append(expected, line)
+		}
+	}
+	sort.Ints(expected)
+
+	return Sample{Content: content, ExpectedLines: expected}
+}
+
+// Negative returns the example without the incorrect and redundant attributes and the tags
+func (e *Example) Negative() Sample {
+	content, _ := e.sample(func(issueType model.IssueType) bool {
+		return issueType != model.IssueTypeMissingAttribute
+	})
+	return Sample{Content: content}
+}
+
+// sample removes the tags and the attributes matching remove, it returns the content and the new line
+// of each line kept
+func (e *Example) sample(remove func(issueType model.IssueType) bool) (content []byte, lines map[int]int) {
+	removed := make(map[int]bool)
+	for _, attr := range e.attributes {
+		if !remove(attr.issueType) {
+			continue
+		}
+		for line := attr.start; line <= attr.end; line++ {
+			removed[line] = true
+		}
+	}
+
+	tagComment := tagCommentRegex
+	if e.Platform == build.PlatformTerraform {
+		tagComment = hclTagCommentRegex
+	}
+
+	kept := make([]string, 0, len(e.lines))
+	lines = make(map[int]int, len(e.lines))
+	for i, text := range e.lines {
+		line := i + 1
+		if removed[line] {
+			continue
+		}
+		untagged := tagComment.ReplaceAllString(text, "")
+		if untagged != text && strings.TrimSpace(untagged) == "" {
+			continue
+		}
+		kept = append(kept, untagged)
+		lines[line] = len(kept)
+	}
+
+	result := strings.Join(kept, "\n")
+	if e.JSON {
+		result = trailingComma.ReplaceAllString(result, "$1")
+	}
+	return []byte(result), lines
+}
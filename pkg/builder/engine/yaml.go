@@ -0,0 +1,360 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	build "github.com/Checkmarx/kics/pkg/builder/model"
+	tagParser "github.com/Checkmarx/kics/pkg/builder/parser/tag"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ansibleTaskKeys are the keys of the lists of tasks in plays and blocks
+var ansibleTaskKeys = map[string]bool{
+	"tasks":      true,
+	"pre_tasks":  true,
+	"post_tasks": true,
+	"handlers":   true,
+	"block":      true,
+	"rescue":     true,
+	"always":     true,
+}
+
+// Example is a YAML or JSON example with its tagged attributes, the lines of the attributes are kept
+// to build the test samples of the query
+type Example struct {
+	Platform   string
+	JSON       bool
+	Rules      []build.Rule
+	lines      []string
+	attributes map[int]attribute
+}
+
+// attribute is a tagged attribute of the example, resultLine is the line the query reports,
+// the line of its parent for missing attributes
+type attribute struct {
+	issueType  model.IssueType
+	start      int
+	end        int
+	resultLine int
+}
+
+// yamlPathItem is a key or a sequence item in the path of a node, with its value node
+type yamlPathItem struct {
+	key   string
+	index bool
+	name  string
+	node  *yaml.Node
+	line  int
+}
+
+type yamlEngine struct {
+	example    *Example
+	conditions []build.Condition
+	// comments are the tag comments by the line of the attribute they are in or above
+	comments map[int]string
+}
+
+// RunYAML parses a YAML or JSON example of the platform, tagged with # comments, and returns its rules
+func RunYAML(src []byte, filename, platform string) (*Example, error) {
+	e := &yamlEngine{
+		example: &Example{
+			Platform:   platform,
+			JSON:       strings.EqualFold(filepath.Ext(filename), ".json"),
+			lines:      strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n"),
+			attributes: make(map[int]attribute),
+		},
+	}
+	e.comments = tagComments(e.example.lines)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(src))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		e.walk(document.Content[0], document.Content[0], []yamlPathItem{})
+	}
+
+	e.example.Rules = groupConditions(e.conditions)
+	return e.example, nil
+}
+
+// groupConditions returns a rule for each condition without group and a rule for each group, sorted by name
+func groupConditions(conditions []build.Condition) []build.Rule {
+	rules := make([]build.Rule, 0)
+	groups := make(map[string][]build.Condition)
+	for _, condition := range conditions {
+		group, ok := condition.AttrAsString("group")
+		if !ok {
+			rules = append(rules, build.Rule{Conditions: []build.Condition{condition}})
+			continue
+		}
+		groups[group] = append(groups[group], condition)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rules = append(rules, build.Rule{Conditions: groups[name]})
+	}
+	return rules
+}
+
+func (e *yamlEngine) walk(document, node *yaml.Node, path []yamlPathItem) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			itemPath := appendPath(path, yamlPathItem{key: key.Value, node: value, line: key.Line})
+			e.addConditions(document, itemPath)
+			e.walk(document, value, itemPath)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			itemPath := appendPath(path, yamlPathItem{index: true, name: mappingValue(item, "name"), node: item, line: item.Line})
+			if item.Kind == yaml.ScalarNode {
+				e.addConditions(document, itemPath)
+			}
+			e.walk(document, item, itemPath)
+		}
+	}
+}
+
+func appendPath(path []yamlPathItem, item yamlPathItem) []yamlPathItem {
+	result := make([]yamlPathItem, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, item)
+}
+
+// tagComments returns the tag comments of the lines, a comment alone in its line tags the next line
+func tagComments(lines []string) map[int]string {
+	comments := make(map[int]string)
+	pending := ""
+	for i, text := range lines {
+		comment := tagCommentRegex.FindString(text)
+		if comment != "" && strings.TrimSpace(strings.TrimSuffix(text, comment)) == "" {
+			pending = strings.TrimSpace(pending + " " + strings.TrimSpace(comment))
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if all := strings.TrimSpace(pending + " " + strings.TrimSpace(comment)); all != "" {
+			comments[i+1] = all
+		}
+		pending = ""
+	}
+	return comments
+}
+
+// addConditions adds the conditions of the tags in the line of the last item of the path, the tags of a line
+// belong to its first attribute
+func (e *yamlEngine) addConditions(document *yaml.Node, path []yamlPathItem) {
+	last := path[len(path)-1]
+	comment, ok := e.comments[last.line]
+	if !ok {
+		return
+	}
+	delete(e.comments, last.line)
+
+	tags, err := tagParser.Parse(comment, model.AllIssueTypesAsString)
+	if err != nil {
+		log.Err(err).Msgf("Line %d: failed to parse comment '%s'", last.line, comment)
+		return
+	}
+
+	conditionPath, ok := e.annotate(document, path)
+	if !ok {
+		log.Warn().Msgf("Line %d: the attribute is not part of a %s resource", last.line, e.example.Platform)
+		return
+	}
+
+	var value interface{}
+	if err := last.node.Decode(&value); err != nil {
+		log.Err(err).Msgf("Line %d: failed to decode value", last.line)
+		return
+	}
+
+	for _, t := range tags {
+		issueType := model.IssueType(t.Name)
+		e.conditions = append(e.conditions, build.Condition{
+			Line:       last.line,
+			IssueType:  issueType,
+			Path:       conditionPath,
+			Value:      value,
+			Attributes: t.Attributes,
+		})
+		e.example.attributes[last.line] = attribute{
+			issueType:  issueType,
+			start:      last.line,
+			end:        e.endLine(last.node),
+			resultLine: e.resultLine(document, path, issueType),
+		}
+	}
+}
+
+// annotate returns the path of the condition, with the resource items of the platform
+func (e *yamlEngine) annotate(document *yaml.Node, path []yamlPathItem) ([]build.PathItem, bool) {
+	switch e.example.Platform {
+	case build.PlatformKubernetes:
+		kind := mappingValue(document, "kind")
+		if kind == "" {
+			return nil, false
+		}
+		return append([]build.PathItem{{Type: build.PathTypeResourceType, Name: kind}}, pathItems(path)...), true
+	case build.PlatformCloudFormation:
+		if len(path) < 3 || path[0].key != "Resources" || path[1].index {
+			return nil, false
+		}
+		return append([]build.PathItem{
+			{Type: build.PathTypeResource, Name: path[0].key},
+			{Type: build.PathTypeResourceType, Name: mappingValue(path[1].node, "Type")},
+			{Type: build.PathTypeResourceName, Name: path[1].key},
+		}, pathItems(path[2:])...), true
+	case build.PlatformDockerCompose:
+		if len(path) < 3 || path[0].key != "services" || path[1].index {
+			return nil, false
+		}
+		return append([]build.PathItem{
+			{Type: build.PathTypeResource, Name: path[0].key},
+			{Type: build.PathTypeResourceName, Name: path[1].key},
+		}, pathItems(path[2:])...), true
+	case build.PlatformAnsible:
+		// the module is the key of the innermost task holding the attribute
+		for i := len(path) - 3; i >= 0; i-- {
+			if !path[i].index || path[i+1].index || !(i == 0 || (!path[i-1].index && ansibleTaskKeys[path[i-1].key])) {
+				continue
+			}
+			return append([]build.PathItem{
+				{Type: build.PathTypeResourceType, Name: path[i+1].key},
+			}, pathItems(path[i+2:])...), true
+		}
+	}
+	return nil, false
+}
+
+func pathItems(path []yamlPathItem) []build.PathItem {
+	items := make([]build.PathItem, 0, len(path))
+	for _, item := range path {
+		if item.index {
+			items = append(items, build.PathItem{Type: build.PathTypeIndex, Name: item.name})
+		} else {
+			items = append(items, build.PathItem{Type: build.PathTypeDefault, Name: item.key})
+		}
+	}
+	return items
+}
+
+// resultLine returns the line of the last item of the search key, sequence items without name are not part
+// of the search key and a missing attribute is reported in its parent
+func (e *yamlEngine) resultLine(document *yaml.Node, path []yamlPathItem, issueType model.IssueType) int {
+	if issueType == model.IssueTypeMissingAttribute {
+		path = path[:len(path)-1]
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if !path[i].index || path[i].name != "" {
+			return path[i].line
+		}
+	}
+
+	// the search keys of kubernetes start with the name of the resource
+	if metadata := mappingNode(document, "metadata"); metadata != nil {
+		if name := mappingNode(metadata, "name"); name != nil {
+			return name.Line
+		}
+	}
+	return document.Line
+}
+
+// endLine returns the last line of the node, the closing bracket of flow mappings and sequences included
+func (e *yamlEngine) endLine(node *yaml.Node) int {
+	if node.Style&yaml.FlowStyle != 0 && (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode) {
+		return closingLine(e.example.lines, node.Line, node.Column)
+	}
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return node.Line + strings.Count(strings.TrimRight(node.Value, "\n"), "\n") + 1
+	}
+
+	end := node.Line
+	for _, child := range node.Content {
+		if childEnd := e.endLine(child); childEnd > end {
+			end = childEnd
+		}
+	}
+	return end
+}
+
+// closingLine returns the line of the bracket closing the one in the line and column
+func closingLine(lines []string, line, column int) int {
+	depth := 0
+	inString := false
+	for l := line; l <= len(lines); l++ {
+		start := 0
+		if l == line {
+			start = column - 1
+		}
+		text := lines[l-1]
+		for c := start; c < len(text); c++ {
+			switch {
+			case text[c] == '"' && (c == 0 || text[c-1] != '\\'):
+				inString = !inString
+			case inString:
+			case text[c] == '{' || text[c] == '[':
+				depth++
+			case text[c] == '}' || text[c] == ']':
+				depth--
+				if depth == 0 {
+					return l
+				}
+			}
+		}
+	}
+	return line
+}
+
+func mappingNode(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) string {
+	if value := mappingNode(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// LookupPlatform returns the builder platform with the name, case insensitive
+func LookupPlatform(name string) (string, error) {
+	for _, platform := range build.Platforms() {
+		if strings.EqualFold(platform, name) {
+			return platform, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported platform %s, supported platforms are %s", name, strings.Join(build.Platforms(), ", "))
+}
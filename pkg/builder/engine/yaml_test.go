@@ -0,0 +1,230 @@
+package engine
+
+import (
+	"testing"
+
+	build "github.com/Checkmarx/kics/pkg/builder/model"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunYAML tests the functions [RunYAML()] and all the methods called by them
+func TestRunYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		filename string
+		platform string
+		want     []build.Rule
+	}{
+		{
+			name: "kubernetes",
+			src: `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+    - name: web
+      # MissingAttribute
+      securityContext:
+        runAsNonRoot: true
+      image: nginx:latest # IncorrectValue:"group=image"
+`,
+			filename: "pod.yaml",
+			platform: build.PlatformKubernetes,
+			want: []build.Rule{
+				{Conditions: []build.Condition{{
+					Line:      9,
+					IssueType: model.IssueTypeMissingAttribute,
+					Path: []build.PathItem{
+						{Name: "Pod", Type: build.PathTypeResourceType},
+						{Name: "spec", Type: build.PathTypeDefault},
+						{Name: "containers", Type: build.PathTypeDefault},
+						{Name: "web", Type: build.PathTypeIndex},
+						{Name: "securityContext", Type: build.PathTypeDefault},
+					},
+					Value:      map[string]interface{}{"runAsNonRoot": true},
+					Attributes: map[string]interface{}{},
+				}}},
+				{Conditions: []build.Condition{{
+					Line:      11,
+					IssueType: model.IssueTypeIncorrectValue,
+					Path: []build.PathItem{
+						{Name: "Pod", Type: build.PathTypeResourceType},
+						{Name: "spec", Type: build.PathTypeDefault},
+						{Name: "containers", Type: build.PathTypeDefault},
+						{Name: "web", Type: build.PathTypeIndex},
+						{Name: "image", Type: build.PathTypeDefault},
+					},
+					Value:      "nginx:latest",
+					Attributes: map[string]interface{}{"group": "image"},
+				}}},
+			},
+		},
+		{
+			name: "cloudformation_json",
+			src: `{
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {
+        "AccessControl": "PublicRead" # IncorrectValue
+      }
+    }
+  }
+}
+`,
+			filename: "template.json",
+			platform: build.PlatformCloudFormation,
+			want: []build.Rule{
+				{Conditions: []build.Condition{{
+					Line:      6,
+					IssueType: model.IssueTypeIncorrectValue,
+					Path: []build.PathItem{
+						{Name: "Resources", Type: build.PathTypeResource},
+						{Name: "AWS::S3::Bucket", Type: build.PathTypeResourceType},
+						{Name: "Bucket", Type: build.PathTypeResourceName},
+						{Name: "Properties", Type: build.PathTypeDefault},
+						{Name: "AccessControl", Type: build.PathTypeDefault},
+					},
+					Value:      "PublicRead",
+					Attributes: map[string]interface{}{},
+				}}},
+			},
+		},
+		{
+			name: "ansible",
+			src: `- name: bucket
+  hosts: localhost
+  tasks:
+    - name: create bucket
+      amazon.aws.s3_bucket:
+        name: public
+        acl: public-read # IncorrectValue
+`,
+			filename: "playbook.yaml",
+			platform: build.PlatformAnsible,
+			want: []build.Rule{
+				{Conditions: []build.Condition{{
+					Line:      7,
+					IssueType: model.IssueTypeIncorrectValue,
+					Path: []build.PathItem{
+						{Name: "amazon.aws.s3_bucket", Type: build.PathTypeResourceType},
+						{Name: "acl", Type: build.PathTypeDefault},
+					},
+					Value:      "public-read",
+					Attributes: map[string]interface{}{},
+				}}},
+			},
+		},
+		{
+			name: "outside_resource",
+			src: `version: "3"
+volumes:
+  data: {} # RedundantAttribute
+`,
+			filename: "docker-compose.yaml",
+			platform: build.PlatformDockerCompose,
+			want:     []build.Rule{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RunYAML([]byte(tt.src), tt.filename, tt.platform)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.Rules)
+		})
+	}
+}
+
+// TestExampleSamples tests the functions [Positive()] and [Negative()]
+func TestExampleSamples(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		example, err := RunYAML([]byte(`version: "3"
+services:
+  web:
+    image: nginx
+    privileged: true # IncorrectValue
+    # MissingAttribute
+    mem_limit: 512m
+    read_only: true
+`), "docker-compose.yaml", build.PlatformDockerCompose)
+		require.NoError(t, err)
+
+		positive := example.Positive()
+		require.Equal(t, `version: "3"
+services:
+  web:
+    image: nginx
+    privileged: true
+    read_only: true
+`, string(positive.Content))
+		require.Equal(t, []int{3, 5}, positive.ExpectedLines)
+
+		require.Equal(t, `version: "3"
+services:
+  web:
+    image: nginx
+    mem_limit: 512m
+    read_only: true
+`, string(example.Negative().Content))
+	})
+
+	t.Run("terraform", func(t *testing.T) {
+		example, err := RunExample([]byte(`resource "aws_s3_bucket" "b" {
+  bucket = "my-bucket"
+  acl    = "public-read" // IncorrectValue:"resource=aws_s3_bucket"
+
+  // MissingAttribute:"resource=aws_s3_bucket"
+  force_destroy = true
+}
+`), "example.tf")
+		require.NoError(t, err)
+
+		positive := example.Positive()
+		require.Equal(t, `resource "aws_s3_bucket" "b" {
+  bucket = "my-bucket"
+  acl    = "public-read"
+
+}
+`, string(positive.Content))
+		require.Equal(t, []int{1, 3}, positive.ExpectedLines)
+
+		require.Equal(t, `resource "aws_s3_bucket" "b" {
+  bucket = "my-bucket"
+
+  force_destroy = true
+}
+`, string(example.Negative().Content))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		example, err := RunYAML([]byte(`{
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {
+        "BucketName": "bucket",
+        "AccessControl": "PublicRead" # IncorrectValue
+      }
+    }
+  }
+}`), "template.json", build.PlatformCloudFormation)
+		require.NoError(t, err)
+
+		positive := example.Positive()
+		require.Equal(t, 7, positive.ExpectedLines[0])
+		require.Equal(t, `{
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {
+        "BucketName": "bucket"
+      }
+    }
+  }
+}`, string(example.Negative().Content))
+	})
+}
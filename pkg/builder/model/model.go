@@ -11,9 +11,11 @@ const (
 	PathTypeResource     PathItemType = "RESOURCE"
 	PathTypeResourceType PathItemType = "RESOURCE_TYPE"
 	PathTypeResourceName PathItemType = "RESOURCE_NAME"
+	PathTypeIndex        PathItemType = "INDEX"
 )
 
-// PathItem represents json's element name and type
+// PathItem represents json's element name and type, the name of an index item is the name of the
+// element when it has one
 type PathItem struct {
 	Name string
 	Type PathItemType
@@ -57,3 +59,17 @@ func (c Condition) AttrAsString(name string) (string, bool) {
 
 	return "", false
 }
+
+// Platforms of the examples, named as in the queries metadata
+const (
+	PlatformTerraform      = "Terraform"
+	PlatformKubernetes     = "Kubernetes"
+	PlatformCloudFormation = "CloudFormation"
+	PlatformDockerCompose  = "DockerCompose"
+	PlatformAnsible        = "Ansible"
+)
+
+// Platforms returns the platforms supported by the builder
+func Platforms() []string {
+	return []string{PlatformTerraform, PlatformKubernetes, PlatformCloudFormation, PlatformDockerCompose, PlatformAnsible}
+}
@@ -20,10 +20,10 @@ type Tag struct {
 	Attributes map[string]interface{}
 }
 
-// Parse tag from following structure
+// Parse tag from following structure, in a // or # comment
 // name1:"expected=private,test=false" name2:"attr=1"
 func Parse(s string, supportedNames []string) ([]Tag, error) {
-	s = strings.TrimLeft(strings.TrimLeft(strings.TrimSpace(s), "/"), " ")
+	s = strings.TrimLeft(strings.TrimLeft(strings.TrimSpace(s), "/#"), " ")
 	var tags []Tag
 	for _, si := range strings.Split(s, " ") {
 		cleanSi := strings.TrimSpace(si)
@@ -93,6 +93,19 @@ func TestParseTags(t *testing.T) { //nolint
 		})
 	})
 
+	t.Run("hash_comment", func(t *testing.T) {
+		tags, err := Parse("# Test:\"group=a\"", []string{"Test"})
+		require.NoError(t, err)
+		assertEqualTags(t, tags, []Tag{
+			{
+				Name: "Test",
+				Attributes: map[string]interface{}{
+					"group": "a",
+				},
+			},
+		})
+	})
+
 	t.Run("parse_args", func(t *testing.T) {
 		tags, err := Parse("// Test:testArr[a=testA,b=testB]", []string{"Test"})
 		require.NoError(t, err)
@@ -0,0 +1,490 @@
+package writer
+
+import (
+	"bytes"
+	_ "embed" // Embed the platforms template
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	build "github.com/Checkmarx/kics/pkg/builder/model"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	//go:embed template_yaml.gorego
+	yamlTemplate string
+
+	identifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	nonWordRegex    = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+	// k8sSpecPaths are the paths of the pod specs returned by the getSpecInfo library function, the longest first
+	k8sSpecPaths = [][]string{
+		{"spec", "jobTemplate", "spec", "template", "spec"},
+		{"spec", "template", "spec"},
+		{"spec"},
+	}
+)
+
+// YAMLWriter renders the rules of a YAML or JSON example as a query of its platform
+type YAMLWriter struct {
+	tmpl *template.Template
+}
+
+// YAMLQuery is the data of the platform template
+type YAMLQuery struct {
+	Imports []string
+	Rules   []YAMLRule
+}
+
+// YAMLRule is a policy of the query, its bindings, conditions and result fields
+type YAMLRule struct {
+	Bindings   []string
+	Conditions []string
+	Result     []ResultField
+}
+
+// ResultField is a field of the result of a policy, Value is rego
+type ResultField struct {
+	Key   string
+	Value string
+}
+
+// QueryMetadata is the metadata.json of a generated query
+type QueryMetadata struct {
+	ID              string `json:"id"`
+	QueryName       string `json:"queryName"`
+	Severity        string `json:"severity"`
+	Category        string `json:"category"`
+	DescriptionText string `json:"descriptionText"`
+	DescriptionURL  string `json:"descriptionUrl"`
+	Platform        string `json:"platform"`
+	DescriptionID   string `json:"descriptionID"`
+	CloudProvider   string `json:"cloudProvider,omitempty"`
+}
+
+// step is a path item of a condition, with the rego reference to it and its search key
+type step struct {
+	name    string
+	index   bool
+	ref     string
+	binding string
+	format  string
+	args    []string
+}
+
+// ruleBuilder builds the policy of a rule, the bindings are shared by its conditions
+type ruleBuilder struct {
+	platform string
+	block    Block
+	rule     YAMLRule
+	bound    map[string]string
+	vars     map[string]bool
+	usesSpec bool
+}
+
+// NewYAMLWriter initializes a YAMLWriter with the platforms template
+func NewYAMLWriter() (*YAMLWriter, error) {
+	tmpl, err := template.New("template_yaml.gorego").Parse(yamlTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &YAMLWriter{tmpl: tmpl}, nil
+}
+
+// Render returns the query of the platform for the rules
+func (w *YAMLWriter) Render(platform string, rules []build.Rule) ([]byte, error) {
+	query := YAMLQuery{
+		Imports: []string{"data.generic.common as common_lib"},
+		Rules:   make([]YAMLRule, 0, len(rules)),
+	}
+	switch platform {
+	case build.PlatformKubernetes:
+		query.Imports = append(query.Imports, "data.generic.k8s as k8sLib")
+	case build.PlatformCloudFormation:
+		query.Imports = append(query.Imports, "data.generic.cloudformation as cf_lib")
+	case build.PlatformAnsible:
+		query.Imports = append(query.Imports, "data.generic.ansible as ansLib")
+	case build.PlatformDockerCompose:
+	default:
+		return nil, fmt.Errorf("platform %s is not supported by the YAML writer", platform)
+	}
+
+	for _, rule := range rules {
+		query.Rules = append(query.Rules, newRuleBuilder(platform, rule).build(rule))
+	}
+
+	wr := bytes.NewBuffer(nil)
+	if err := w.tmpl.Execute(wr, query); err != nil {
+		return nil, errors.Wrap(err, "failed to render")
+	}
+	return wr.Bytes(), nil
+}
+
+func newRuleBuilder(platform string, rule build.Rule) *ruleBuilder {
+	block := createBlock(rule)
+	sort.Strings(block.List)
+	return &ruleBuilder{
+		platform: platform,
+		block:    block,
+		bound:    make(map[string]string),
+		vars: map[string]bool{
+			"document": true, "metadata": true, "specInfo": true, "resource": true, "resourceTypes": true,
+			"service": true, "task": true, "modules": true, "module": true, "result": true, "name": true,
+		},
+	}
+}
+
+func (b *ruleBuilder) build(rule build.Rule) YAMLRule {
+	b.resourceBindings()
+
+	var result YAMLRule
+	for i, condition := range rule.Conditions {
+		steps := b.steps(condition)
+		last := len(steps) - 1
+		used := last
+		if condition.IssueType == model.IssueTypeMissingAttribute {
+			used = last - 1
+		}
+		if b.usesSpec {
+			b.addBinding("specInfo := k8sLib.getSpecInfo(document)")
+		}
+		for _, s := range steps[1 : used+1] {
+			b.bind(s)
+		}
+
+		b.rule.Conditions = append(b.rule.Conditions, yamlCondition(condition, steps[last-1], steps[last]))
+
+		if i < len(rule.Conditions)-1 {
+			continue
+		}
+		keyPath := keyPath(condition)
+		expected, actual := yamlResultValues(condition, keyPath, steps[last].ref)
+		searchStep := steps[last]
+		if condition.IssueType == model.IssueTypeMissingAttribute {
+			searchStep = steps[last-1]
+		}
+		result = b.rule
+		result.Result = append(b.resultFields(),
+			ResultField{Key: "searchKey", Value: sprintf(searchStep.format, searchStep.args)},
+			ResultField{Key: "issueType", Value: fmt.Sprintf("%q", condition.IssueType)},
+			ResultField{Key: "keyExpectedValue", Value: expected},
+			ResultField{Key: "keyActualValue", Value: actual},
+		)
+	}
+	return result
+}
+
+// resourceBindings binds the resources of the platform and filters them by type
+func (b *ruleBuilder) resourceBindings() {
+	switch b.platform {
+	case build.PlatformKubernetes:
+		b.addBinding("document := input.document[i]")
+		b.addBinding("metadata := document.metadata")
+		if !b.block.All {
+			b.addBinding(fmt.Sprintf("k8sLib.checkKind(document.kind, %s)", stringList(b.block.List)))
+		}
+	case build.PlatformCloudFormation:
+		b.addBinding("document := input.document[i]")
+		b.addBinding("resource := document.Resources[name]")
+		if !b.block.All {
+			b.addBinding(fmt.Sprintf("resourceTypes := {%s}", strings.Trim(stringList(b.block.List), "[]")))
+			b.addBinding("resource.Type == resourceTypes[_]")
+		}
+	case build.PlatformDockerCompose:
+		b.addBinding("document := input.document[i]")
+		b.addBinding("service := document.services[name]")
+	case build.PlatformAnsible:
+		b.addBinding("task := ansLib.tasks[id][t]")
+		if b.block.All {
+			b.addBinding("module := task[moduleName]")
+			b.addBinding("is_object(module)")
+		} else {
+			b.addBinding(fmt.Sprintf("modules := {%s}", strings.Trim(stringList(ansibleModules(b.block.List)), "[]")))
+			b.addBinding("module := task[modules[m]]")
+		}
+		b.addBinding("ansLib.checkState(module)")
+	}
+}
+
+// ansibleModules adds the short name of the modules with a fully qualified collection name
+func ansibleModules(modules []string) []string {
+	names := make(map[string]bool, len(modules))
+	for _, module := range modules {
+		names[module] = true
+		if i := strings.LastIndex(module, "."); i >= 0 {
+			names[module[i+1:]] = true
+		}
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (b *ruleBuilder) resultFields() []ResultField {
+	switch b.platform {
+	case build.PlatformKubernetes:
+		return []ResultField{
+			{Key: "documentId", Value: "document.id"},
+			{Key: "resourceType", Value: "document.kind"},
+			{Key: "resourceName", Value: "metadata.name"},
+		}
+	case build.PlatformCloudFormation:
+		return []ResultField{
+			{Key: "documentId", Value: "document.id"},
+			{Key: "resourceType", Value: "resource.Type"},
+			{Key: "resourceName", Value: "cf_lib.get_resource_name(resource, name)"},
+		}
+	case build.PlatformAnsible:
+		return []ResultField{
+			{Key: "documentId", Value: "id"},
+			{Key: "resourceType", Value: b.moduleName()},
+			{Key: "resourceName", Value: "task.name"},
+		}
+	default:
+		return []ResultField{{Key: "documentId", Value: "document.id"}}
+	}
+}
+
+func (b *ruleBuilder) moduleName() string {
+	if b.block.All {
+		return "moduleName"
+	}
+	return "modules[m]"
+}
+
+// steps returns the resource of the condition followed by the items of its path
+func (b *ruleBuilder) steps(c build.Condition) []step {
+	path := make([]build.PathItem, 0, len(c.Path))
+	for _, item := range c.Path {
+		if item.Type == build.PathTypeDefault || item.Type == build.PathTypeIndex {
+			path = append(path, item)
+		}
+	}
+
+	var base step
+	switch b.platform {
+	case build.PlatformKubernetes:
+		base = step{ref: "document", format: "metadata.name={{%s}}", args: []string{"metadata.name"}}
+		if n := k8sSpecPrefix(path); n > 0 {
+			b.usesSpec = true
+			base = step{ref: "specInfo.spec", format: "metadata.name={{%s}}.%s", args: []string{"metadata.name", "specInfo.path"}}
+			path = path[n:]
+		}
+	case build.PlatformCloudFormation:
+		base = step{ref: "resource", format: "Resources.%s", args: []string{"name"}}
+	case build.PlatformDockerCompose:
+		base = step{ref: "service", format: "services.%s", args: []string{"name"}}
+	case build.PlatformAnsible:
+		base = step{ref: "module", format: "name={{%s}}.{{%s}}", args: []string{"task.name", b.moduleName()}}
+	}
+
+	_, anyKey := c.Attr("any_key")
+	steps := []step{base}
+	for i, item := range path {
+		previous := steps[len(steps)-1]
+		current := step{name: item.Name, format: previous.format, args: previous.args}
+		switch {
+		case item.Type == build.PathTypeIndex:
+			current.index = true
+			current.ref = b.elementVar(previous)
+			current.binding = fmt.Sprintf("%s := %s[_]", current.ref, previous.ref)
+			if item.Name != "" {
+				current.format += ".name={{%s}}"
+				current.args = appendArg(previous.args, current.ref+".name")
+			}
+		case anyKey && i == len(path)-1:
+			current.ref = previous.ref + "[key]"
+			current.format += ".%s"
+			current.args = appendArg(previous.args, "key")
+		default:
+			current.ref = previous.ref + accessor(item.Name)
+			current.format += "." + item.Name
+		}
+		steps = append(steps, current)
+	}
+	return steps
+}
+
+// k8sSpecPrefix returns the length of the pod spec path the condition is in, zero when it is not
+func k8sSpecPrefix(path []build.PathItem) int {
+	for _, specPath := range k8sSpecPaths {
+		if len(path) <= len(specPath) {
+			continue
+		}
+		matches := true
+		for i, key := range specPath {
+			if path[i].Type != build.PathTypeDefault || path[i].Name != key {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return len(specPath)
+		}
+	}
+	return 0
+}
+
+// elementVar returns the variable bound to the items of the sequence, shared by the conditions of the rule
+func (b *ruleBuilder) elementVar(sequence step) string {
+	if v, ok := b.bound[sequence.ref]; ok {
+		return v
+	}
+
+	name := strings.TrimSuffix(sequence.name, "s")
+	parts := nonWordRegex.Split(name, -1)
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	name = strings.Join(parts, "")
+	if name == "" || name == sequence.name || !identifierRegex.MatchString(name) {
+		name = "item"
+	}
+
+	v := name
+	for i := 2; b.vars[v]; i++ {
+		v = fmt.Sprintf("%s%d", name, i)
+	}
+	b.vars[v] = true
+	b.bound[sequence.ref] = v
+	return v
+}
+
+func (b *ruleBuilder) bind(s step) {
+	if s.binding != "" {
+		b.addBinding(s.binding)
+	}
+}
+
+func (b *ruleBuilder) addBinding(binding string) {
+	for _, existing := range b.rule.Bindings {
+		if existing == binding {
+			return
+		}
+	}
+	b.rule.Bindings = append(b.rule.Bindings, binding)
+}
+
+func yamlCondition(c build.Condition, parent, current step) string {
+	switch c.IssueType {
+	case model.IssueTypeMissingAttribute:
+		if current.index {
+			return fmt.Sprintf("not common_lib.inArray(%s, %s)", parent.ref, jsonValue(c.Value))
+		}
+		return fmt.Sprintf("not common_lib.valid_key(%s, %q)", parent.ref, current.name)
+	case model.IssueTypeRedundantAttribute:
+		if current.index {
+			return fmt.Sprintf("%s == %s", current.ref, jsonValue(c.Value))
+		}
+		return fmt.Sprintf("common_lib.valid_key(%s, %q)", parent.ref, current.name)
+	}
+
+	key := current.ref
+	if _, ok := c.Attr("upper"); ok {
+		key = fmt.Sprintf("upper(%s)", key)
+	}
+	if _, ok := c.Attr("lower"); ok {
+		key = fmt.Sprintf("lower(%s)", key)
+	}
+
+	if reg, ok := c.AttrAsString("regex"); ok {
+		return fmt.Sprintf("re_match(%q, %s)", reg, key)
+	}
+
+	operator := "=="
+	if v, ok := c.AttrAsString("condition"); ok {
+		operator = v
+	}
+
+	value := c.Value
+	if v, ok := c.AttrAsString("val"); ok {
+		// the value is typed as it would be in the example
+		if err := yaml.Unmarshal([]byte(v), &value); err != nil {
+			value = v
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s", key, operator, jsonValue(value))
+}
+
+func yamlResultValues(c build.Condition, keyPath, ref string) (expected, actual string) {
+	switch c.IssueType {
+	case model.IssueTypeMissingAttribute:
+		return fmt.Sprintf("\"'%s' should be defined\"", keyPath), fmt.Sprintf("\"'%s' is undefined\"", keyPath)
+	case model.IssueTypeRedundantAttribute:
+		return fmt.Sprintf("\"'%s' should be undefined\"", keyPath), fmt.Sprintf("\"'%s' is defined\"", keyPath)
+	default:
+		return fmt.Sprintf("\"'%s' should be valid\"", keyPath), fmt.Sprintf("sprintf(\"'%s' is %%v\", [%s])", keyPath, ref)
+	}
+}
+
+// keyPath returns the keys of the condition path, without the resource and the sequence items
+func keyPath(c build.Condition) string {
+	keys := make([]string, 0, len(c.Path))
+	for _, item := range c.Path {
+		if item.Type == build.PathTypeDefault {
+			keys = append(keys, item.Name)
+		}
+	}
+	return strings.Join(keys, ".")
+}
+
+func accessor(key string) string {
+	if identifierRegex.MatchString(key) {
+		return "." + key
+	}
+	return fmt.Sprintf("[%q]", key)
+}
+
+func appendArg(args []string, arg string) []string {
+	result := make([]string, len(args), len(args)+1)
+	copy(result, args)
+	return append(result, arg)
+}
+
+func sprintf(format string, args []string) string {
+	return fmt.Sprintf("sprintf(%q, [%s])", format, strings.Join(args, ", "))
+}
+
+func stringList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func jsonValue(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		log.Warn().Msgf("Can't convert value, %T to rego", value)
+		return "null"
+	}
+	return string(content)
+}
+
+// Metadata returns the metadata.json of a generated query, the description is left to be written
+func Metadata(queryName, severity, category, platform, cloudProvider string) ([]byte, error) {
+	return json.MarshalIndent(QueryMetadata{
+		ID:            uuid.New().String(),
+		QueryName:     queryName,
+		Severity:      severity,
+		Category:      category,
+		Platform:      platform,
+		DescriptionID: strings.ReplaceAll(uuid.New().String(), "-", "")[:8],
+		CloudProvider: cloudProvider,
+	}, "", "  ")
+}
@@ -0,0 +1,115 @@
+package writer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	build "github.com/Checkmarx/kics/pkg/builder/model"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var containerPath = []build.PathItem{
+	{Name: "Pod", Type: build.PathTypeResourceType},
+	{Name: "spec", Type: build.PathTypeDefault},
+	{Name: "containers", Type: build.PathTypeDefault},
+	{Name: "web", Type: build.PathTypeIndex},
+}
+
+// TestYAMLWriterRender tests the functions [YAMLWriter.Render()] and all the methods called by them
+func TestYAMLWriterRender(t *testing.T) {
+	w, err := NewYAMLWriter()
+	require.NoError(t, err)
+
+	t.Run("kubernetes", func(t *testing.T) {
+		content, err := w.Render(build.PlatformKubernetes, []build.Rule{{
+			Conditions: []build.Condition{
+				{
+					IssueType:  model.IssueTypeIncorrectValue,
+					Path:       append(append([]build.PathItem{}, containerPath...), build.PathItem{Name: "image", Type: build.PathTypeDefault}),
+					Value:      "nginx:latest",
+					Attributes: map[string]interface{}{"regex": ".*:latest$"},
+				},
+				{
+					IssueType:  model.IssueTypeMissingAttribute,
+					Path:       append(append([]build.PathItem{}, containerPath...), build.PathItem{Name: "securityContext", Type: build.PathTypeDefault}),
+					Attributes: map[string]interface{}{},
+				},
+			},
+		}})
+		require.NoError(t, err)
+
+		query := string(content)
+		for _, line := range []string{
+			"import data.generic.k8s as k8sLib",
+			`k8sLib.checkKind(document.kind, ["Pod"])`,
+			"specInfo := k8sLib.getSpecInfo(document)",
+			"container := specInfo.spec.containers[_]",
+			`re_match(".*:latest$", container.image)`,
+			`not common_lib.valid_key(container, "securityContext")`,
+			`"searchKey": sprintf("metadata.name={{%s}}.%s.containers.name={{%s}}", [metadata.name, specInfo.path, container.name]),`,
+			`"issueType": "MissingAttribute",`,
+		} {
+			require.Contains(t, query, line)
+		}
+	})
+
+	t.Run("cloudformation", func(t *testing.T) {
+		content, err := w.Render(build.PlatformCloudFormation, []build.Rule{{
+			Conditions: []build.Condition{{
+				IssueType: model.IssueTypeIncorrectValue,
+				Path: []build.PathItem{
+					{Name: "Resources", Type: build.PathTypeResource},
+					{Name: "AWS::S3::Bucket", Type: build.PathTypeResourceType},
+					{Name: "Bucket", Type: build.PathTypeResourceName},
+					{Name: "Properties", Type: build.PathTypeDefault},
+					{Name: "Versioning", Type: build.PathTypeDefault},
+				},
+				Value:      "Enabled",
+				Attributes: map[string]interface{}{"condition": "!=", "val": "Suspended"},
+			}},
+		}})
+		require.NoError(t, err)
+
+		query := string(content)
+		require.Contains(t, query, `resourceTypes := {"AWS::S3::Bucket"}`)
+		require.Contains(t, query, `resource.Properties.Versioning != "Suspended"`)
+		require.Contains(t, query, `"searchKey": sprintf("Resources.%s.Properties.Versioning", [name]),`)
+	})
+
+	t.Run("ansible_any_module", func(t *testing.T) {
+		content, err := w.Render(build.PlatformAnsible, []build.Rule{{
+			Conditions: []build.Condition{{
+				IssueType:  model.IssueTypeRedundantAttribute,
+				Path:       []build.PathItem{{Name: "s3_bucket", Type: build.PathTypeResourceType}, {Name: "acl", Type: build.PathTypeDefault}},
+				Attributes: map[string]interface{}{"resource": "*"},
+			}},
+		}})
+		require.NoError(t, err)
+
+		query := string(content)
+		require.Contains(t, query, "module := task[moduleName]")
+		require.Contains(t, query, `common_lib.valid_key(module, "acl")`)
+		require.Contains(t, query, `"searchKey": sprintf("name={{%s}}.{{%s}}.acl", [task.name, moduleName]),`)
+	})
+
+	t.Run("unsupported_platform", func(t *testing.T) {
+		_, err := w.Render(build.PlatformTerraform, []build.Rule{})
+		require.Error(t, err)
+	})
+}
+
+// TestMetadata tests the function [Metadata()]
+func TestMetadata(t *testing.T) {
+	content, err := Metadata("Generated", "HIGH", "Access Control", build.PlatformKubernetes, "")
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(content), "cloudProvider"))
+
+	var metadata QueryMetadata
+	require.NoError(t, json.Unmarshal(content, &metadata))
+	require.Equal(t, "Generated", metadata.QueryName)
+	require.Equal(t, build.PlatformKubernetes, metadata.Platform)
+	require.Len(t, metadata.ID, 36)
+	require.Len(t, metadata.DescriptionID, 8)
+}
@@ -0,0 +1,216 @@
+// Package coverage aggregates the rego coverage of the queries and libraries evaluated by the inspector
+package coverage
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/cover"
+)
+
+const (
+	// KindQuery is the kind of the query sources
+	KindQuery = "query"
+	// KindLibrary is the kind of the library sources
+	KindLibrary = "library"
+)
+
+// Source is a rego module evaluated by a query, File is the name of the module in the evaluation
+type Source struct {
+	Name    string
+	Kind    string
+	File    string
+	Content string
+}
+
+// Rule is a rule of a source
+type Rule struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+// File is the coverage of a rego source
+type File struct {
+	Name            string        `json:"name"`
+	Kind            string        `json:"kind"`
+	CoveredLines    int           `json:"covered_lines"`
+	NotCoveredLines int           `json:"not_covered_lines"`
+	Coverage        float64       `json:"coverage"`
+	Covered         []cover.Range `json:"covered,omitempty"`
+	NotCovered      []cover.Range `json:"not_covered,omitempty"`
+	RulesNeverTrue  []Rule        `json:"rules_never_true,omitempty"`
+	Content         string        `json:"-"`
+}
+
+// Report is the coverage of the queries and libraries evaluated
+type Report struct {
+	CoveredLines    int     `json:"covered_lines"`
+	NotCoveredLines int     `json:"not_covered_lines"`
+	Coverage        float64 `json:"coverage"`
+	Queries         []File  `json:"queries"`
+	Libraries       []File  `json:"libraries"`
+}
+
+type source struct {
+	kind    string
+	content string
+	module  *ast.Module
+	// lines holds the lines that can be covered, true when they were covered by any evaluation
+	lines map[int]bool
+}
+
+// Collector aggregates the lines covered in each query evaluation
+type Collector struct {
+	mu      sync.Mutex
+	sources map[string]*source
+}
+
+// NewCollector creates an empty collector
+func NewCollector() *Collector {
+	return &Collector{
+		sources: make(map[string]*source),
+	}
+}
+
+// Collect adds the lines of the sources covered by an evaluation traced by cov
+func (c *Collector) Collect(cov *cover.Cover, sources []Source) error {
+	modules := make(map[string]*ast.Module, len(sources))
+	for i := range sources {
+		module, err := c.module(&sources[i])
+		if err != nil {
+			return err
+		}
+		modules[sources[i].File] = module
+	}
+
+	report := cov.Report(modules)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range sources {
+		fileReport, ok := report.Files[sources[i].File]
+		if !ok {
+			continue
+		}
+		lines := c.sources[sources[i].Name].lines
+		for _, r := range fileReport.NotCovered {
+			for row := r.Start.Row; row <= r.End.Row; row++ {
+				if _, ok := lines[row]; !ok {
+					lines[row] = false
+				}
+			}
+		}
+		for _, r := range fileReport.Covered {
+			for row := r.Start.Row; row <= r.End.Row; row++ {
+				lines[row] = true
+			}
+		}
+	}
+	return nil
+}
+
+// module returns the parsed module of the source, parsing it on the first evaluation
+func (c *Collector) module(s *Source) (*ast.Module, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.sources[s.Name]; ok {
+		return cached.module, nil
+	}
+
+	module, err := ast.ParseModule(s.File, s.Content)
+	if err != nil {
+		return nil, err
+	}
+	c.sources[s.Name] = &source{
+		kind:    s.Kind,
+		content: s.Content,
+		module:  module,
+		lines:   make(map[int]bool),
+	}
+	return module, nil
+}
+
+// Report returns the coverage of the sources collected, sorted by name
+func (c *Collector) Report() *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &Report{
+		Queries:   make([]File, 0),
+		Libraries: make([]File, 0),
+	}
+	for name, s := range c.sources {
+		file := s.report(name)
+		report.CoveredLines += file.CoveredLines
+		report.NotCoveredLines += file.NotCoveredLines
+		if s.kind == KindLibrary {
+			report.Libraries = append(report.Libraries, file)
+		} else {
+			report.Queries = append(report.Queries, file)
+		}
+	}
+	report.Coverage = percentage(report.CoveredLines, report.NotCoveredLines)
+
+	sort.Slice(report.Queries, func(i, j int) bool { return report.Queries[i].Name < report.Queries[j].Name })
+	sort.Slice(report.Libraries, func(i, j int) bool { return report.Libraries[i].Name < report.Libraries[j].Name })
+	return report
+}
+
+func (s *source) report(name string) File {
+	file := File{
+		Name:    name,
+		Kind:    s.kind,
+		Content: s.content,
+	}
+
+	rows := make([]int, 0, len(s.lines))
+	for row := range s.lines {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	for _, row := range rows {
+		if s.lines[row] {
+			file.CoveredLines++
+			file.Covered = appendRow(file.Covered, row)
+		} else {
+			file.NotCoveredLines++
+			file.NotCovered = appendRow(file.NotCovered, row)
+		}
+	}
+	file.Coverage = percentage(file.CoveredLines, file.NotCoveredLines)
+
+	// the cover tracer hits the head of a rule when the rule is evaluated as true
+	ast.WalkRules(s.module, func(rule *ast.Rule) bool {
+		if !s.lines[rule.Head.Location.Row] {
+			file.RulesNeverTrue = append(file.RulesNeverTrue, Rule{
+				Name: rule.Head.Ref().String(),
+				Line: rule.Head.Location.Row,
+			})
+		}
+		return false
+	})
+	return file
+}
+
+// appendRow appends the row to the ranges, extending the last range when the row follows it
+func appendRow(ranges []cover.Range, row int) []cover.Range {
+	if last := len(ranges) - 1; last >= 0 && ranges[last].End.Row == row-1 {
+		ranges[last].End.Row = row
+		return ranges
+	}
+	return append(ranges, cover.Range{
+		Start: cover.Position{Row: row},
+		End:   cover.Position{Row: row},
+	})
+}
+
+func percentage(covered, notCovered int) float64 {
+	if covered+notCovered == 0 {
+		return 0
+	}
+	const precision = 100
+	return math.Round(float64(covered)/float64(covered+notCovered)*100*precision) / precision
+}
@@ -0,0 +1,106 @@
+package coverage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cover"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/stretchr/testify/require"
+)
+
+const libraryCode = `package generic.common
+
+is_public(acl) {
+	acl == "public-read"
+}
+
+is_private(acl) {
+	acl == "private"
+}
+`
+
+const queryCode = `package Cx
+
+import data.generic.common as common_lib
+
+CxPolicy[result] {
+	bucket := input.buckets[_]
+	common_lib.is_public(bucket.acl)
+	result := bucket.name
+}
+`
+
+func evaluate(t *testing.T, collector *Collector, input interface{}) {
+	cov := cover.New()
+	query, err := rego.New(
+		rego.Query("result = data.Cx.CxPolicy"),
+		rego.Module("Common", libraryCode),
+		rego.Module("query", queryCode),
+	).PrepareForEval(context.Background())
+	require.NoError(t, err)
+
+	_, err = query.Eval(context.Background(), rego.EvalInput(input), rego.EvalQueryTracer(cov))
+	require.NoError(t, err)
+
+	require.NoError(t, collector.Collect(cov, []Source{
+		{Name: "common", Kind: KindLibrary, File: "Common", Content: libraryCode},
+		{Name: "terraform/query", Kind: KindQuery, File: "query", Content: queryCode},
+	}))
+}
+
+func Test_Collector(t *testing.T) {
+	collector := NewCollector()
+
+	evaluate(t, collector, map[string]interface{}{
+		"buckets": []interface{}{map[string]interface{}{"name": "b", "acl": "private"}},
+	})
+	report := collector.Report()
+	require.Len(t, report.Queries, 1)
+	require.Equal(t, []Rule{{Name: "CxPolicy", Line: 5}}, report.Queries[0].RulesNeverTrue)
+
+	// the lines covered in each evaluation are aggregated
+	evaluate(t, collector, map[string]interface{}{
+		"buckets": []interface{}{map[string]interface{}{"name": "b", "acl": "public-read"}},
+	})
+	report = collector.Report()
+
+	query := report.Queries[0]
+	require.Equal(t, "terraform/query", query.Name)
+	require.Equal(t, KindQuery, query.Kind)
+	require.Empty(t, query.RulesNeverTrue)
+	require.Zero(t, query.NotCoveredLines)
+	require.Equal(t, float64(100), query.Coverage)
+
+	require.Len(t, report.Libraries, 1)
+	library := report.Libraries[0]
+	require.Equal(t, "common", library.Name)
+	require.Equal(t, []Rule{{Name: "is_private", Line: 7}}, library.RulesNeverTrue)
+	require.True(t, inRanges(library.Covered, 4))
+	require.True(t, inRanges(library.NotCovered, 8))
+	require.Less(t, library.Coverage, float64(100))
+
+	require.Equal(t, query.CoveredLines+library.CoveredLines, report.CoveredLines)
+	require.Equal(t, library.NotCoveredLines, report.NotCoveredLines)
+}
+
+func Test_Write(t *testing.T) {
+	collector := NewCollector()
+	evaluate(t, collector, map[string]interface{}{
+		"buckets": []interface{}{map[string]interface{}{"name": "b", "acl": "public-read"}},
+	})
+	report := collector.Report()
+
+	content, err := HTML(report)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `<span class="line covered"><span class="number">4</span>	acl == &#34;public-read&#34;</span>`)
+	require.Contains(t, string(content), `<span class="line not-covered"><span class="number">8</span>	acl == &#34;private&#34;</span>`)
+	require.Contains(t, string(content), `<code>is_private</code> (line 7)`)
+
+	dir := t.TempDir()
+	require.NoError(t, Write(filepath.Join(dir, "coverage.json"), report))
+	require.FileExists(t, filepath.Join(dir, "coverage.json"))
+	require.NoError(t, Write(filepath.Join(dir, "coverage.html"), report))
+	require.FileExists(t, filepath.Join(dir, "coverage.html"))
+}
@@ -0,0 +1,103 @@
+package coverage
+
+import (
+	"bytes"
+	_ "embed" // used for embedding the HTML template
+	"encoding/json"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/open-policy-agent/opa/cover"
+)
+
+var (
+	//go:embed template/coverage.tmpl
+	htmlTemplate string
+)
+
+const htmlExtension = ".html"
+
+type htmlLine struct {
+	Number int
+	Text   string
+	Class  string
+}
+
+type htmlFile struct {
+	File
+	Lines []htmlLine
+}
+
+type htmlReport struct {
+	*Report
+	Version string
+	Files   []htmlFile
+}
+
+// Write writes the report to the path, as an HTML view of the sources when the path has the .html extension
+// and as JSON otherwise
+func Write(path string, report *Report) error {
+	var content []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), htmlExtension) {
+		content, err = HTML(report)
+	} else {
+		content, err = json.MarshalIndent(report, "", "\t")
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), content, os.ModePerm)
+}
+
+// HTML renders the sources of the report with their covered and not covered lines highlighted
+func HTML(report *Report) ([]byte, error) {
+	tmpl, err := template.New("coverage").Parse(htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	data := htmlReport{
+		Report:  report,
+		Version: constants.Version,
+	}
+	for _, files := range [][]File{report.Queries, report.Libraries} {
+		for i := range files {
+			data.Files = append(data.Files, htmlFile{File: files[i], Lines: htmlLines(&files[i])})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func htmlLines(file *File) []htmlLine {
+	lines := strings.Split(strings.ReplaceAll(file.Content, "\r\n", "\n"), "\n")
+	result := make([]htmlLine, 0, len(lines))
+	for i, text := range lines {
+		line := htmlLine{Number: i + 1, Text: text}
+		switch {
+		case inRanges(file.Covered, line.Number):
+			line.Class = "covered"
+		case inRanges(file.NotCovered, line.Number):
+			line.Class = "not-covered"
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+func inRanges(ranges []cover.Range, row int) bool {
+	for _, r := range ranges {
+		if r.In(row) {
+			return true
+		}
+	}
+	return false
+}
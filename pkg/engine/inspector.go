@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/Checkmarx/kics/pkg/detector"
 	"github.com/Checkmarx/kics/pkg/detector/docker"
 	"github.com/Checkmarx/kics/pkg/detector/helm"
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
 	"github.com/Checkmarx/kics/pkg/engine/source"
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/open-policy-agent/opa/ast"
@@ -74,6 +76,7 @@ type Inspector struct {
 
 	enableCoverageReport bool
 	coverageReport       cover.Report
+	queryCoverage        *coverage.Collector
 	queryExecTimeout     time.Duration
 }
 
@@ -290,6 +293,13 @@ func (c *Inspector) EnableCoverageReport() {
 	c.enableCoverageReport = true
 }
 
+// EnableQueryCoverage enables the coverage report and aggregates the coverage of every query evaluation,
+// along with the libraries it uses, in the collector
+func (c *Inspector) EnableQueryCoverage(collector *coverage.Collector) {
+	c.enableCoverageReport = true
+	c.queryCoverage = collector
+}
+
 // GetCoverageReport returns the scan coverage report
 func (c *Inspector) GetCoverageReport() cover.Report {
 	return c.coverageReport
@@ -329,6 +339,12 @@ func (c *Inspector) doRun(ctx *QueryContext) ([]model.Vulnerability, error) {
 		c.coverageReport = cov.Report(map[string]*ast.Module{
 			ctx.Query.Metadata.Query: module,
 		})
+
+		if c.queryCoverage != nil {
+			if err := c.queryCoverage.Collect(cov, c.QueryLoader.coverageSources(&ctx.Query.Metadata)); err != nil {
+				log.Warn().Msgf("Failed to collect coverage of query %s: %s", ctx.Query.Metadata.Query, err)
+			}
+		}
 	}
 
 	log.Trace().
@@ -358,6 +374,7 @@ func (c *Inspector) DecodeQueryResults(ctx *QueryContext, results rego.ResultSet
 
 	vulnerabilities := make([]model.Vulnerability, 0, len(queryResultItems))
 	failedDetectLine := false
+	now := time.Now()
 	for _, queryResultItem := range queryResultItems {
 		vulnerability, err := c.vb(ctx, c.tracker, queryResultItem, c.detector)
 		if err != nil && err.Error() == ErrNoResult.Error() {
@@ -381,7 +398,8 @@ func (c *Inspector) DecodeQueryResults(ctx *QueryContext, results rego.ResultSet
 			continue
 		}
 		file := ctx.Files[vulnerability.FileID]
-		if ShouldSkipVulnerability(file.Commands, vulnerability.QueryID) {
+		skip, suppression := FindSuppression(&file, vulnerability.Line, vulnerability.QueryID, now)
+		if skip {
 			log.Debug().Msgf("Skipping vulnerability in file %s for query '%s':%s", file.FilePath, vulnerability.QueryName, vulnerability.QueryID)
 			continue
 		}
@@ -390,13 +408,18 @@ func (c *Inspector) DecodeQueryResults(ctx *QueryContext, results rego.ResultSet
 			failedDetectLine = true
 		}
 
-		if _, ok := c.excludeResults[vulnerability.SimilarityID]; ok {
+		if checkComment(vulnerability.Line, file.LinesIgnore) {
 			log.Debug().
-				Msgf("Excluding result SimilarityID: %s", vulnerability.SimilarityID)
+				Msgf("Excluding result Comment: %s", vulnerability.SimilarityID)
 			continue
-		} else if checkComment(vulnerability.Line, file.LinesIgnore) {
+		}
+
+		vulnerability.Suppression = suppression
+		MapSourceLine(ctx.BaseScanPaths, &file, vulnerability)
+
+		if _, ok := c.excludeResults[vulnerability.SimilarityID]; ok {
 			log.Debug().
-				Msgf("Excluding result Comment: %s", vulnerability.SimilarityID)
+				Msgf("Excluding result SimilarityID: %s", vulnerability.SimilarityID)
 			continue
 		}
 
@@ -458,6 +481,28 @@ func ShouldSkipVulnerability(command model.CommentsCommands, queryID string) boo
 	return false
 }
 
+// FindSuppression looks up the comments of the file for a vulnerability of the query at the line, it returns true
+// when the comment commands of the file skip the query, otherwise the suppression active at the given time that
+// covers the line, expired suppressions are reported and do not hide the vulnerability
+func FindSuppression(file *model.FileMetadata, line int, queryID string, now time.Time) (bool, *model.Suppression) {
+	if ShouldSkipVulnerability(file.Commands, queryID) {
+		return true, nil
+	}
+	for idx := range file.Suppressions {
+		suppression := &file.Suppressions[idx]
+		if !suppression.Covers(line, queryID) {
+			continue
+		}
+		if suppression.Expired(now) {
+			log.Warn().Msgf("Suppression at line %d of %s expired on %s, owner: %s",
+				suppression.Line, file.FilePath, suppression.Expires, suppression.Owner)
+			continue
+		}
+		return false, suppression
+	}
+	return false, nil
+}
+
 func prepareQueries(queries []model.QueryMetadata, commonLibrary source.RegoLibraries,
 	platformLibraries map[string]source.RegoLibraries, tracker Tracker) QueryLoader {
 	// track queries loaded
@@ -474,6 +519,16 @@ func prepareQueries(queries []model.QueryMetadata, commonLibrary source.RegoLibr
 	}
 }
 
+// coverageSources returns the modules evaluated by the query, named as in the coverage report
+func (q QueryLoader) coverageSources(query *model.QueryMetadata) []coverage.Source {
+	platform := strings.ToLower(query.Platform)
+	return []coverage.Source{
+		{Name: "common", Kind: coverage.KindLibrary, File: "Common", Content: q.commonLibrary.LibraryCode},
+		{Name: platform, Kind: coverage.KindLibrary, File: "Generic", Content: q.platformLibraries[query.Platform].LibraryCode},
+		{Name: path.Join(platform, query.Query), Kind: coverage.KindQuery, File: query.Query, Content: query.Content},
+	}
+}
+
 // LoadQuery loads the query into memory so it can be freed when not used anymore
 func (q QueryLoader) LoadQuery(ctx context.Context, query *model.QueryMetadata) (*rego.PreparedEvalQuery, error) {
 	opaQuery := rego.PreparedEvalQuery{}
@@ -758,6 +758,46 @@ func TestInspector_checkComment(t *testing.T) {
 	}
 }
 
+func TestFindSuppression(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	file := &model.FileMetadata{
+		FilePath: "main.tf",
+		Commands: model.CommentsCommands{"disable": "q3"},
+		Suppressions: []model.Suppression{
+			{Command: model.IgnoreLine, Line: 1, Lines: []int{2}, Queries: []string{"q1"}, Expires: "2026-06-01"},
+			{Command: model.IgnoreLine, Line: 3, Lines: []int{4}, Queries: []string{"q1"}, Reason: "accepted"},
+			{Command: model.IgnoreBlock, Line: 5, Lines: []int{6, 7}, Expires: "2026-06-02"},
+			{Command: model.IgnoreFile, Line: 8, Queries: []string{"q4"}, Reason: "generated"},
+		},
+	}
+	tests := []struct {
+		name     string
+		line     int
+		queryID  string
+		wantSkip bool
+		want     int
+	}{
+		{name: "expired_suppression", line: 2, queryID: "q1", want: -1},
+		{name: "query_scoped_suppression", line: 4, queryID: "q1", want: 1},
+		{name: "other_query", line: 4, queryID: "q2", want: -1},
+		{name: "block_suppression", line: 7, queryID: "q2", want: 2},
+		{name: "not_suppressed", line: 8, queryID: "q1", want: -1},
+		{name: "file_suppression", line: 20, queryID: "q4", want: 3},
+		{name: "disabled_query", line: 4, queryID: "q3", wantSkip: true, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip, got := FindSuppression(file, tt.line, tt.queryID, now)
+			require.Equal(t, tt.wantSkip, skip)
+			if tt.want < 0 {
+				require.Nil(t, got)
+				return
+			}
+			require.Equal(t, &file.Suppressions[tt.want], got)
+		})
+	}
+}
+
 func TestInspector_prepareQueries(t *testing.T) {
 	type args struct {
 		queries           []model.QueryMetadata
@@ -830,3 +870,7 @@ func TestInspector_prepareQueries(t *testing.T) {
 		})
 	}
 }
+
+func (m *mockSource) GetPrompts() ([]model.PromptMetadata, error) {
+	return []model.PromptMetadata{}, nil
+}
@@ -64,3 +64,18 @@ func (mr *MockQueriesSourceMockRecorder) GetQueryLibrary(platform interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryLibrary", reflect.TypeOf((*MockQueriesSource)(nil).GetQueryLibrary), platform)
 }
+
+// GetPrompts mocks base method.
+func (m *MockQueriesSource) GetPrompts() ([]model.PromptMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrompts")
+	ret0, _ := ret[0].([]model.PromptMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrompts indicates an expected call of GetPrompts.
+func (mr *MockQueriesSourceMockRecorder) GetPrompts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrompts", reflect.TypeOf((*MockQueriesSource)(nil).GetPrompts))
+}
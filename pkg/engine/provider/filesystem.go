@@ -12,6 +12,7 @@ import (
 
 	sentryReport "github.com/Checkmarx/kics/internal/sentry"
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/resolver"
 	"github.com/Checkmarx/kics/pkg/utils"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -159,7 +160,7 @@ func (s *FileSystemSourceProvider) walkDir(ctx context.Context, scanPath string,
 			return skipFolder
 		}
 
-		// ------------------ Helm resolver --------------------------------
+		// ------------------ Helm and Ansible resolvers ------------------
 		if info.IsDir() {
 			excluded, errRes := resolverSink(ctx, strings.ReplaceAll(path, "\\", "/"))
 			if errRes != nil {
@@ -172,9 +173,11 @@ func (s *FileSystemSourceProvider) walkDir(ctx context.Context, scanPath string,
 				return nil
 			}
 			if errAdd := s.AddExcluded(excluded); errAdd != nil {
-				log.Err(errAdd).Msgf("Filesystem files provider couldn't exclude rendered Chart files, Chart=%s", info.Name())
+				log.Err(errAdd).Msgf("Filesystem files provider couldn't exclude resolved files, Directory=%s", info.Name())
+			}
+			if resolver.GetKind(path) == model.KindHELM {
+				resolved = true
 			}
-			resolved = true
 			return nil
 		}
 		// -----------------------------------------------------------------
@@ -235,11 +238,14 @@ func (s *FileSystemSourceProvider) checkConditions(info os.FileInfo, extensions
 			log.Info().Msgf("Directory ignored: %s", path)
 			return true, filepath.SkipDir
 		}
-		_, err := os.Stat(filepath.Join(path, "Chart.yaml"))
-		if err != nil || resolved {
+		switch resolver.GetKind(path) {
+		case model.KindHELM:
+			return resolved, nil
+		case model.KindANSIBLE:
+			return false, nil
+		default:
 			return true, nil
 		}
-		return false, nil
 	}
 
 	if f, ok := s.excludes[info.Name()]; ok && containsFile(f, info) {
@@ -50,6 +50,7 @@ type Inspector struct {
 	vulnerabilities       []model.Vulnerability
 	queryExecutionTimeout time.Duration
 	foundLines            []int
+	minConfidence         string
 	mu                    sync.RWMutex
 	SecretTracker         []SecretTracker
 }
@@ -65,9 +66,9 @@ type MultilineResult struct {
 }
 
 type AllowRule struct {
-	Description string `json:"description"`
-	RegexStr    string `json:"regex"`
-	Regex       *regexp.Regexp
+	Description string         `json:"description"`
+	RegexStr    string         `json:"regex"`
+	Regex       *regexp.Regexp `json:"-"`
 }
 
 type RegexQuery struct {
@@ -78,7 +79,8 @@ type RegexQuery struct {
 	SpecialMask string          `json:"specialMask"`
 	Entropies   []Entropy       `json:"entropies"`
 	AllowRules  []AllowRule     `json:"allowRules"`
-	Regex       *regexp.Regexp
+	Samples     *RuleSamples    `json:"samples,omitempty"`
+	Regex       *regexp.Regexp  `json:"-"`
 }
 
 type RegexRuleStruct struct {
@@ -294,6 +296,11 @@ func CompileRegex(allowRules []AllowRule) ([]AllowRule, error) {
 	return allowRules, nil
 }
 
+// SetMinConfidence sets the minimum confidence a detected secret must have to be reported
+func (c *Inspector) SetMinConfidence(minConfidence string) {
+	c.minConfidence = minConfidence
+}
+
 func (c *Inspector) GetQueriesLength() int {
 	return len(c.regexQueries)
 }
@@ -384,6 +391,7 @@ func (c *Inspector) checkFileContent(query *RegexQuery, basePaths []string, file
 				query,
 				lineVuln.lineNumber,
 				lineVuln.lineContent,
+				lineVuln.groups,
 			)
 		}
 
@@ -406,6 +414,7 @@ func (c *Inspector) checkFileContent(query *RegexQuery, basePaths []string, file
 						query,
 						lineVuln.lineNumber,
 						lineVuln.lineContent,
+						lineVuln.groups,
 					)
 				}
 			}
@@ -468,6 +477,7 @@ func (c *Inspector) checkLineByLine(wg *sync.WaitGroup, query *RegexQuery,
 			query,
 			lineNumber,
 			currentLine,
+			groups[0],
 		)
 	}
 
@@ -492,16 +502,25 @@ func (c *Inspector) checkLineByLine(wg *sync.WaitGroup, query *RegexQuery,
 				query,
 				lineNumber,
 				currentLine,
+				groups[0],
 			)
 		}
 	}
 }
 
-func (c *Inspector) addVulnerability(basePaths []string, file *model.FileMetadata, query *RegexQuery, lineNumber int, issueLine string) {
-	if engine.ShouldSkipVulnerability(file.Commands, query.ID) {
+func (c *Inspector) addVulnerability(basePaths []string, file *model.FileMetadata, query *RegexQuery,
+	lineNumber int, issueLine string, groups []string) {
+	skip, suppression := engine.FindSuppression(file, lineNumber+1, query.ID, time.Now())
+	if skip {
 		log.Debug().Msgf("Skipping vulnerability in file %s for query '%s':%s", file.FilePath, query.Name, query.ID)
 		return
 	}
+	secretType, confidence := classifySecret(query, groups)
+	if !meetsMinConfidence(confidence, c.minConfidence) {
+		log.Debug().Msgf("Skipping %s secret with %s confidence in file %s for query '%s':%s",
+			secretType, confidence, file.FilePath, query.Name, query.ID)
+		return
+	}
 	simID, err := similarity.ComputeSimilarityID(
 		basePaths,
 		file.FilePath,
@@ -514,28 +533,33 @@ func (c *Inspector) addVulnerability(basePaths []string, file *model.FileMetadat
 	}
 
 	c.mu.Lock()
-	if _, ok := c.excludeResults[engine.PtrStringToString(simID)]; !ok {
-		linesVuln := c.detector.GetAdjacent(file, lineNumber+1)
-		if !ignoreLine(linesVuln.Line, file.LinesIgnore) {
-			vuln := model.Vulnerability{
-				QueryID:          query.ID,
-				QueryName:        SecretsQueryMetadata["queryName"] + " - " + query.Name,
-				SimilarityID:     engine.PtrStringToString(simID),
-				FileID:           file.ID,
-				FileName:         file.FilePath,
-				Line:             linesVuln.Line,
-				VulnLines:        hideSecret(&linesVuln, issueLine, query, &c.SecretTracker),
-				IssueType:        "RedundantAttribute",
-				Platform:         SecretsQueryMetadata["platform"],
-				Severity:         model.SeverityHigh,
-				QueryURI:         SecretsQueryMetadata["descriptionUrl"],
-				Category:         SecretsQueryMetadata["category"],
-				Description:      SecretsQueryMetadata["descriptionText"],
-				DescriptionID:    SecretsQueryMetadata["descriptionID"],
-				KeyExpectedValue: "Hardcoded secret key should not appear in source",
-				KeyActualValue:   "Hardcoded secret key appears in source",
-				CloudProvider:    SecretsQueryMetadata["cloudProvider"],
-			}
+	linesVuln := c.detector.GetAdjacent(file, lineNumber+1)
+	if !ignoreLine(linesVuln.Line, file.LinesIgnore) {
+		vuln := model.Vulnerability{
+			QueryID:          query.ID,
+			QueryName:        SecretsQueryMetadata["queryName"] + " - " + query.Name,
+			SimilarityID:     engine.PtrStringToString(simID),
+			FileID:           file.ID,
+			FileName:         file.FilePath,
+			Line:             linesVuln.Line,
+			VulnLines:        hideSecret(&linesVuln, issueLine, query, &c.SecretTracker),
+			IssueType:        "RedundantAttribute",
+			Platform:         SecretsQueryMetadata["platform"],
+			Severity:         model.SeverityHigh,
+			QueryURI:         SecretsQueryMetadata["descriptionUrl"],
+			Category:         SecretsQueryMetadata["category"],
+			CWE:              SecretsQueryMetadata["cwe"],
+			Description:      SecretsQueryMetadata["descriptionText"],
+			DescriptionID:    SecretsQueryMetadata["descriptionID"],
+			KeyExpectedValue: "Hardcoded secret key should not appear in source",
+			KeyActualValue:   "Hardcoded secret key appears in source",
+			CloudProvider:    SecretsQueryMetadata["cloudProvider"],
+			SecretType:       secretType,
+			Confidence:       confidence,
+			Suppression:      suppression,
+		}
+		engine.MapSourceLine(basePaths, file, &vuln)
+		if _, ok := c.excludeResults[vuln.SimilarityID]; !ok {
 			c.vulnerabilities = append(c.vulnerabilities, vuln)
 		}
 	}
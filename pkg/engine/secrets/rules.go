@@ -0,0 +1,210 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"regexp/syntax"
+)
+
+var queryIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{12}$`)
+
+// RuleSamples keeps the samples used by 'kics secrets test' to check a regex rule,
+// positive samples must be detected by the rule and negative samples must not
+type RuleSamples struct {
+	Positive []string `json:"positive,omitempty"`
+	Negative []string `json:"negative,omitempty"`
+}
+
+// LoadRulesPacks merges the rules packs files on top of the regex rules content
+func LoadRulesPacks(regexRulesContent string, rulesPacksPaths []string) (RegexRuleStruct, error) {
+	var rules RegexRuleStruct
+	if err := json.Unmarshal([]byte(regexRulesContent), &rules); err != nil {
+		return rules, err
+	}
+	for _, path := range rulesPacksPaths {
+		pack, err := readRulesPack(path)
+		if err != nil {
+			return rules, err
+		}
+		rules = MergeRules(rules, pack)
+	}
+	return rules, nil
+}
+
+func readRulesPack(path string) (RegexRuleStruct, error) {
+	var pack RegexRuleStruct
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return pack, err
+	}
+	if err := json.Unmarshal(content, &pack); err != nil {
+		return pack, fmt.Errorf("failed to parse secrets rules pack %s: %w", path, err)
+	}
+	return pack, nil
+}
+
+// MergeRules merges a rules pack on top of the rules, a pack rule with an existing ID overrides the fields it
+// defines (e.g. only the entropies of a rule can be tuned) and new rules are appended; since several rules
+// can share the same ID, the pack rule name selects which of them is overridden
+func MergeRules(rules, pack RegexRuleStruct) RegexRuleStruct {
+	merged := RegexRuleStruct{
+		Rules:      append(make([]RegexQuery, 0, len(rules.Rules)+len(pack.Rules)), rules.Rules...),
+		AllowRules: append(append(make([]AllowRule, 0), rules.AllowRules...), pack.AllowRules...),
+	}
+	for i := range pack.Rules {
+		positions := overriddenRules(merged.Rules, &pack.Rules[i])
+		if len(positions) == 0 {
+			merged.Rules = append(merged.Rules, pack.Rules[i])
+			continue
+		}
+		for _, position := range positions {
+			overrideRule(&merged.Rules[position], &pack.Rules[i])
+		}
+	}
+	return merged
+}
+
+// overriddenRules returns the positions of the rules overridden by a pack rule
+func overriddenRules(rules []RegexQuery, packRule *RegexQuery) []int {
+	sameID := make([]int, 0)
+	sameName := make([]int, 0)
+	for i := range rules {
+		if rules[i].ID != packRule.ID {
+			continue
+		}
+		sameID = append(sameID, i)
+		if rules[i].Name == packRule.Name {
+			sameName = append(sameName, i)
+		}
+	}
+	if packRule.Name == "" {
+		return sameID
+	}
+	if len(sameName) == 0 && len(sameID) == 1 {
+		return sameID
+	}
+	return sameName
+}
+
+func overrideRule(rule, packRule *RegexQuery) {
+	if packRule.Name != "" {
+		rule.Name = packRule.Name
+	}
+	if packRule.RegexStr != "" {
+		rule.RegexStr = packRule.RegexStr
+	}
+	if packRule.SpecialMask != "" {
+		rule.SpecialMask = packRule.SpecialMask
+	}
+	if packRule.Multiline != (MultilineResult{}) {
+		rule.Multiline = packRule.Multiline
+	}
+	if packRule.Entropies != nil {
+		rule.Entropies = packRule.Entropies
+	}
+	if packRule.Samples != nil {
+		rule.Samples = packRule.Samples
+	}
+	rule.AllowRules = append(append(make([]AllowRule, 0), rule.AllowRules...), packRule.AllowRules...)
+}
+
+// ValidateRules checks the regex rules against the rules schema, returning one error per problem found
+func ValidateRules(rules *RegexRuleStruct) []error {
+	errs := make([]error, 0)
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if !queryIDRegex.MatchString(rule.ID) {
+			errs = append(errs, fmt.Errorf("the query %s defines an invalid query ID (%s)", rule.Name, rule.ID))
+		}
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("the query %s does not define a name", rule.ID))
+		}
+		for _, err := range validateRule(rule) {
+			errs = append(errs, fmt.Errorf("the query %s (%s) %w", rule.Name, rule.ID, err))
+		}
+	}
+	for _, err := range validateAllowRules(rules.AllowRules) {
+		errs = append(errs, fmt.Errorf("the global %w", err))
+	}
+	return errs
+}
+
+func validateRule(rule *RegexQuery) []error {
+	errs := make([]error, 0)
+	if rule.RegexStr == "" {
+		return append(errs, errors.New("does not define a regex"))
+	}
+	compiled, err := regexp.Compile(rule.RegexStr)
+	if err != nil {
+		return append(errs, fmt.Errorf("defines an invalid regex: %w", err))
+	}
+	groups := compiled.NumSubexp()
+	if rule.Multiline.DetectLineGroup < 0 || rule.Multiline.DetectLineGroup > groups {
+		errs = append(errs, fmt.Errorf("defines a multiline detectLineGroup (%d) that is not a group of the regex",
+			rule.Multiline.DetectLineGroup))
+	}
+	for _, entropy := range rule.Entropies {
+		if entropy.Group < 0 || entropy.Group > groups {
+			errs = append(errs, fmt.Errorf("defines an entropy group (%d) that is not a group of the regex", entropy.Group))
+		}
+		if entropy.Min < 0 || entropy.Max < entropy.Min {
+			errs = append(errs, fmt.Errorf("defines an invalid entropy interval [%v, %v]", entropy.Min, entropy.Max))
+		}
+	}
+	if rule.SpecialMask != "" && rule.SpecialMask != "all" {
+		if _, err := regexp.Compile("(.*)" + rule.SpecialMask); err != nil {
+			errs = append(errs, fmt.Errorf("defines an invalid specialMask: %w", err))
+		}
+	}
+	return append(errs, validateAllowRules(rule.AllowRules)...)
+}
+
+func validateAllowRules(allowRules []AllowRule) []error {
+	errs := make([]error, 0)
+	for i := range allowRules {
+		if allowRules[i].RegexStr == "" {
+			errs = append(errs, fmt.Errorf("allow rule '%s' does not define a regex", allowRules[i].Description))
+			continue
+		}
+		if _, err := regexp.Compile(allowRules[i].RegexStr); err != nil {
+			errs = append(errs, fmt.Errorf("allow rule '%s' defines an invalid regex: %w", allowRules[i].Description, err))
+		}
+	}
+	return errs
+}
+
+// hasNestedQuantifiers reports whether the regex repeats an expression that is itself unbounded repeated,
+// e.g. '(a+)+', which is prone to catastrophic backtracking in backtracking regex engines
+func hasNestedQuantifiers(regex string) bool {
+	parsed, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return findNestedQuantifier(parsed, false)
+}
+
+func findNestedQuantifier(re *syntax.Regexp, insideQuantifier bool) bool {
+	unbounded := isUnboundedRepeat(re)
+	if unbounded && insideQuantifier {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if findNestedQuantifier(sub, insideQuantifier || unbounded) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnboundedRepeat(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return true
+	case syntax.OpRepeat:
+		return re.Max == -1
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package secrets
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/assets"
+	"github.com/stretchr/testify/require"
+)
+
+var rulesPacksFixtures = filepath.FromSlash("../../../test/fixtures/test_secrets_rules_packs")
+
+func findRules(rules []RegexQuery, name string) []RegexQuery {
+	found := make([]RegexQuery, 0)
+	for i := range rules {
+		if rules[i].Name == name {
+			found = append(found, rules[i])
+		}
+	}
+	return found
+}
+
+func TestLoadRulesPacks(t *testing.T) {
+	base, err := LoadRulesPacks(assets.SecretsQueryRegexRulesJSON, []string{})
+	require.NoError(t, err)
+
+	rules, err := LoadRulesPacks(assets.SecretsQueryRegexRulesJSON, []string{
+		filepath.Join(rulesPacksFixtures, "acme.json"),
+		filepath.Join(rulesPacksFixtures, "entropy.json"),
+	})
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, len(base.Rules)+1)
+	require.Len(t, rules.AllowRules, len(base.AllowRules)+1)
+
+	acme := findRules(rules.Rules, "Acme API Token")
+	require.Len(t, acme, 1)
+	require.Equal(t, "acme_[a-z0-9]{32}", acme[0].RegexStr)
+	require.Equal(t, []Entropy{{Group: 0, Min: 3.5, Max: 12}}, acme[0].Entropies)
+
+	// only the AWS Access Key rule is overridden by the pack, although the AWS rules share the same ID
+	aws := findRules(rules.Rules, "AWS Access Key")
+	require.Len(t, aws, 1)
+	require.NotNil(t, aws[0].Samples)
+	require.Equal(t, "(A3T[A-Z0-9]|AKIA|ASIA)[A-Z0-9]{16}", aws[0].RegexStr)
+	require.Nil(t, findRules(rules.Rules, "AWS Certificate")[0].Samples)
+
+	_, err = LoadRulesPacks(assets.SecretsQueryRegexRulesJSON, []string{filepath.Join(rulesPacksFixtures, "missing.json")})
+	require.Error(t, err)
+}
+
+func TestMergeRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     RegexRuleStruct
+		pack      RegexRuleStruct
+		wantRules []RegexQuery
+	}{
+		{
+			name: "append_new_rule",
+			rules: RegexRuleStruct{Rules: []RegexQuery{
+				{ID: "a", Name: "A", RegexStr: "a"},
+			}},
+			pack: RegexRuleStruct{Rules: []RegexQuery{
+				{ID: "b", Name: "B", RegexStr: "b"},
+			}},
+			wantRules: []RegexQuery{
+				{ID: "a", Name: "A", RegexStr: "a"},
+				{ID: "b", Name: "B", RegexStr: "b"},
+			},
+		},
+		{
+			name: "override_defined_fields",
+			rules: RegexRuleStruct{Rules: []RegexQuery{
+				{ID: "a", Name: "A", RegexStr: "a", SpecialMask: "all", AllowRules: []AllowRule{{RegexStr: "x"}}},
+			}},
+			pack: RegexRuleStruct{Rules: []RegexQuery{
+				{ID: "a", Entropies: []Entropy{{Group: 0, Min: 1, Max: 2}}, AllowRules: []AllowRule{{RegexStr: "y"}}},
+			}},
+			wantRules: []RegexQuery{
+				{
+					ID:          "a",
+					Name:        "A",
+					RegexStr:    "a",
+					SpecialMask: "all",
+					Entropies:   []Entropy{{Group: 0, Min: 1, Max: 2}},
+					AllowRules:  []AllowRule{{RegexStr: "x"}, {RegexStr: "y"}},
+				},
+			},
+		},
+		{
+			name: "shared_id_with_new_name",
+			rules: RegexRuleStruct{Rules: []RegexQuery{
+				{ID: "a", Name: "A1", RegexStr: "a1"},
+				{ID: "a", Name: "A2", RegexStr: "a2"},
+			}},
+			pack: RegexRuleStruct{Rules: []RegexQuery{
+				{ID: "a", Name: "A3", RegexStr: "a3"},
+			}},
+			wantRules: []RegexQuery{
+				{ID: "a", Name: "A1", RegexStr: "a1"},
+				{ID: "a", Name: "A2", RegexStr: "a2"},
+				{ID: "a", Name: "A3", RegexStr: "a3"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeRules(tt.rules, tt.pack)
+			require.Len(t, got.Rules, len(tt.wantRules))
+			for i := range tt.wantRules {
+				require.Equal(t, tt.wantRules[i].ID, got.Rules[i].ID)
+				require.Equal(t, tt.wantRules[i].Name, got.Rules[i].Name)
+				require.Equal(t, tt.wantRules[i].RegexStr, got.Rules[i].RegexStr)
+				require.Equal(t, tt.wantRules[i].SpecialMask, got.Rules[i].SpecialMask)
+				require.Equal(t, tt.wantRules[i].Entropies, got.Rules[i].Entropies)
+				require.Len(t, got.Rules[i].AllowRules, len(tt.wantRules[i].AllowRules))
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	rules, err := LoadRulesPacks(assets.SecretsQueryRegexRulesJSON, []string{})
+	require.NoError(t, err)
+	require.Empty(t, ValidateRules(&rules))
+
+	invalid, err := LoadRulesPacks(`{"rules": []}`, []string{filepath.Join(rulesPacksFixtures, "invalid.json")})
+	require.NoError(t, err)
+	errs := ValidateRules(&invalid)
+	require.Len(t, errs, 5)
+	require.Contains(t, errs[0].Error(), "invalid query ID")
+	require.Contains(t, errs[1].Error(), "detectLineGroup")
+	require.Contains(t, errs[2].Error(), "entropy group")
+	require.Contains(t, errs[3].Error(), "entropy interval")
+	require.Contains(t, errs[4].Error(), "allow rule")
+}
+
+func TestHasNestedQuantifiers(t *testing.T) {
+	tests := []struct {
+		regex string
+		want  bool
+	}{
+		{regex: `acme_[a-z0-9]{32}`, want: false},
+		{regex: `(a|b)+c`, want: false},
+		{regex: `(a+)+b`, want: true},
+		{regex: `(\w+\s*)*=`, want: true},
+		{regex: `([a-z]{2,})+`, want: true},
+		{regex: `([a-z]{2,4})+`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			require.Equal(t, tt.want, hasNestedQuantifiers(tt.regex))
+		})
+	}
+}
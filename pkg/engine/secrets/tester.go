@@ -0,0 +1,174 @@
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RuleTestResult keeps the outcome of testing a regex rule, errors make the rule fail
+// while warnings point to rules that may need to be reviewed
+type RuleTestResult struct {
+	ID       string
+	Name     string
+	Errors   []string
+	Warnings []string
+}
+
+// Passed returns true when the rule has no errors
+func (r *RuleTestResult) Passed() bool {
+	return len(r.Errors) == 0
+}
+
+// TestRulesPacks merges the rules packs on top of the regex rules content and tests the rules defined by the packs,
+// when no rules pack is given all the rules are tested
+func TestRulesPacks(regexRulesContent string, rulesPacksPaths []string) ([]RuleTestResult, []error) {
+	rules, err := LoadRulesPacks(regexRulesContent, rulesPacksPaths)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if len(rulesPacksPaths) == 0 {
+		return TestRules(&rules)
+	}
+	selected := make(map[int]bool)
+	for _, path := range rulesPacksPaths {
+		pack, err := readRulesPack(path)
+		if err != nil {
+			return nil, []error{err}
+		}
+		for i := range pack.Rules {
+			for _, position := range overriddenRules(rules.Rules, &pack.Rules[i]) {
+				selected[position] = true
+			}
+		}
+	}
+	return testRules(&rules, selected)
+}
+
+// TestRules validates the rules and runs the samples of each rule
+func TestRules(rules *RegexRuleStruct) ([]RuleTestResult, []error) {
+	selected := make(map[int]bool, len(rules.Rules))
+	for i := range rules.Rules {
+		selected[i] = true
+	}
+	return testRules(rules, selected)
+}
+
+// testRules runs the samples of the selected rules, all the rules are used to find overlapping matches
+func testRules(rules *RegexRuleStruct, selected map[int]bool) ([]RuleTestResult, []error) {
+	if errs := ValidateRules(rules); len(errs) > 0 {
+		return nil, errs
+	}
+
+	compiled := make([]RegexQuery, len(rules.Rules))
+	copy(compiled, rules.Rules)
+	for i := range compiled {
+		compiled[i].Regex = regexp.MustCompile(compiled[i].RegexStr)
+		compiled[i].AllowRules = append(make([]AllowRule, 0), compiled[i].AllowRules...)
+		for j := range compiled[i].AllowRules {
+			compiled[i].AllowRules[j].Regex = regexp.MustCompile(compiled[i].AllowRules[j].RegexStr)
+		}
+	}
+	allowRules, err := CompileRegex(append(make([]AllowRule, 0), rules.AllowRules...))
+	if err != nil {
+		return nil, []error{err}
+	}
+	inspector := &Inspector{allowRules: allowRules}
+
+	results := make([]RuleTestResult, 0, len(selected))
+	for i := range compiled {
+		if selected[i] {
+			results = append(results, inspector.testRule(compiled, i))
+		}
+	}
+	return results, nil
+}
+
+func (c *Inspector) testRule(rules []RegexQuery, idx int) RuleTestResult {
+	rule := &rules[idx]
+	result := RuleTestResult{
+		ID:       rule.ID,
+		Name:     rule.Name,
+		Errors:   make([]string, 0),
+		Warnings: make([]string, 0),
+	}
+
+	if hasNestedQuantifiers(rule.RegexStr) {
+		result.Warnings = append(result.Warnings,
+			"regex has nested unbounded quantifiers, which risk catastrophic backtracking in backtracking regex engines")
+	}
+
+	if rule.Samples == nil || len(rule.Samples.Positive) == 0 {
+		result.Warnings = append(result.Warnings, "rule has no positive samples, it was not tested")
+	}
+	if rule.Samples == nil {
+		return result
+	}
+
+	matched := 0
+	for _, sample := range rule.Samples.Positive {
+		if !c.detects(rule, sample) {
+			result.Errors = append(result.Errors, fmt.Sprintf("positive sample not detected: %s", sample))
+			continue
+		}
+		matched++
+		overlaps := make([]string, 0)
+		for i := range rules {
+			if i != idx && c.detects(&rules[i], sample) {
+				overlaps = append(overlaps, fmt.Sprintf("'%s' (%s)", rules[i].Name, rules[i].ID))
+			}
+		}
+		if len(overlaps) > 0 {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("positive sample also detected by %s: %s", strings.Join(overlaps, ", "), sample))
+		}
+	}
+	if len(rule.Samples.Positive) > 0 && matched == 0 {
+		result.Errors = append(result.Errors, "rule matches none of its positive samples")
+	}
+
+	for _, sample := range rule.Samples.Negative {
+		if c.detects(rule, sample) {
+			result.Errors = append(result.Errors, fmt.Sprintf("negative sample detected: %s", sample))
+		}
+	}
+	return result
+}
+
+// detects checks a sample the same way the inspector checks the scanned files,
+// line by line for single line rules and as a whole for multiline rules
+func (c *Inspector) detects(rule *RegexQuery, sample string) bool {
+	if rule.Multiline != (MultilineResult{}) {
+		return c.detectsText(rule, sample, true)
+	}
+	for _, line := range strings.Split(sample, "\n") {
+		if c.detectsText(rule, line, false) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Inspector) detectsText(rule *RegexQuery, text string, multiline bool) bool {
+	isSecret, groups := c.isSecret(text, rule)
+	if !isSecret {
+		return false
+	}
+	if len(rule.Entropies) == 0 {
+		return true
+	}
+	if !multiline {
+		groups = groups[:1]
+	}
+	for _, group := range groups {
+		for _, entropy := range rule.Entropies {
+			if len(group) <= entropy.Group {
+				continue
+			}
+			if isMatch, _ := CheckEntropyInterval(entropy, group[entropy.Group]); isMatch {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/assets"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRulesPacks(t *testing.T) {
+	results, errs := TestRulesPacks(assets.SecretsQueryRegexRulesJSON, []string{
+		filepath.Join(rulesPacksFixtures, "acme.json"),
+		filepath.Join(rulesPacksFixtures, "entropy.json"),
+	})
+	require.Empty(t, errs)
+	require.Len(t, results, 2)
+	for i := range results {
+		require.True(t, results[i].Passed(), "rule %s failed: %v", results[i].Name, results[i].Errors)
+	}
+
+	_, errs = TestRulesPacks(assets.SecretsQueryRegexRulesJSON, []string{filepath.Join(rulesPacksFixtures, "invalid.json")})
+	require.NotEmpty(t, errs)
+}
+
+func TestTestRules(t *testing.T) {
+	rules := RegexRuleStruct{
+		Rules: []RegexQuery{
+			{
+				ID:       "9d9a2c1e-4f4b-4c11-8d8e-2b7a6f0e1c55",
+				Name:     "Acme API Token",
+				RegexStr: `acme_[a-z0-9]{32}`,
+				Samples: &RuleSamples{
+					Positive: []string{"token: acme_6f1d2c8a9b3e4f5d6a7b8c9d0e1f2a3b", "token: acme_00000000000000000000000000000000"},
+					Negative: []string{"token: acme_6f1d2c8a9b3e4f5d6a7b8c9d0e1f2a3c"},
+				},
+			},
+			{
+				ID:       "9d9a2c1e-4f4b-4c11-8d8e-2b7a6f0e1c56",
+				Name:     "Generic Token",
+				RegexStr: `(token: )((\w+)+)`,
+				Samples: &RuleSamples{
+					Positive: []string{"unrelated"},
+				},
+			},
+			{
+				ID:       "9d9a2c1e-4f4b-4c11-8d8e-2b7a6f0e1c57",
+				Name:     "High Entropy Key",
+				RegexStr: `key: (\w+)`,
+				Entropies: []Entropy{
+					{Group: 1, Min: 3.5, Max: 12},
+				},
+				Samples: &RuleSamples{
+					Positive: []string{"key: 6f1d2c8a9b3e4f5d6a7b8c9d0e1f2a3b"},
+					Negative: []string{"key: aaaaaaaaaaaaaaaa"},
+				},
+			},
+		},
+		AllowRules: []AllowRule{
+			{Description: "Avoiding sandbox tokens", RegexStr: `acme_0{32}`},
+		},
+	}
+
+	results, errs := TestRules(&rules)
+	require.Empty(t, errs)
+	require.Len(t, results, 3)
+
+	acme := results[0]
+	require.False(t, acme.Passed())
+	require.Equal(t, []string{
+		"positive sample not detected: token: acme_00000000000000000000000000000000",
+		"negative sample detected: token: acme_6f1d2c8a9b3e4f5d6a7b8c9d0e1f2a3c",
+	}, acme.Errors)
+	require.Len(t, acme.Warnings, 1)
+	require.Contains(t, acme.Warnings[0], "also detected by 'Generic Token'")
+
+	generic := results[1]
+	require.False(t, generic.Passed())
+	require.Contains(t, generic.Errors, "rule matches none of its positive samples")
+	require.Contains(t, generic.Warnings[0], "catastrophic backtracking")
+
+	require.True(t, results[2].Passed(), "%v", results[2].Errors)
+}
@@ -0,0 +1,246 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"hash/crc32"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// Secret types assigned to the detected secrets
+const (
+	SecretTypeGithubToken            = "github-token"
+	SecretTypeGithubFineGrainedToken = "github-fine-grained-token"
+	SecretTypeAWSAccessKeyID         = "aws-access-key-id"
+	SecretTypeAWSResourceID          = "aws-resource-id"
+	SecretTypeJWT                    = "jwt"
+	SecretTypePrivateKey             = "private-key"
+	SecretTypePlaceholder            = "placeholder"
+	SecretTypeGeneric                = "generic"
+)
+
+const (
+	base62Chars          = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	githubChecksumLength = 6
+	githubRandomStart    = 4
+	minRepeatedLength    = 4
+	minPGPKeyLength      = 200
+)
+
+var (
+	githubTokenRgx            = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36})\b`)
+	githubFineGrainedTokenRgx = regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9]{22}_[A-Za-z0-9]{59}\b`)
+	awsKeyIDRgx               = regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA|AGPA|AIDA|AIPA|ANPA|ANVA|AROA|APKA|ASCA)([A-Z0-9]{16})\b`)
+	awsKeyIDBodyRgx           = regexp.MustCompile(`^[A-Z2-7]{16}$`)
+	jwtRgx                    = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`)
+	privateKeyRgx             = regexp.MustCompile(`(?s)-----BEGIN ([A-Z ]*)PRIVATE KEY( BLOCK)?-----.*?-----END ([A-Z ]*)PRIVATE KEY( BLOCK)?-----`)
+
+	// awsCredentialsPrefixes are the key ID prefixes of credentials, the remaining prefixes identify resources
+	awsCredentialsPrefixes = []string{"AKIA", "ASIA", "A3T"}
+
+	// placeholderValues are common values used as examples or defaults instead of real secrets
+	placeholderValues = []string{
+		"admin", "changeit", "changeme", "change_me", "default", "dummy", "empty", "example", "fake", "fixme",
+		"letmein", "mypassword", "none", "null", "pass", "passw0rd", "password", "password1", "password123",
+		"qwerty", "redacted", "root", "sample", "secret", "test", "testing", "todo", "undefined",
+		"123456", "12345678", "123456789", "1234567890", "abcdef", "abc123",
+	}
+	// placeholderFragments are parts of values that identify examples, templates or references to other values
+	placeholderFragments = []string{
+		"example", "placeholder", "changeme", "change_me", "change-me", "dummy", "redacted", "sample", "your_",
+		"your-", "yourpassword", "yoursecret", "insert", "replace", "<", "${", "{{", "$(", "%(", "xxxx", "****",
+	}
+
+	now = time.Now
+)
+
+// classifySecret classifies a secret matched by a regex rule using offline verifiable formats,
+// returning its type and the confidence that it is a real secret
+func classifySecret(query *RegexQuery, groups []string) (secretType, confidence string) {
+	if len(groups) == 0 {
+		return SecretTypeGeneric, model.SecretConfidenceMedium
+	}
+	match := groups[0]
+
+	if key := privateKeyRgx.FindString(match); key != "" {
+		return SecretTypePrivateKey, classifyPrivateKey(key)
+	}
+	if token := jwtRgx.FindString(match); token != "" {
+		return SecretTypeJWT, classifyJWT(token)
+	}
+	if token := githubTokenRgx.FindString(match); token != "" {
+		if isValidGithubChecksum(token) {
+			return SecretTypeGithubToken, model.SecretConfidenceHigh
+		}
+		return SecretTypeGithubToken, model.SecretConfidenceLow
+	}
+	if githubFineGrainedTokenRgx.MatchString(match) {
+		return SecretTypeGithubFineGrainedToken, model.SecretConfidenceMedium
+	}
+	if keyID := awsKeyIDRgx.FindStringSubmatch(match); keyID != nil {
+		return classifyAWSKeyID(keyID[1], keyID[2])
+	}
+
+	if isPlaceholder(secretValue(query, groups)) {
+		return SecretTypePlaceholder, model.SecretConfidenceLow
+	}
+	return SecretTypeGeneric, model.SecretConfidenceMedium
+}
+
+// secretValue returns the value part of the match, without the key that precedes it
+func secretValue(query *RegexQuery, groups []string) string {
+	value := groups[0]
+	switch {
+	case len(query.Entropies) > 0 && len(groups) > query.Entropies[0].Group:
+		value = groups[query.Entropies[0].Group]
+	case query.SpecialMask != "" && query.SpecialMask != "all":
+		if re, err := regexp.Compile("(.*)" + query.SpecialMask); err == nil {
+			value = value[len(re.FindString(value)):]
+		}
+	}
+	return strings.Trim(strings.TrimSpace(value), `"'`+"`")
+}
+
+// isValidGithubChecksum verifies the CRC32 checksum that GitHub appends, base62 encoded, to its tokens
+func isValidGithubChecksum(token string) bool {
+	random := token[githubRandomStart : len(token)-githubChecksumLength]
+	checksum := crc32.ChecksumIEEE([]byte(random))
+
+	encoded := make([]byte, githubChecksumLength)
+	for i := githubChecksumLength - 1; i >= 0; i-- {
+		encoded[i] = base62Chars[checksum%uint32(len(base62Chars))]
+		checksum /= uint32(len(base62Chars))
+	}
+	return string(encoded) == token[len(token)-githubChecksumLength:]
+}
+
+// classifyAWSKeyID checks the key ID prefix and its base32 body, AWS documentation keys end with 'EXAMPLE'
+func classifyAWSKeyID(prefix, body string) (secretType, confidence string) {
+	if strings.HasSuffix(body, "EXAMPLE") || !awsKeyIDBodyRgx.MatchString(body) {
+		return SecretTypeAWSAccessKeyID, model.SecretConfidenceLow
+	}
+	for _, credentialsPrefix := range awsCredentialsPrefixes {
+		if strings.HasPrefix(prefix, credentialsPrefix) {
+			return SecretTypeAWSAccessKeyID, model.SecretConfidenceHigh
+		}
+	}
+	return SecretTypeAWSResourceID, model.SecretConfidenceLow
+}
+
+// classifyJWT checks the token structure, unsigned and expired tokens are less likely to be usable
+func classifyJWT(token string) string {
+	parts := strings.Split(token, ".")
+	header := make(map[string]interface{})
+	claims := make(map[string]interface{})
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return model.SecretConfidenceLow
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return model.SecretConfidenceLow
+	}
+	alg, _ := header["alg"].(string)
+	if alg == "" {
+		return model.SecretConfidenceLow
+	}
+	if exp, ok := claims["exp"].(float64); ok && time.Unix(int64(exp), 0).Before(now()) {
+		return model.SecretConfidenceLow
+	}
+	if strings.EqualFold(alg, "none") || parts[2] == "" {
+		return model.SecretConfidenceMedium
+	}
+	return model.SecretConfidenceHigh
+}
+
+func decodeJWTPart(part string, value interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, value)
+}
+
+// classifyPrivateKey checks that the key is a well formed PEM block holding a parsable private key
+func classifyPrivateKey(key string) string {
+	lines := strings.Split(strings.ReplaceAll(key, `\n`, "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.Trim(strings.TrimSpace(lines[i]), `"',`)
+	}
+	normalized := strings.Join(lines, "\n")
+
+	if strings.Contains(normalized, "PGP PRIVATE KEY BLOCK") {
+		// PGP armored keys are not PEM encoded, only their size is checked
+		if len(normalized) > minPGPKeyLength {
+			return model.SecretConfidenceMedium
+		}
+		return model.SecretConfidenceLow
+	}
+
+	block, _ := pem.Decode([]byte(normalized))
+	if block == nil || len(block.Bytes) == 0 {
+		return model.SecretConfidenceLow
+	}
+	if _, ok := block.Headers["Proc-Type"]; ok || block.Type == "ENCRYPTED PRIVATE KEY" {
+		// encrypted keys can not be parsed without the passphrase
+		return model.SecretConfidenceMedium
+	}
+	if isParsablePrivateKey(block) {
+		return model.SecretConfidenceHigh
+	}
+	return model.SecretConfidenceLow
+}
+
+func isParsablePrivateKey(block *pem.Block) bool {
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		return bytes.HasPrefix(block.Bytes, []byte("openssh-key-v1\x00"))
+	case "RSA PRIVATE KEY":
+		_, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		return err == nil
+	case "EC PRIVATE KEY":
+		_, err := x509.ParseECPrivateKey(block.Bytes)
+		return err == nil
+	default:
+		_, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		return err == nil
+	}
+}
+
+// isPlaceholder checks the value against known placeholder values and patterns
+func isPlaceholder(value string) bool {
+	lower := strings.ToLower(value)
+	if lower == "" {
+		return true
+	}
+	for _, placeholder := range placeholderValues {
+		if lower == placeholder {
+			return true
+		}
+	}
+	for _, fragment := range placeholderFragments {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return len(lower) >= minRepeatedLength && strings.Count(lower, lower[:1]) == len(lower)
+}
+
+// meetsMinConfidence returns true when the confidence is equal or higher than the minimum confidence
+func meetsMinConfidence(confidence, minConfidence string) bool {
+	return confidenceLevel(confidence) >= confidenceLevel(minConfidence)
+}
+
+func confidenceLevel(confidence string) int {
+	for i, level := range model.AllSecretConfidences {
+		if strings.EqualFold(level, confidence) {
+			return i
+		}
+	}
+	return 0
+}
@@ -177,6 +177,8 @@ var DefaultVulnerabilityBuilder = func(ctx *QueryContext, tracker Tracker,
 		QueryID:          queryID,
 		QueryURI:         getStringFromMap("descriptionUrl", DefaultQueryURI, overrideKey, vObj, &logWithFields),
 		Category:         getStringFromMap("category", "", overrideKey, vObj, &logWithFields),
+		CWE:              PtrStringToString(mustMapKeyToString(vObj, "cwe")),
+		Exposure:         PtrStringToString(mustMapKeyToString(vObj, "exposure")),
 		Description:      getStringFromMap("descriptionText", "", overrideKey, vObj, &logWithFields),
 		DescriptionID:    getStringFromMap("descriptionID", DefaultQueryDescriptionID, overrideKey, vObj, &logWithFields),
 		Severity:         severity,
@@ -196,6 +198,7 @@ var DefaultVulnerabilityBuilder = func(ctx *QueryContext, tracker Tracker,
 		CloudProvider:    getCloudProvider(platform, overrideKey, vObj, &logWithFields),
 		Remediation:      PtrStringToString(mustMapKeyToString(vObj, "remediation")),
 		RemediationType:  PtrStringToString(mustMapKeyToString(vObj, "remediationType")),
+		ResolvedFrom:     resolvedFrom(&file, linesVulne.ResolvedFile),
 	}, nil
 }
 
@@ -3,9 +3,12 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	dec "github.com/Checkmarx/kics/pkg/detector"
+	"github.com/Checkmarx/kics/pkg/engine/similarity"
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/utils"
 	"github.com/rs/zerolog"
@@ -38,6 +41,78 @@ func (s *searchLineCalculator) calculate() {
 		}
 	}
 }
+
+// MapSourceLine moves a result of a file inlining other files, like an Ansible playbook with its included tasks,
+// to the file and line it was inlined from, keeping only the adjacent lines of the same file
+func MapSourceLine(basePaths []string, file *model.FileMetadata, vulnerability *model.Vulnerability) {
+	if vulnerability.Line < 1 || vulnerability.Line > len(file.SourceLines) {
+		return
+	}
+	source := file.SourceLines[vulnerability.Line-1]
+	if vulnerability.VulnLines != nil {
+		vulnLines := make([]model.CodeLine, 0, len(*vulnerability.VulnLines))
+		for _, codeLine := range *vulnerability.VulnLines {
+			if codeLine.Position < 1 || codeLine.Position > len(file.SourceLines) {
+				continue
+			}
+			adjacent := file.SourceLines[codeLine.Position-1]
+			if adjacent.FilePath != source.FilePath || adjacent.Line-source.Line != codeLine.Position-vulnerability.Line {
+				continue
+			}
+			line := codeLine.Line
+			if len(line) >= adjacent.Indent {
+				line = line[adjacent.Indent:]
+			}
+			vulnLines = append(vulnLines, model.CodeLine{Line: line, Position: adjacent.Line})
+		}
+		vulnerability.VulnLines = &vulnLines
+	}
+	if vulnerability.SearchLine > 0 && vulnerability.SearchLine <= len(file.SourceLines) {
+		// the search line is only kept when it is in the same file as the result line
+		searchLine := file.SourceLines[vulnerability.SearchLine-1]
+		vulnerability.SearchLine = -1
+		if searchLine.FilePath == source.FilePath {
+			vulnerability.SearchLine = searchLine.Line
+		}
+	}
+	vulnerability.Line = source.Line
+	if source.FilePath != file.FilePath {
+		vulnerability.FileName = source.FilePath
+		vulnerability.ResolvedFrom = file.FilePath
+		// the result of an included file has the same similarity ID whatever the file including it
+		similarityID, err := similarity.ComputeSimilarityID(basePaths, source.FilePath, vulnerability.QueryID,
+			strconv.Itoa(source.Line), vulnerability.SearchValue)
+		if err != nil {
+			log.Err(err).Msgf("Failed to compute similarity ID of %s", source.FilePath)
+			return
+		}
+		vulnerability.SimilarityID = PtrStringToString(similarityID)
+	}
+}
+
+// resolvedFrom returns the file the result file was resolved from: the file referencing the result file
+// when the line was found in a resolved reference, or the values of the chart of a rendered Helm template
+func resolvedFrom(file *model.FileMetadata, resultFile string) string {
+	if resultFile != "" && resultFile != file.FilePath {
+		return file.FilePath
+	}
+	if file.Kind != model.KindHELM {
+		return ""
+	}
+	for dir := filepath.Dir(file.FilePath); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "Chart.yaml")); err == nil {
+			values := filepath.Join(dir, "values.yaml")
+			if _, err := os.Stat(values); err != nil {
+				return ""
+			}
+			return values
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
 func mergeWithMetadata(base, additional map[string]interface{}) map[string]interface{} {
 	for k, v := range additional {
 		if _, ok := base[k]; ok {
@@ -49,7 +124,7 @@ func mergeWithMetadata(base, additional map[string]interface{}) map[string]inter
 }
 func mustMapKeyToString(m map[string]interface{}, key string) *string {
 	res, err := mapKeyToString(m, key, true)
-	excludedFields := []string{"value", "resourceName", "resourceType", "remediation", "remediationType"}
+	excludedFields := []string{"value", "resourceName", "resourceType", "remediation", "remediationType", "cwe", "exposure"}
 	if err != nil && !utils.Contains(key, excludedFields) {
 		log.Warn().
 			Str("reason", err.Error()).
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -170,3 +171,74 @@ func Test_PtrStringToString(t *testing.T) {
 		})
 	}
 }
+
+// TestMapSourceLine tests the function [MapSourceLine()]
+func TestMapSourceLine(t *testing.T) {
+	file := &model.FileMetadata{
+		FilePath: "site.yml",
+		SourceLines: []model.SourceLine{
+			{FilePath: "site.yml", Line: 1},
+			{FilePath: "site.yml", Line: 2},
+			{FilePath: "site.yml", Line: 2},
+			{FilePath: "extra.yml", Line: 1, Indent: 4},
+			{FilePath: "extra.yml", Line: 2, Indent: 4},
+		},
+	}
+	vulnerability := &model.Vulnerability{
+		FileName: "site.yml",
+		Line:     5,
+		VulnLines: &[]model.CodeLine{
+			{Position: 3, Line: "  block:"},
+			{Position: 4, Line: "    - name: install nginx"},
+			{Position: 5, Line: "      yum: nginx"},
+		},
+	}
+	MapSourceLine([]string{"."}, file, vulnerability)
+	require.Equal(t, "extra.yml", vulnerability.FileName)
+	require.Equal(t, "site.yml", vulnerability.ResolvedFrom)
+	require.Equal(t, 2, vulnerability.Line)
+	require.Equal(t, []model.CodeLine{
+		{Position: 1, Line: "- name: install nginx"},
+		{Position: 2, Line: "  yum: nginx"},
+	}, *vulnerability.VulnLines)
+
+	vulnerability = &model.Vulnerability{FileName: "site.yml", Line: 2, SimilarityID: "site"}
+	MapSourceLine([]string{"."}, file, vulnerability)
+	require.Equal(t, "site.yml", vulnerability.FileName)
+	require.Empty(t, vulnerability.ResolvedFrom)
+	require.Equal(t, 2, vulnerability.Line)
+	require.Equal(t, "site", vulnerability.SimilarityID)
+}
+
+// TestMapSourceLine_IncludedFile tests the function [MapSourceLine()] with a file included by two playbooks
+func TestMapSourceLine_IncludedFile(t *testing.T) {
+	site := &model.FileMetadata{
+		FilePath: "site.yml",
+		SourceLines: []model.SourceLine{
+			{FilePath: "site.yml", Line: 1},
+			{FilePath: "extra.yml", Line: 1, Indent: 4},
+			{FilePath: "extra.yml", Line: 2, Indent: 4},
+		},
+	}
+	other := &model.FileMetadata{
+		FilePath: "other.yml",
+		SourceLines: []model.SourceLine{
+			{FilePath: "other.yml", Line: 1},
+			{FilePath: "other.yml", Line: 2},
+			{FilePath: "extra.yml", Line: 1, Indent: 6},
+			{FilePath: "extra.yml", Line: 2, Indent: 6},
+		},
+	}
+
+	fromSite := &model.Vulnerability{QueryID: "query", SimilarityID: "site", Line: 3, SearchLine: 2}
+	MapSourceLine([]string{"."}, site, fromSite)
+	fromOther := &model.Vulnerability{QueryID: "query", SimilarityID: "other", Line: 4, SearchLine: 2}
+	MapSourceLine([]string{"."}, other, fromOther)
+
+	require.Equal(t, "extra.yml", fromSite.FileName)
+	require.Equal(t, "extra.yml", fromOther.FileName)
+	require.NotEqual(t, "site", fromSite.SimilarityID)
+	require.Equal(t, fromSite.SimilarityID, fromOther.SimilarityID)
+	require.Equal(t, 1, fromSite.SearchLine)
+	require.Equal(t, -1, fromOther.SearchLine)
+}
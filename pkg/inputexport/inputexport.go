@@ -0,0 +1,136 @@
+// Package inputexport writes the rego input of the queries of each platform with the KICS libraries,
+// as a bundle to test policies with opa test and conftest
+package inputexport
+
+import (
+	_ "embed" // Embed the input schema
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/scan"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SchemaFile is the JSON schema of the inputs
+	SchemaFile = "input.schema.json"
+	// BundleDir is the directory loaded by opa test, with the inputs, the libraries and their data
+	BundleDir = "bundle"
+	// InputFile is the name of the input files
+	InputFile = "input.json"
+	testFile  = "kics_input_test.rego"
+	common    = "common"
+)
+
+var (
+	//go:embed schema/input.schema.json
+	inputSchema []byte
+)
+
+// Write writes the inputs and the libraries of their platforms to the output directory:
+//
+//	input.schema.json                              the JSON schema of the inputs
+//	bundle/inputs/<platform>/<kind>/input.json     the input of the queries of the platform for the files of the kind
+//	bundle/lib/<library>.rego                      the common library and the libraries of the platforms
+//	bundle/data.json                               the input data of the libraries
+//	bundle/kics_input_test.rego                    a test for each input, loaded as data.inputs.<platform>.<kind>
+func Write(outputDir, librariesPath string, inputs []scan.PlatformInput) error {
+	bundleDir := filepath.Join(outputDir, BundleDir)
+	if err := os.MkdirAll(filepath.Join(bundleDir, "lib"), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, SchemaFile), inputSchema, os.ModePerm); err != nil {
+		return err
+	}
+
+	libraries := []string{common}
+	for i := range inputs {
+		content, err := json.MarshalIndent(inputs[i].Input, "", "  ")
+		if err != nil {
+			return err
+		}
+		inputDir := filepath.Join(bundleDir, "inputs", inputPath(&inputs[i]))
+		if err := os.MkdirAll(inputDir, os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(inputDir, InputFile), content, os.ModePerm); err != nil {
+			return err
+		}
+		if !contains(libraries, inputs[i].Library) {
+			libraries = append(libraries, inputs[i].Library)
+		}
+	}
+
+	if err := writeLibraries(bundleDir, librariesPath, libraries); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(bundleDir, testFile), []byte(testModule(inputs)), os.ModePerm)
+}
+
+// inputPath returns the path of the input in the bundle, also its path in data
+func inputPath(input *scan.PlatformInput) string {
+	return filepath.Join(strings.ToLower(input.Platform), strings.ToLower(string(input.Kind)))
+}
+
+// writeLibraries writes the libraries, custom ones merged with the embedded, and their merged input data
+func writeLibraries(bundleDir, librariesPath string, libraries []string) error {
+	librariesSource := source.NewFilesystemSource([]string{}, []string{""}, []string{""}, librariesPath, "")
+
+	data := "{}"
+	for _, name := range libraries {
+		library, err := librariesSource.GetQueryLibrary(name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s library", name)
+		}
+		if err := os.WriteFile(filepath.Join(bundleDir, "lib", name+".rego"), []byte(library.LibraryCode), os.ModePerm); err != nil {
+			return err
+		}
+		if data, err = source.MergeInputData(data, library.LibraryInputData); err != nil {
+			return errors.Wrapf(err, "failed to merge %s library data", name)
+		}
+	}
+
+	var indented interface{}
+	if err := json.Unmarshal([]byte(data), &indented); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(indented, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(bundleDir, "data.json"), content, os.ModePerm)
+}
+
+// testModule returns tests checking the documents of each input, examples of binding an input
+func testModule(inputs []scan.PlatformInput) string {
+	var sb strings.Builder
+	sb.WriteString("package kics_input_test\n\n")
+	sb.WriteString("# The inputs are loaded as data.inputs.<platform>.<kind>, bind one to input with \"with input as\"\n")
+	sb.WriteString("# to test policies using the KICS libraries, data.generic.common and data.generic.<platform>\n")
+
+	tests := make([]string, 0, len(inputs))
+	for i := range inputs {
+		ref := "data.inputs." + strings.ReplaceAll(filepath.ToSlash(inputPath(&inputs[i])), "/", ".")
+		tests = append(tests, fmt.Sprintf("\ntest_%s {\n\tcount(input.document) == %d with input as %s\n}\n",
+			strings.ReplaceAll(ref[len("data.inputs."):], ".", "_"), len(inputs[i].Input.Documents), ref))
+	}
+	sort.Strings(tests)
+	for _, test := range tests {
+		sb.WriteString(test)
+	}
+	return sb.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package inputexport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/scan"
+	"github.com/open-policy-agent/opa/tester"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	inputs := []scan.PlatformInput{
+		{
+			Platform: "kubernetes",
+			Kind:     model.KindYAML,
+			Library:  "k8s",
+			Input: model.Documents{Documents: []model.Document{
+				{"id": "1", "file": "pod.yaml", "kind": "Pod", "metadata": map[string]interface{}{"name": "pod"}},
+				{"id": "2", "file": "pod.yaml", "kind": "Deployment"},
+			}},
+		},
+		{
+			Platform: "terraform",
+			Kind:     model.KindTerraform,
+			Library:  "terraform",
+			Input: model.Documents{Documents: []model.Document{
+				{"id": "3", "file": "main.tf", "resource": map[string]interface{}{}},
+			}},
+		},
+	}
+
+	outputDir := t.TempDir()
+	require.NoError(t, Write(outputDir, filepath.FromSlash("../../assets/libraries"), inputs))
+
+	for _, file := range []string{
+		SchemaFile,
+		filepath.Join(BundleDir, "inputs", "kubernetes", "yaml", InputFile),
+		filepath.Join(BundleDir, "inputs", "terraform", "tf", InputFile),
+		filepath.Join(BundleDir, "lib", "common.rego"),
+		filepath.Join(BundleDir, "lib", "k8s.rego"),
+		filepath.Join(BundleDir, "lib", "terraform.rego"),
+		filepath.Join(BundleDir, "data.json"),
+		filepath.Join(BundleDir, testFile),
+	} {
+		_, err := os.Stat(filepath.Join(outputDir, file))
+		require.NoError(t, err, file)
+	}
+
+	results, err := tester.Run(context.Background(), filepath.Join(outputDir, BundleDir))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.True(t, result.Pass(), "%s failed: %v", result.Name, result.Error)
+	}
+}
@@ -38,6 +38,9 @@ func (s *Service) resolverSink(ctx context.Context, filename, scanID string) ([]
 			log.Err(err).Msgf("failed to parse file content")
 			return []string{}, nil
 		}
+		fileCommands := s.Parser.CommentsCommands(rfile.FileName, rfile.OriginalData)
+		fileSuppressions := model.GetSuppressions(string(rfile.OriginalData))
+
 		for _, document := range documents.Docs {
 			_, err = json.Marshal(document)
 			if err != nil {
@@ -64,11 +67,14 @@ func (s *Service) resolverSink(ctx context.Context, filename, scanID string) ([]
 				Kind:              kind,
 				FilePath:          rfile.FileName,
 				Content:           string(rfile.Content),
+				Commands:          fileCommands,
 				HelmID:            rfile.SplitID,
 				IDInfo:            rfile.IDInfo,
 				LinesIgnore:       documents.IgnoreLines,
+				Suppressions:      fileSuppressions,
 				ResolvedFiles:     documents.ResolvedFiles,
 				LinesOriginalData: utils.SplitLines(string(rfile.OriginalData)),
+				SourceLines:       rfile.SourceLines,
 			}
 			s.saveToFile(ctx, &file)
 		}
@@ -68,7 +68,7 @@ func (s *Service) PrepareSources(ctx context.Context, scanID string, wg *sync.Wa
 	// CxSAST query under review
 	data := make([]byte, mbConst)
 
-	if s.Inspector != nil {
+	if s.GptInspector == nil {
 		if err := s.SourceProvider.GetSources(
 			ctx,
 			s.Parser.SupportedExtensions(),
@@ -185,6 +185,11 @@ func getContent(rc io.Reader, data []byte) (*Content, error) {
 	return c, nil
 }
 
+// Files returns the files prepared by PrepareSources, with the documents the inspector combines as input
+func (s *Service) Files() model.FileMetadatas {
+	return s.files
+}
+
 // GetVulnerabilities returns a list of scan detected vulnerabilities
 func (s *Service) GetVulnerabilities(ctx context.Context, scanID string) ([]model.Vulnerability, error) {
 	return s.Storage.GetVulnerabilities(ctx, scanID)
@@ -56,6 +56,7 @@ func (s *Service) sink(ctx context.Context, filename, scanID string, rc io.Reade
 	s.Tracker.TrackFileFoundCountLines(linesResolved)
 
 	fileCommands := s.Parser.CommentsCommands(filename, *content)
+	fileSuppressions := model.GetSuppressions(string(*content))
 
 	for _, document := range documents.Docs {
 		_, err = json.Marshal(document)
@@ -84,6 +85,7 @@ func (s *Service) sink(ctx context.Context, filename, scanID string, rc io.Reade
 			FilePath:          filename,
 			Commands:          fileCommands,
 			LinesIgnore:       documents.IgnoreLines,
+			Suppressions:      fileSuppressions,
 			ResolvedFiles:     documents.ResolvedFiles,
 			LinesOriginalData: utils.SplitLines(documents.Content),
 		}
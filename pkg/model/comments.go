@@ -1,5 +1,7 @@
 package model
 
+import "strings"
+
 // RemoveDuplicates removes duplicate lines from a slice of lines.
 func RemoveDuplicates(lines []int) []int {
 	seen := make(map[int]bool)
@@ -14,7 +16,11 @@ func RemoveDuplicates(lines []int) []int {
 }
 
 // ProcessCommands processes a slice of commands.
+// Commands with suppression attributes are not ignores, they are handled through the file suppressions
 func ProcessCommands(commands []string) CommentCommand {
+	if IsSuppressionComment(commands) {
+		return CommentCommand(strings.Join(commands, " "))
+	}
 	for _, command := range commands {
 		switch com := CommentCommand(command); com {
 		case IgnoreLine:
@@ -63,6 +63,13 @@ func TestProcessCommands(t *testing.T) {
 			},
 			want: CommentCommand("regular-command"),
 		},
+		{
+			name: "process commands: suppression",
+			args: args{
+				commands: []string{"ignore-line", "query=abc", "reason=accepted"},
+			},
+			want: CommentCommand("ignore-line query=abc reason=accepted"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,40 @@
+package model
+
+// Gate rule actions
+const (
+	GateActionFail = "fail"
+	GateActionWarn = "warn"
+)
+
+// GateFile is the representation of the versioned policy gate file checked into the scanned repository
+type GateFile struct {
+	Version int        `yaml:"version"`
+	Rules   []GateRule `yaml:"rules"`
+}
+
+// GateRule is violated when the results matching all its criteria are more than its maximum count, failing the
+// scan or only warning about it
+type GateRule struct {
+	Name       string   `yaml:"name" json:"name"`
+	Action     string   `yaml:"action" json:"action"`
+	Severities []string `yaml:"severities" json:"severities,omitempty"`
+	Categories []string `yaml:"categories" json:"categories,omitempty"`
+	QueryIDs   []string `yaml:"queryIDs" json:"query_ids,omitempty"`
+	Paths      []string `yaml:"paths" json:"paths,omitempty"`
+	Platforms  []string `yaml:"platforms" json:"platforms,omitempty"`
+	MaxCount   int      `yaml:"maxCount" json:"max_count"`
+}
+
+// GateRuleResult reports how many results a gate rule matched and if it was violated
+type GateRuleResult struct {
+	GateRule
+	Count           int      `json:"count"`
+	Violated        bool     `json:"violated"`
+	HighestSeverity Severity `json:"highest_severity,omitempty"`
+}
+
+// GateResult is the evaluation of the policy gate, which passes unless a rule with the fail action is violated
+type GateResult struct {
+	Passed bool             `json:"passed"`
+	Rules  []GateRuleResult `json:"rules"`
+}
@@ -23,6 +23,7 @@ const (
 	KindBUILDAH   FileKind = "SH"
 	KindCFG       FileKind = "CFG"
 	KindINI       FileKind = "INI"
+	KindANSIBLE   FileKind = "ANSIBLE"
 )
 
 // Constants to describe commands given from comments
@@ -30,6 +31,7 @@ const (
 	IgnoreLine    CommentCommand = "ignore-line"
 	IgnoreBlock   CommentCommand = "ignore-block"
 	IgnoreComment CommentCommand = "ignore-comment"
+	IgnoreFile    CommentCommand = "ignore"
 )
 
 // Constants to describe vulnerability's severity
@@ -41,6 +43,13 @@ const (
 	SeverityTrace  = "TRACE"
 )
 
+// Constants to describe the confidence that a detected secret is a real secret
+const (
+	SecretConfidenceHigh   = "high"
+	SecretConfidenceMedium = "medium"
+	SecretConfidenceLow    = "low"
+)
+
 // Constants to describe issue's type
 const (
 	IssueTypeMissingAttribute   IssueType = "MissingAttribute"
@@ -58,6 +67,13 @@ var (
 		SeverityTrace,
 	}
 
+	// AllSecretConfidences is sorted from the lowest to the highest confidence
+	AllSecretConfidences = []string{
+		SecretConfidenceLow,
+		SecretConfidenceMedium,
+		SecretConfidenceHigh,
+	}
+
 	AllIssueTypesAsString = []string{
 		string(IssueTypeMissingAttribute),
 		string(IssueTypeRedundantAttribute),
@@ -129,8 +145,10 @@ type FileMetadata struct {
 	IDInfo            map[int]interface{}
 	Commands          CommentsCommands
 	LinesIgnore       []int
+	Suppressions      []Suppression
 	ResolvedFiles     map[string]ResolvedFile
 	LinesOriginalData *[]string
+	SourceLines       []SourceLine
 }
 
 // QueryMetadata is a representation of general information about a query
@@ -159,34 +177,40 @@ type FailedQueries interface {
 // Vulnerability is a representation of a detected vulnerability in scanned files
 // after running a query
 type Vulnerability struct {
-	ID               int         `json:"id"`
-	ScanID           string      `db:"scan_id" json:"-"`
-	SimilarityID     string      `db:"similarity_id" json:"similarityID"`
-	FileID           string      `db:"file_id" json:"-"`
-	FileName         string      `db:"file_name" json:"fileName"`
-	QueryID          string      `db:"query_id" json:"queryID"`
-	QueryName        string      `db:"query_name" json:"queryName"`
-	QueryURI         string      `json:"-"`
-	Category         string      `json:"category"`
-	Description      string      `json:"description"`
-	DescriptionID    string      `json:"descriptionID"`
-	Platform         string      `db:"platform" json:"platform"`
-	Severity         Severity    `json:"severity"`
-	Line             int         `json:"line"`
-	VulnLines        *[]CodeLine `json:"vulnLines"`
-	ResourceType     string      `db:"resource_type" json:"resourceType"`
-	ResourceName     string      `db:"resource_name" json:"resourceName"`
-	IssueType        IssueType   `db:"issue_type" json:"issueType"`
-	SearchKey        string      `db:"search_key" json:"searchKey"`
-	SearchLine       int         `db:"search_line" json:"searchLine"`
-	SearchValue      string      `db:"search_value" json:"searchValue"`
-	KeyExpectedValue string      `db:"key_expected_value" json:"expectedValue"`
-	KeyActualValue   string      `db:"key_actual_value" json:"actualValue"`
-	Value            *string     `db:"value" json:"value"`
-	Output           string      `json:"-"`
-	CloudProvider    string      `json:"cloud_provider"`
-	Remediation      string      `db:"remediation" json:"remediation"`
-	RemediationType  string      `db:"remediation_type" json:"remediation_type"`
+	ID               int          `json:"id"`
+	ScanID           string       `db:"scan_id" json:"-"`
+	SimilarityID     string       `db:"similarity_id" json:"similarityID"`
+	FileID           string       `db:"file_id" json:"-"`
+	FileName         string       `db:"file_name" json:"fileName"`
+	QueryID          string       `db:"query_id" json:"queryID"`
+	QueryName        string       `db:"query_name" json:"queryName"`
+	QueryURI         string       `json:"-"`
+	Category         string       `json:"category"`
+	CWE              string       `json:"cwe,omitempty"`
+	Exposure         string       `json:"exposure,omitempty"`
+	Description      string       `json:"description"`
+	DescriptionID    string       `json:"descriptionID"`
+	Platform         string       `db:"platform" json:"platform"`
+	Severity         Severity     `json:"severity"`
+	Line             int          `json:"line"`
+	VulnLines        *[]CodeLine  `json:"vulnLines"`
+	ResourceType     string       `db:"resource_type" json:"resourceType"`
+	ResourceName     string       `db:"resource_name" json:"resourceName"`
+	IssueType        IssueType    `db:"issue_type" json:"issueType"`
+	SearchKey        string       `db:"search_key" json:"searchKey"`
+	SearchLine       int          `db:"search_line" json:"searchLine"`
+	SearchValue      string       `db:"search_value" json:"searchValue"`
+	KeyExpectedValue string       `db:"key_expected_value" json:"expectedValue"`
+	KeyActualValue   string       `db:"key_actual_value" json:"actualValue"`
+	Value            *string      `db:"value" json:"value"`
+	Output           string       `json:"-"`
+	CloudProvider    string       `json:"cloud_provider"`
+	Remediation      string       `db:"remediation" json:"remediation"`
+	RemediationType  string       `db:"remediation_type" json:"remediation_type"`
+	SecretType       string       `json:"secretType,omitempty"`
+	Confidence       string       `json:"confidence,omitempty"`
+	ResolvedFrom     string       `json:"resolvedFrom,omitempty"`
+	Suppression      *Suppression `json:"suppression,omitempty"`
 }
 
 // QueryConfig is a struct that contains the fileKind and platform of the rego query
@@ -208,6 +232,14 @@ type ResolvedHelm struct {
 	OriginalData []byte
 	SplitID      string
 	IDInfo       map[int]interface{}
+	SourceLines  []SourceLine
+}
+
+// SourceLine is the file and line a line of a resolved file was inlined from, with the indentation added to it
+type SourceLine struct {
+	FilePath string
+	Line     int
+	Indent   int
 }
 
 // Extensions represents a list of supported extensions
@@ -21,21 +21,30 @@ type SeveritySummary struct {
 
 // VulnerableFile contains information of a vulnerable file and where the vulnerability was found
 type VulnerableFile struct {
-	FileName         string      `json:"file_name"`
-	SimilarityID     string      `json:"similarity_id"`
-	Line             int         `json:"line"`
-	VulnLines        *[]CodeLine `json:"-"`
-	ResourceType     string      `json:"resource_type,omitempty"`
-	ResourceName     string      `json:"resource_name,omitempty"`
-	IssueType        IssueType   `json:"issue_type"`
-	SearchKey        string      `json:"search_key"`
-	SearchLine       int         `json:"search_line"`
-	SearchValue      string      `json:"search_value"`
-	KeyExpectedValue string      `json:"expected_value"`
-	KeyActualValue   string      `json:"actual_value"`
-	Value            *string     `json:"value,omitempty"`
-	Remediation      string      `json:"remediation,omitempty"`
-	RemediationType  string      `json:"remediation_type,omitempty"`
+	FileName         string       `json:"file_name"`
+	SimilarityID     string       `json:"similarity_id"`
+	Line             int          `json:"line"`
+	VulnLines        *[]CodeLine  `json:"-"`
+	ResourceType     string       `json:"resource_type,omitempty"`
+	ResourceName     string       `json:"resource_name,omitempty"`
+	IssueType        IssueType    `json:"issue_type"`
+	SearchKey        string       `json:"search_key"`
+	SearchLine       int          `json:"search_line"`
+	SearchValue      string       `json:"search_value"`
+	KeyExpectedValue string       `json:"expected_value"`
+	KeyActualValue   string       `json:"actual_value"`
+	Value            *string      `json:"value,omitempty"`
+	Remediation      string       `json:"remediation,omitempty"`
+	RemediationType  string       `json:"remediation_type,omitempty"`
+	SecretType       string       `json:"secret_type,omitempty"`
+	Confidence       string       `json:"confidence,omitempty"`
+	ResolvedFrom     string       `json:"resolved_from,omitempty"`
+	RiskScore        float64      `json:"risk_score,omitempty"`
+	Owners           []string     `json:"owners,omitempty"`
+	Author           string       `json:"author,omitempty"`
+	Commit           string       `json:"commit,omitempty"`
+	CommitDate       string       `json:"commit_date,omitempty"`
+	Suppression      *Suppression `json:"suppression,omitempty"`
 }
 
 // QueryResult contains a query that tested positive ID, name, severity and a list of files that tested vulnerable
@@ -47,6 +56,8 @@ type QueryResult struct {
 	Platform                    string           `json:"platform"`
 	CloudProvider               string           `json:"cloud_provider,omitempty"`
 	Category                    string           `json:"category"`
+	CWE                         string           `json:"cwe,omitempty"`
+	Exposure                    string           `json:"exposure,omitempty"`
 	Description                 string           `json:"description"`
 	DescriptionID               string           `json:"description_id"`
 	CISDescriptionIDFormatted   string           `json:"cis_description_id,omitempty"`
@@ -57,6 +68,7 @@ type QueryResult struct {
 	CISRationaleText            string           `json:"cis_description_rationale,omitempty"`
 	CISBenchmarkName            string           `json:"cis_benchmark_name,omitempty"`
 	CISBenchmarkVersion         string           `json:"cis_benchmark_version,omitempty"`
+	RiskScore                   float64          `json:"risk_score,omitempty"`
 	Files                       []VulnerableFile `json:"files"`
 }
 
@@ -99,6 +111,9 @@ type Summary struct {
 	ScannedPaths []string          `json:"paths"`
 	Queries      QueryResultSlice  `json:"queries"`
 	Bom          QueryResultSlice  `json:"bill_of_materials,omitempty"`
+	Suppressed   QueryResultSlice  `json:"suppressed,omitempty"`
+	Waivers      []WaiverUsage     `json:"waivers,omitempty"`
+	Gate         *GateResult       `json:"gate,omitempty"`
 	FilePaths    map[string]string `json:"-"`
 }
 
@@ -190,10 +205,17 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 	}
 	filePaths := make(map[string]string)
 
+	suppressed := make(map[string]QueryResult)
+
 	for i := range vulnerabilities {
 		item := vulnerabilities[i]
-		if _, ok := q[item.QueryID]; !ok {
-			q[item.QueryID] = QueryResult{
+		// suppressed results are kept apart from the results, for audit
+		results := q
+		if item.Suppression != nil {
+			results = suppressed
+		}
+		if _, ok := results[item.QueryID]; !ok {
+			results[item.QueryID] = QueryResult{
 				QueryName:     item.QueryName,
 				QueryID:       item.QueryID,
 				Severity:      item.Severity,
@@ -201,14 +223,20 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 				Platform:      item.Platform,
 				CloudProvider: strings.ToUpper(item.CloudProvider),
 				Category:      item.Category,
+				CWE:           item.CWE,
+				Exposure:      item.Exposure,
 				Description:   item.Description,
 				DescriptionID: item.DescriptionID,
 			}
 		}
 
 		resolvedPath := resolvePath(item.FileName, pathExtractionMap)
+		resolvedFrom := ""
+		if item.ResolvedFrom != "" {
+			resolvedFrom = resolvePath(item.ResolvedFrom, pathExtractionMap)
+		}
 
-		qItem := q[item.QueryID]
+		qItem := results[item.QueryID]
 		qItem.Files = append(qItem.Files, VulnerableFile{
 			FileName:         resolvedPath,
 			SimilarityID:     item.SimilarityID,
@@ -225,11 +253,15 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 			Value:            item.Value,
 			Remediation:      item.Remediation,
 			RemediationType:  item.RemediationType,
+			SecretType:       item.SecretType,
+			Confidence:       item.Confidence,
+			ResolvedFrom:     resolvedFrom,
+			Suppression:      item.Suppression,
 		})
 
 		filePaths[resolvedPath] = item.FileName
 
-		q[item.QueryID] = qItem
+		results[item.QueryID] = qItem
 	}
 
 	queries := make([]QueryResult, 0, len(q))
@@ -261,10 +293,19 @@ func CreateSummary(counters Counters, vulnerabilities []Vulnerability,
 		}
 	}
 
+	suppressedQueries := make([]QueryResult, 0, len(suppressed))
+	for idx := range suppressed {
+		suppressedQueries = append(suppressedQueries, suppressed[idx])
+	}
+	sort.Slice(suppressedQueries, func(i, j int) bool {
+		return suppressedQueries[i].QueryName < suppressedQueries[j].QueryName
+	})
+
 	severitySummary.SeverityCounters = sevs
 
 	return Summary{
 		Bom:             materials,
+		Suppressed:      suppressedQueries,
 		Counters:        counters,
 		Queries:         queries,
 		SeveritySummary: severitySummary,
@@ -54,6 +54,7 @@ func TestCreateSummary(t *testing.T) {
 			},
 			Bom:          []QueryResult{},
 			Queries:      []QueryResult{},
+			Suppressed:   []QueryResult{},
 			ScannedPaths: []string{},
 			FilePaths:    make(map[string]string),
 		})
@@ -95,10 +96,24 @@ func TestCreateSummary(t *testing.T) {
 					},
 				},
 			},
+			Suppressed:   []QueryResult{},
 			ScannedPaths: []string{},
 			FilePaths:    filePaths,
 		})
 	})
+
+	t.Run("create_summary_suppressed", func(t *testing.T) {
+		suppression := &Suppression{Command: IgnoreLine, Line: 1, Lines: []int{2}, Reason: "accepted risk"}
+		suppressed := append([]Vulnerability{}, vulnerabilities...)
+		suppressed[0].Suppression = suppression
+		summary := CreateSummary(counter, suppressed, "scanID", pathExtractionMap, Version{})
+		require.Empty(t, summary.Queries)
+		require.Equal(t, 0, summary.TotalCounter)
+		require.Equal(t, 0, summary.SeverityCounters[SeverityHigh])
+		require.Len(t, summary.Suppressed, 1)
+		require.Equal(t, "QueryID", summary.Suppressed[0].QueryID)
+		require.Equal(t, suppression, summary.Suppressed[0].Files[0].Suppression)
+	})
 }
 
 func TestModel_resolvePath(t *testing.T) {
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SuppressionDateLayout is the layout of the suppressions expiration date
+const SuppressionDateLayout = "2006-01-02"
+
+var (
+	// suppressionRgxp identifies KICS ignore comments followed by suppression attributes
+	suppressionRgxp = regexp.MustCompile(
+		`(?i)(^|\s)((/{2})|#|;)\s*kics-scan\s+(ignore-line|ignore-block|ignore)\s+(\w+=.*)$`)
+	suppressionAttributeRgxp = regexp.MustCompile(`(\w+)=("[^"]*"|'[^']*'|\S+)`)
+	closingBracketRgxp       = regexp.MustCompile(`^\s*[}\])]`)
+)
+
+// Suppression is an ignore comment with a justification, an optional expiration date and an owner,
+// scoped to some queries when the query attribute is set, an ignore comment at the file beginning covers the whole file
+type Suppression struct {
+	Command CommentCommand `json:"kind"`
+	Line    int            `json:"line"`
+	Lines   []int          `json:"-"`
+	Queries []string       `json:"queries,omitempty"`
+	Reason  string         `json:"reason,omitempty"`
+	Owner   string         `json:"owner,omitempty"`
+	Expires string         `json:"expires,omitempty"`
+}
+
+// Expired returns true when the suppression expiration date is set and it is before the given time
+// invalid expiration dates are considered expired so the findings are not hidden by mistake
+func (s *Suppression) Expired(now time.Time) bool {
+	return isExpired(s.Expires, now)
+}
+
+func isExpired(date string, now time.Time) bool {
+	if date == "" {
+		return false
+	}
+	expires, err := time.Parse(SuppressionDateLayout, date)
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires)
+}
+
+// Covers returns true when the suppression applies to the given line and query
+func (s *Suppression) Covers(line int, queryID string) bool {
+	covered := s.Command == IgnoreFile
+	for _, suppressedLine := range s.Lines {
+		if suppressedLine == line {
+			covered = true
+			break
+		}
+	}
+	if !covered {
+		return false
+	}
+	if len(s.Queries) == 0 {
+		return true
+	}
+	for _, query := range s.Queries {
+		if strings.EqualFold(query, queryID) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSuppressionComment returns true when the KICS comment commands have suppression attributes
+func IsSuppressionComment(commands []string) bool {
+	for _, command := range commands {
+		if strings.Contains(command, "=") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSuppressions returns the suppressions defined in the content comments
+// ignore-line suppressions cover the line with the comment, when it follows code, or the next line
+// ignore-block suppressions also cover the lines nested under the next line
+// ignore suppressions cover the whole file and are only read from the comments before the code starts
+func GetSuppressions(content string) []Suppression {
+	suppressions := make([]Suppression, 0)
+	lines := strings.Split(content, "\n")
+	codeStart := nextCodeLine(lines, 0)
+	for idx, line := range lines {
+		groups := suppressionRgxp.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if groups == nil {
+			continue
+		}
+		suppression := Suppression{
+			Command: CommentCommand(strings.ToLower(groups[4])),
+			Line:    idx + 1,
+		}
+		parseSuppressionAttributes(&suppression, groups[5])
+		if suppression.Command == IgnoreFile {
+			if idx < codeStart {
+				suppressions = append(suppressions, suppression)
+			}
+			continue
+		}
+
+		start := idx
+		if strings.TrimSpace(line[:strings.Index(line, groups[0])]) == "" {
+			start = nextCodeLine(lines, idx+1)
+		}
+		if start < len(lines) {
+			suppression.Lines = Range(start+1, start+1)
+			if suppression.Command == IgnoreBlock {
+				suppression.Lines = Range(start+1, blockEnd(lines, start)+1)
+			}
+		}
+		suppressions = append(suppressions, suppression)
+	}
+	return suppressions
+}
+
+func parseSuppressionAttributes(suppression *Suppression, attributes string) {
+	for _, attribute := range suppressionAttributeRgxp.FindAllStringSubmatch(attributes, -1) {
+		value := strings.Trim(attribute[2], `"'`)
+		switch strings.ToLower(attribute[1]) {
+		case "query", "queries":
+			for _, query := range strings.Split(value, ",") {
+				if query = strings.TrimSpace(query); query != "" {
+					suppression.Queries = append(suppression.Queries, query)
+				}
+			}
+		case "reason":
+			suppression.Reason = value
+		case "owner":
+			suppression.Owner = value
+		case "expires":
+			suppression.Expires = value
+		}
+	}
+}
+
+// nextCodeLine returns the index of the next line that is neither blank nor a comment
+func nextCodeLine(lines []string, start int) int {
+	for idx := start; idx < len(lines); idx++ {
+		trimmed := strings.TrimSpace(lines[idx])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") ||
+			strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		return idx
+	}
+	return len(lines)
+}
+
+// blockEnd returns the index of the last line of the block starting at the given line, the block holds
+// the lines with greater indentation, its closing bracket and the line continuations
+func blockEnd(lines []string, start int) int {
+	indentation := lineIndentation(lines[start])
+	end := start
+	for idx := start + 1; idx < len(lines); idx++ {
+		if strings.TrimSpace(lines[idx]) == "" {
+			continue
+		}
+		continued := strings.HasSuffix(strings.TrimRight(lines[idx-1], " \t\r"), "\\")
+		if lineIndentation(lines[idx]) > indentation || continued {
+			end = idx
+			continue
+		}
+		if closingBracketRgxp.MatchString(lines[idx]) {
+			end = idx
+		}
+		break
+	}
+	return end
+}
+
+func lineIndentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetSuppressions tests the GetSuppressions function.
+func TestGetSuppressions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Suppression
+	}{
+		{
+			name:    "no suppressions",
+			content: "# kics-scan ignore-line\nresource \"aws_s3_bucket\" \"b\" {\n}\n",
+			want:    []Suppression{},
+		},
+		{
+			name: "ignore-line with attributes",
+			content: "resource \"aws_s3_bucket\" \"b\" {\n" +
+				"  # kics-scan ignore-line query=a227ec01-f97a-4084-91a4-47b350c1db54 reason=\"public website\" " +
+				"expires=2027-01-01 owner=@web-team\n\n  acl = \"public-read\"\n}\n",
+			want: []Suppression{
+				{
+					Command: IgnoreLine,
+					Line:    2,
+					Lines:   []int{4},
+					Queries: []string{"a227ec01-f97a-4084-91a4-47b350c1db54"},
+					Reason:  "public website",
+					Owner:   "@web-team",
+					Expires: "2027-01-01",
+				},
+			},
+		},
+		{
+			name:    "trailing ignore-line",
+			content: "a: 1\nb: 2 # kics-scan ignore-line reason='not used' query=q1,q2\n",
+			want: []Suppression{
+				{Command: IgnoreLine, Line: 2, Lines: []int{2}, Queries: []string{"q1", "q2"}, Reason: "not used"},
+			},
+		},
+		{
+			name: "ignore-block",
+			content: "// kics-scan ignore-block reason=\"legacy bucket\"\nresource \"aws_s3_bucket\" \"b\" {\n" +
+				"  acl = \"public-read\"\n\n  versioning {\n    enabled = false\n  }\n}\n" +
+				"resource \"aws_s3_bucket\" \"c\" {\n}\n",
+			want: []Suppression{
+				{Command: IgnoreBlock, Line: 1, Lines: []int{2, 3, 4, 5, 6, 7, 8}, Reason: "legacy bucket"},
+			},
+		},
+		{
+			name: "file ignore",
+			content: "# kics-scan ignore query=q1 reason=\"generated file\" expires=2027-01-01 owner=@platform\n" +
+				"---\na: 1 # kics-scan ignore reason=late\n",
+			want: []Suppression{
+				{
+					Command: IgnoreFile,
+					Line:    1,
+					Queries: []string{"q1"},
+					Reason:  "generated file",
+					Owner:   "@platform",
+					Expires: "2027-01-01",
+				},
+			},
+		},
+		{
+			name:    "ignore-block with line continuations",
+			content: "# kics-scan ignore-block reason=apt\nRUN apt-get update && \\\napt-get install -y curl\nUSER root\n",
+			want: []Suppression{
+				{Command: IgnoreBlock, Line: 1, Lines: []int{2, 3}, Reason: "apt"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, GetSuppressions(tt.content))
+		})
+	}
+}
+
+// TestSuppression_Covers tests the Covers function.
+func TestSuppression_Covers(t *testing.T) {
+	all := Suppression{Lines: []int{3, 4}}
+	scoped := Suppression{Lines: []int{3, 4}, Queries: []string{"Q1"}}
+
+	require.True(t, all.Covers(3, "q2"))
+	require.False(t, all.Covers(5, "q2"))
+	require.True(t, scoped.Covers(4, "q1"))
+	require.False(t, scoped.Covers(4, "q2"))
+
+	file := Suppression{Command: IgnoreFile, Queries: []string{"q1"}}
+	require.True(t, file.Covers(40, "q1"))
+	require.False(t, file.Covers(40, "q2"))
+}
+
+// TestSuppression_Expired tests the Expired function.
+func TestSuppression_Expired(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expires string
+		want    bool
+	}{
+		{expires: "", want: false},
+		{expires: "2027-01-01", want: false},
+		{expires: "2026-06-01", want: true},
+		{expires: "2026-01-01", want: true},
+		{expires: "next year", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expires, func(t *testing.T) {
+			suppression := Suppression{Expires: tt.expires}
+			require.Equal(t, tt.want, suppression.Expired(now))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Waiver statuses reported in the waivers usage
+const (
+	WaiverStatusUsed    = "used"
+	WaiverStatusUnused  = "unused"
+	WaiverStatusExpired = "expired"
+)
+
+// WaiversFile is the representation of the versioned waivers file checked into the scanned repository
+type WaiversFile struct {
+	Version int      `yaml:"version"`
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// Waiver excludes the results matching all its criteria until its expiration date
+type Waiver struct {
+	SimilarityID string `yaml:"similarityID" json:"similarity_id,omitempty"`
+	QueryID      string `yaml:"queryID" json:"query_id,omitempty"`
+	Path         string `yaml:"path" json:"path,omitempty"`
+	ResourceName string `yaml:"resourceName" json:"resource_name,omitempty"`
+	Severity     string `yaml:"severity" json:"severity,omitempty"`
+	Reason       string `yaml:"reason" json:"reason"`
+	Expires      string `yaml:"expires" json:"expires,omitempty"`
+}
+
+// WaiverUsage reports how many results a waiver excluded and its status
+type WaiverUsage struct {
+	Waiver
+	Status  string `json:"status"`
+	Matches int    `json:"matches"`
+}
+
+// Expired returns true when the waiver expiration date is set and it is before the given time
+func (w *Waiver) Expired(now time.Time) bool {
+	return isExpired(w.Expires, now)
+}
@@ -0,0 +1,160 @@
+package ownership
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeownersLocations are the paths, relative to the repository root, where GitHub looks for the CODEOWNERS file,
+// in its order of precedence, followed by the location only used by GitLab, which reads it after the root and docs
+var codeownersLocations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+	filepath.Join(".gitlab", "CODEOWNERS"),
+}
+
+var sectionRegex = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+type codeownersSection struct {
+	name          string
+	defaultOwners []string
+	rules         []codeownersRule
+}
+
+// Codeowners holds the rules of a CODEOWNERS file, in GitHub or GitLab syntax
+type Codeowners struct {
+	sections []*codeownersSection
+}
+
+// FindCodeowners reads the CODEOWNERS file of a repository, it returns nil when the repository has none
+func FindCodeowners(root string) (*Codeowners, error) {
+	for _, location := range codeownersLocations {
+		content, err := os.ReadFile(filepath.Clean(filepath.Join(root, location)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return ParseCodeowners(string(content)), nil
+	}
+	return nil, nil
+}
+
+// ParseCodeowners parses the rules of a CODEOWNERS file, GitLab sections, with their default owners, are
+// supported and a file gets the owners of the last rule matching it in every section
+func ParseCodeowners(content string) *Codeowners {
+	section := &codeownersSection{}
+	codeowners := &Codeowners{sections: []*codeownersSection{section}}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if groups := sectionRegex.FindStringSubmatch(line); groups != nil {
+			section = &codeownersSection{name: groups[1], defaultOwners: parseOwners(groups[2])}
+			codeowners.sections = append(codeowners.sections, section)
+			continue
+		}
+
+		pattern, owners := splitRule(line)
+		rule := codeownersRule{pattern: patternRegex(pattern), owners: parseOwners(owners)}
+		if len(rule.owners) == 0 {
+			rule.owners = section.defaultOwners
+		}
+		section.rules = append(section.rules, rule)
+	}
+
+	return codeowners
+}
+
+// splitRule splits a rule in its pattern and its owners, spaces of the pattern may be escaped
+func splitRule(line string) (pattern, owners string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case ' ', '\t':
+			return strings.ReplaceAll(line[:i], `\`, ""), line[i+1:]
+		}
+	}
+	return strings.ReplaceAll(line, `\`, ""), ""
+}
+
+func parseOwners(owners string) []string {
+	if i := strings.Index(owners, " #"); i >= 0 {
+		owners = owners[:i]
+	}
+	return strings.Fields(owners)
+}
+
+// patternRegex converts a gitignore-like CODEOWNERS pattern to a regex matching the paths relative to the
+// repository root, a pattern matching a directory matches every file under it
+func patternRegex(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	// docs/* owns the files of docs but not the ones of its subdirectories
+	suffix := `(?:/.*)?$`
+	if strings.HasSuffix(pattern, "/*") {
+		suffix = `$`
+	}
+	pattern = strings.Trim(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString(`^`)
+	} else {
+		expr.WriteString(`^(?:.*/)?`)
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString(`(?:.*/)?`)
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(`.*`)
+			i++
+		case pattern[i] == '*':
+			expr.WriteString(`[^/]*`)
+		case pattern[i] == '?':
+			expr.WriteString(`[^/]`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString(suffix)
+
+	return regexp.MustCompile(expr.String())
+}
+
+// Owners returns the owners of a path relative to the repository root
+func (c *Codeowners) Owners(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	owners := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, section := range c.sections {
+		for i := len(section.rules) - 1; i >= 0; i-- {
+			if !section.rules[i].pattern.MatchString(path) {
+				continue
+			}
+			for _, owner := range section.rules[i].owners {
+				if !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
@@ -0,0 +1,70 @@
+package ownership
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeownersOwners(t *testing.T) {
+	codeowners := ParseCodeowners(`# global owners
+*                    @org/platform
+*.tf                 @org/infra # terraform
+/docs/*              docs@example.com
+apps/**/k8s/         @org/apps
+build/logs\ dir/     @org/ci
+/secrets/
+
+[Security][2] @org/security
+**/iam/
+terraform/prod/      @org/security @org/sre
+`)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "README.md", want: []string{"@org/platform"}},
+		{path: "modules/network/main.tf", want: []string{"@org/infra"}},
+		{path: "docs/index.md", want: []string{"docs@example.com"}},
+		{path: "docs/guides/index.md", want: []string{"@org/platform"}},
+		{path: "apps/web/k8s/deployment.yaml", want: []string{"@org/apps"}},
+		{path: "apps/k8s/deployment.yaml", want: []string{"@org/apps"}},
+		{path: "build/logs dir/job.yaml", want: []string{"@org/ci"}},
+		{path: "secrets/key.yaml", want: []string{}},
+		{path: "modules/iam/main.tf", want: []string{"@org/infra", "@org/security"}},
+		{path: "terraform/prod/main.tf", want: []string{"@org/infra", "@org/security", "@org/sre"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.want, codeowners.Owners(tt.path))
+		})
+	}
+}
+
+func TestFindCodeowners(t *testing.T) {
+	root := t.TempDir()
+	codeowners, err := FindCodeowners(root)
+	require.NoError(t, err)
+	require.Nil(t, codeowners)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "CODEOWNERS"), []byte("* @org/team-docs\n"), os.ModePerm))
+	codeowners, err = FindCodeowners(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"@org/team-docs"}, codeowners.Owners("main.tf"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @org/team-root\n"), os.ModePerm))
+	codeowners, err = FindCodeowners(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"@org/team-root"}, codeowners.Owners("main.tf"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @org/team-a\n"), os.ModePerm))
+	codeowners, err = FindCodeowners(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"@org/team-a"}, codeowners.Owners("main.tf"))
+}
@@ -0,0 +1,192 @@
+package ownership
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/results"
+	"github.com/rs/zerolog/log"
+)
+
+// notCommitted is the commit git blame gives to the lines not committed yet
+const notCommitted = "0000000000000000000000000000000000000000"
+
+// BlameLine is the last commit that changed a line
+type BlameLine struct {
+	Author string
+	Commit string
+	Date   time.Time
+}
+
+type repository struct {
+	root       string
+	codeowners *Codeowners
+}
+
+// Enricher adds the owners, from the CODEOWNERS files, and the author and date of the last change, from git
+// blame, to the results, using only the local git repositories of the scanned files
+type Enricher struct {
+	repositories map[string]*repository
+	blames       map[string]map[int]BlameLine
+}
+
+// NewEnricher creates an enricher
+func NewEnricher() *Enricher {
+	return &Enricher{
+		repositories: make(map[string]*repository),
+		blames:       make(map[string]map[int]BlameLine),
+	}
+}
+
+// Enrich sets the ownership of the results of the summary, the results out of a git repository are left as is
+func (e *Enricher) Enrich(summary *model.Summary) {
+	for i := range summary.Queries {
+		for j := range summary.Queries[i].Files {
+			e.enrichFile(summary, &summary.Queries[i].Files[j])
+		}
+	}
+}
+
+func (e *Enricher) enrichFile(summary *model.Summary, file *model.VulnerableFile) {
+	path := file.FileName
+	if original, ok := summary.FilePaths[file.FileName]; ok {
+		path = original
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	repo := e.repository(filepath.Dir(path))
+	if repo == nil {
+		return
+	}
+	relPath, err := filepath.Rel(repo.root, path)
+	if err != nil {
+		return
+	}
+	if repo.codeowners != nil {
+		file.Owners = repo.codeowners.Owners(relPath)
+	}
+
+	if blame, ok := e.blame(repo.root, relPath)[file.Line]; ok {
+		file.Author = blame.Author
+		file.Commit = blame.Commit
+		file.CommitDate = blame.Date.UTC().Format(time.RFC3339)
+	}
+}
+
+// repository returns the git repository containing a directory, nil when there is none
+func (e *Enricher) repository(dir string) *repository {
+	if repo, ok := e.repositories[dir]; ok {
+		return repo
+	}
+
+	var repo *repository
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		codeowners, err := FindCodeowners(dir)
+		if err != nil {
+			log.Warn().Msgf("Failed to read the CODEOWNERS file of %s: %s", dir, err)
+		}
+		repo = &repository{root: dir, codeowners: codeowners}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		repo = e.repository(parent)
+	}
+
+	e.repositories[dir] = repo
+	return repo
+}
+
+// blame returns the last commit of each line of a file, by line number
+func (e *Enricher) blame(root, relPath string) map[int]BlameLine {
+	key := filepath.Join(root, relPath)
+	if lines, ok := e.blames[key]; ok {
+		return lines
+	}
+
+	cmd := exec.Command("git", "-C", root, "blame", "--line-porcelain", "--", filepath.ToSlash(relPath)) //#nosec
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		log.Debug().Msgf("Failed to blame %s: %s %s", key, err, strings.TrimSpace(stderr.String()))
+		e.blames[key] = map[int]BlameLine{}
+		return e.blames[key]
+	}
+
+	e.blames[key] = parseBlame(output)
+	return e.blames[key]
+}
+
+// parseBlame parses the output of git blame --line-porcelain, the lines not committed yet are left out
+func parseBlame(output []byte) map[int]BlameLine {
+	lines := make(map[int]BlameLine)
+	var current BlameLine
+	line := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 10*bufio.MaxScanTokenSize)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			if current.Commit != notCommitted {
+				lines[line] = current
+			}
+		case strings.HasPrefix(text, "author "):
+			current.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				current.Date = time.Unix(seconds, 0)
+			}
+		default:
+			// the header of a line: <commit> <original line> <final line> [<lines of the group>]
+			fields := strings.Fields(text)
+			if len(fields) >= 3 && len(fields[0]) == len(notCommitted) {
+				current = BlameLine{Commit: fields[0]}
+				line, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+
+	return lines
+}
+
+// Filter keeps the results of the summary owned by one of the owners, the @ of the owners is optional
+func Filter(summary *model.Summary, owners []string) {
+	wanted := make(map[string]bool, len(owners))
+	for _, owner := range owners {
+		wanted[normalizeOwner(owner)] = true
+	}
+
+	queries := make(model.QueryResultSlice, 0, len(summary.Queries))
+	for i := range summary.Queries {
+		files := make([]model.VulnerableFile, 0, len(summary.Queries[i].Files))
+		for j := range summary.Queries[i].Files {
+			for _, owner := range summary.Queries[i].Files[j].Owners {
+				if wanted[normalizeOwner(owner)] {
+					files = append(files, summary.Queries[i].Files[j])
+					break
+				}
+			}
+		}
+		if len(files) > 0 {
+			summary.Queries[i].Files = files
+			queries = append(queries, summary.Queries[i])
+		}
+	}
+
+	summary.Queries = queries
+	results.Recount(summary)
+}
+
+func normalizeOwner(owner string) string {
+	return strings.ToLower(strings.TrimPrefix(owner, "@"))
+}
@@ -0,0 +1,123 @@
+package ownership
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlame(t *testing.T) {
+	output := `4c3b1e0a9b5f8e7d6c5b4a39281706f5e4d3c2b1 1 1 2
+author Jane Doe
+author-mail <jane@example.com>
+author-time 1700000000
+author-tz +0000
+summary add bucket
+filename main.tf
+	resource "aws_s3_bucket" "logs" {
+4c3b1e0a9b5f8e7d6c5b4a39281706f5e4d3c2b1 2 2
+author Jane Doe
+author-mail <jane@example.com>
+author-time 1700000000
+author-tz +0000
+summary add bucket
+filename main.tf
+	  acl = "public-read"
+0000000000000000000000000000000000000000 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1710000000
+author-tz +0000
+summary Version of main.tf from main.tf
+filename main.tf
+	}
+`
+
+	got := parseBlame([]byte(output))
+
+	require.Len(t, got, 2)
+	require.Equal(t, BlameLine{
+		Author: "Jane Doe",
+		Commit: "4c3b1e0a9b5f8e7d6c5b4a39281706f5e4d3c2b1",
+		Date:   time.Unix(1700000000, 0),
+	}, got[2])
+	require.NotContains(t, got, 3)
+}
+
+func TestFilter(t *testing.T) {
+	summary := &model.Summary{
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "S3 Bucket ACL Allows Read Or Write to All Users",
+				Severity:  model.SeverityHigh,
+				Files: []model.VulnerableFile{
+					{FileName: "infra/main.tf", Owners: []string{"@Org/Infra"}},
+					{FileName: "apps/main.tf", Owners: []string{"@org/apps"}},
+				},
+			},
+			{
+				QueryName: "Resource Not Using Tags",
+				Severity:  model.SeverityInfo,
+				Files:     []model.VulnerableFile{{FileName: "main.tf"}},
+			},
+		},
+	}
+
+	Filter(summary, []string{"org/infra"})
+
+	require.Len(t, summary.Queries, 1)
+	require.Len(t, summary.Queries[0].Files, 1)
+	require.Equal(t, "infra/main.tf", summary.Queries[0].Files[0].FileName)
+	require.Equal(t, 1, summary.TotalCounter)
+	require.Equal(t, 1, summary.SeverityCounters[model.SeverityHigh])
+}
+
+func TestEnrich(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE=2023-11-14T22:13:20Z",
+			"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "infra"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("/infra/ @org/infra\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "infra", "main.tf"), []byte("resource \"aws_s3_bucket\" \"logs\" {\n}\n"), os.ModePerm))
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "add bucket")
+
+	outside := filepath.Join(t.TempDir(), "main.tf")
+	summary := &model.Summary{
+		Queries: model.QueryResultSlice{
+			{
+				Files: []model.VulnerableFile{
+					{FileName: filepath.Join(root, "infra", "main.tf"), Line: 1},
+					{FileName: outside, Line: 1},
+				},
+			},
+		},
+	}
+
+	NewEnricher().Enrich(summary)
+
+	file := summary.Queries[0].Files[0]
+	require.Equal(t, []string{"@org/infra"}, file.Owners)
+	require.Equal(t, "Jane Doe", file.Author)
+	require.Len(t, file.Commit, 40)
+	require.Equal(t, "2023-11-14T22:13:20Z", file.CommitDate)
+
+	require.Equal(t, model.VulnerableFile{FileName: outside, Line: 1}, summary.Queries[0].Files[1])
+}
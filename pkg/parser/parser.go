@@ -101,6 +101,10 @@ func (c *Parser) CommentsCommands(filePath string, fileContent []byte) model.Com
 				}
 				fields := strings.Fields(strings.TrimSpace(strings.TrimPrefix(line, commentToken)))
 				if len(fields) > 1 && fields[0] == "kics-scan" && fields[1] != "" {
+					// an ignore command with suppression attributes does not drop the file, see model.GetSuppressions
+					if model.IsSuppressionComment(fields[2:]) {
+						continue
+					}
 					commandParameters := strings.SplitN(fields[1], "=", 2)
 					if len(commandParameters) > 1 {
 						commentsCommands[commandParameters[0]] = commandParameters[1]
@@ -122,6 +122,7 @@ func TestCommentsCommands(t *testing.T) {
 	commands := parser[0].CommentsCommands("Dockerfile", []byte(`
 	# kics-scan ignore
 	# kics-scan disable=ffdf4b37-7703-4dfe-a682-9d2e99bc6c09
+	# kics-scan ignore reason="base image" expires=2027-01-01
 	FROM foo
 	COPY . /
 	RUN echo hello
@@ -131,6 +132,12 @@ func TestCommentsCommands(t *testing.T) {
 		"disable": "ffdf4b37-7703-4dfe-a682-9d2e99bc6c09",
 	}
 	require.Equal(t, expectedCommands, commands)
+
+	commands = parser[0].CommentsCommands("Dockerfile", []byte(`
+	# kics-scan ignore query=ffdf4b37-7703-4dfe-a682-9d2e99bc6c09 reason="base image"
+	FROM foo
+	`))
+	require.Empty(t, commands)
 }
 
 func TestParser_Contains(t *testing.T) {
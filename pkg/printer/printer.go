@@ -121,10 +121,15 @@ func PrintResult(summary *model.Summary, failedQueries map[string]error, printer
 			continue
 		}
 
+		riskScore := ""
+		if summary.Queries[idx].RiskScore > 0 {
+			riskScore = fmt.Sprintf(", Risk Score: %.1f", summary.Queries[idx].RiskScore)
+		}
 		fmt.Printf(
-			"%s, Severity: %s, Results: %d\n",
+			"%s, Severity: %s%s, Results: %d\n",
 			printer.PrintBySev(summary.Queries[idx].QueryName, string(summary.Queries[idx].Severity)),
 			printer.PrintBySev(string(summary.Queries[idx].Severity), string(summary.Queries[idx].Severity)),
+			riskScore,
 			len(summary.Queries[idx].Files),
 		)
 		if !printer.minimal {
@@ -163,6 +168,10 @@ func PrintResult(summary *model.Summary, failedQueries map[string]error, printer
 	printSeverityCounter(model.SeverityInfo, summary.SeveritySummary.SeverityCounters[model.SeverityInfo], printer.Info)
 	fmt.Printf("TOTAL: %d\n\n", summary.SeveritySummary.TotalCounter)
 
+	if summary.Gate != nil {
+		printGate(summary.Gate, printer)
+	}
+
 	log.Info().Msgf("Scanned Files: %d", summary.ScannedFiles)
 	log.Info().Msgf("Parsed Files: %d", summary.ParsedFiles)
 	log.Info().Msgf("Scanned Lines: %d", summary.ScannedFilesLines)
@@ -175,14 +184,38 @@ func PrintResult(summary *model.Summary, failedQueries map[string]error, printer
 	return nil
 }
 
+// printGate prints the violated rules of the policy gate and its status
+func printGate(gate *model.GateResult, printer *Printer) {
+	for i := range gate.Rules {
+		rule := &gate.Rules[i]
+		if !rule.Violated {
+			continue
+		}
+		action := printer.PrintBySev("[FAIL]", model.SeverityHigh)
+		if rule.Action == model.GateActionWarn {
+			action = printer.PrintBySev("[WARN]", model.SeverityMedium)
+		}
+		fmt.Printf("%s %s: %d results, maximum %d\n", action, rule.Name, rule.Count, rule.MaxCount)
+	}
+	if gate.Passed {
+		fmt.Printf("%s %s\n\n", printer.Bold("Gate:"), printer.Success.Sprint("PASSED"))
+		return
+	}
+	fmt.Printf("%s %s\n\n", printer.Bold("Gate:"), printer.PrintBySev("FAILED", model.SeverityHigh))
+}
+
 func printSeverityCounter(severity string, counter int, printColor color.RGBColor) {
 	fmt.Printf("%s: %d\n", printColor.Sprint(severity), counter)
 }
 
 func printFiles(query *model.QueryResult, printer *Printer) {
 	for fileIdx := range query.Files {
-		fmt.Printf("\t%s %s:%s\n", printer.PrintBySev(fmt.Sprintf("[%d]:", fileIdx+1), string(query.Severity)),
-			query.Files[fileIdx].FileName, printer.Success.Sprint(query.Files[fileIdx].Line))
+		riskScore := ""
+		if query.Files[fileIdx].RiskScore > 0 {
+			riskScore = fmt.Sprintf(" (risk score: %.1f)", query.Files[fileIdx].RiskScore)
+		}
+		fmt.Printf("\t%s %s:%s%s\n", printer.PrintBySev(fmt.Sprintf("[%d]:", fileIdx+1), string(query.Severity)),
+			query.Files[fileIdx].FileName, printer.Success.Sprint(query.Files[fileIdx].Line), riskScore)
 		if !printer.minimal && query.Files[fileIdx].VulnLines != nil {
 			fmt.Println()
 			for _, line := range *query.Files[fileIdx].VulnLines {
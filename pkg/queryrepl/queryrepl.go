@@ -0,0 +1,266 @@
+// Package queryrepl evaluates rego against a file parsed as the scan does, with the common and platform
+// libraries loaded, so queries can be debugged without running a full scan
+package queryrepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/internal/tracker"
+	"github.com/Checkmarx/kics/pkg/engine"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/scan"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"github.com/pkg/errors"
+)
+
+const (
+	scanID = "query_repl"
+	// Package is the package of the rules defined in the session
+	Package = "repl"
+)
+
+// Session holds the parsed file, the libraries and the rules defined while debugging
+type Session struct {
+	Path          string
+	Platform      string
+	LibrariesPath string
+	QueryTimeout  int
+	// LineInfo binds the documents with the lines information to the input instead of the scanned documents
+	LineInfo bool
+
+	files   model.FileMetadatas
+	common  source.RegoLibraries
+	library source.RegoLibraries
+	store   storage.Store
+	rules   map[string]string
+}
+
+// NewSession parses the file as the scan does and loads the common and platform libraries
+func NewSession(path, platform, librariesPath string, queryTimeout int) (*Session, error) {
+	platformName, libraryName, ok := lookupPlatform(platform)
+	if !ok {
+		return nil, fmt.Errorf("unknown platform %s, supported platforms are %s", platform,
+			strings.Join(source.ListSupportedPlatforms(), ", "))
+	}
+
+	parsers, err := scan.NewParsers("", []string{""}, []string{""})
+	if err != nil {
+		return nil, err
+	}
+	files, err := scan.ParseFile(parsers, scanID, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+
+	librariesSource := source.NewFilesystemSource([]string{}, []string{""}, []string{""}, librariesPath, "")
+	common, err := librariesSource.GetQueryLibrary("common")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load common library")
+	}
+	library, err := librariesSource.GetQueryLibrary(libraryName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load %s library", libraryName)
+	}
+
+	inputData, err := source.MergeInputData(library.LibraryInputData, common.LibraryInputData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to merge libraries input data")
+	}
+
+	return &Session{
+		Path:          path,
+		Platform:      platformName,
+		LibrariesPath: librariesPath,
+		QueryTimeout:  queryTimeout,
+		files:         files,
+		common:        common,
+		library:       library,
+		store:         inmem.NewFromReader(bytes.NewBufferString(inputData)),
+		rules:         make(map[string]string),
+	}, nil
+}
+
+// lookupPlatform returns the platform name used by the queries metadata and the name of its library
+func lookupPlatform(platform string) (name, library string, ok bool) {
+	for name, library := range constants.AvailablePlatforms {
+		if strings.EqualFold(name, platform) || strings.EqualFold(library, platform) {
+			return name, library, true
+		}
+	}
+	return "", "", false
+}
+
+// Input returns the documents bound to input
+func (s *Session) Input() model.Documents {
+	return s.files.Combine(s.LineInfo)
+}
+
+// Define adds the rules of the statement to the repl package, replacing the rules with the same name,
+// it returns false when the statement is not a rule definition
+func (s *Session) Define(statement string) (defined []string, ok bool, err error) {
+	statements, _, err := ast.ParseStatements("", statement)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isDefinition(statements) {
+		return nil, false, nil
+	}
+
+	module, err := ast.ParseModule(Package, fmt.Sprintf("package %s\n\n%s", Package, statement))
+	if err != nil {
+		return nil, false, err
+	}
+
+	rules := make(map[string]string, len(s.rules))
+	for name, rule := range s.rules {
+		rules[name] = rule
+	}
+	for _, rule := range module.Rules {
+		name := rule.Head.Ref().String()
+		rules[name] = statement
+		defined = append(defined, name)
+	}
+
+	// the rules are compiled with the libraries before being kept, so an invalid rule does not break the session
+	if _, err := s.prepare(context.Background(), "true", rules); err != nil {
+		return nil, false, err
+	}
+	s.rules = rules
+	return defined, true, nil
+}
+
+// isDefinition returns true when the statements are rules or a single assignment to a variable
+func isDefinition(statements []ast.Statement) bool {
+	if len(statements) == 0 {
+		return false
+	}
+	for _, statement := range statements {
+		switch x := statement.(type) {
+		case *ast.Rule:
+			continue
+		case ast.Body:
+			if len(x) != 1 || !x[0].IsAssignment() {
+				return false
+			}
+			if _, ok := x[0].Operand(0).Value.(ast.Var); !ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Eval evaluates the query with the input and libraries bound, in the repl package
+func (s *Session) Eval(ctx context.Context, query string) (rego.ResultSet, error) {
+	prepared, err := s.prepare(ctx, query, s.rules)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := s.payload()
+	if err != nil {
+		return nil, err
+	}
+	return prepared.Eval(ctx, rego.EvalParsedInput(input))
+}
+
+func (s *Session) prepare(ctx context.Context, query string, rules map[string]string) (rego.PreparedEvalQuery, error) {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	module := []string{"package " + Package}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		// a statement defining several rules is kept under each of their names
+		if !seen[rules[name]] {
+			module = append(module, rules[name])
+			seen[rules[name]] = true
+		}
+	}
+
+	return rego.New(
+		rego.Query(query),
+		rego.Package(Package),
+		rego.Module("Common", s.common.LibraryCode),
+		rego.Module("Generic", s.library.LibraryCode),
+		rego.Module(Package, strings.Join(module, "\n\n")),
+		rego.Store(s.store),
+	).PrepareForEval(ctx)
+}
+
+// payload returns the input as the inspector builds it
+func (s *Session) payload() (ast.Value, error) {
+	content, err := json.Marshal(s.Input())
+	if err != nil {
+		return nil, err
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(content, &input); err != nil {
+		return nil, err
+	}
+	return ast.InterfaceToValue(input)
+}
+
+// Run runs the queries of the directory on the file and returns their results with the detected lines
+func (s *Session) Run(ctx context.Context, queryDir string) ([]model.Vulnerability, error) {
+	queriesSource := source.NewFilesystemSource([]string{queryDir}, []string{""}, []string{""}, s.LibrariesPath, "")
+	inspector, err := engine.NewInspector(ctx,
+		queriesSource,
+		engine.DefaultVulnerabilityBuilder,
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}, ByCategories: []string{}},
+		},
+		map[string]bool{},
+		s.QueryTimeout,
+		false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load queries of %s", queryDir)
+	}
+
+	if len(inspector.QueryLoader.QueriesMetadata) == 0 {
+		return nil, fmt.Errorf("no queries found in %s", queryDir)
+	}
+	for i := range inspector.QueryLoader.QueriesMetadata {
+		metadata := &inspector.QueryLoader.QueriesMetadata[i]
+		if platform, _ := metadata.Metadata["platform"].(string); !strings.EqualFold(platform, s.Platform) {
+			return nil, fmt.Errorf("query %s is a %s query, the file is parsed as %s", metadata.Query, platform, s.Platform)
+		}
+	}
+
+	currentQuery := make(chan int64, len(inspector.QueryLoader.QueriesMetadata))
+	defer close(currentQuery)
+
+	vulnerabilities, err := inspector.Inspect(ctx, scanID, s.files, []string{filepath.Dir(s.Path)},
+		[]string{s.Platform}, currentQuery)
+	if err != nil {
+		return nil, err
+	}
+	if failedQueries := inspector.GetFailedQueries(); len(failedQueries) > 0 {
+		failures := make([]string, 0, len(failedQueries))
+		for name, err := range failedQueries {
+			failures = append(failures, fmt.Sprintf("query %s failed: %s", name, err))
+		}
+		sort.Strings(failures)
+		return nil, errors.New(strings.Join(failures, "\n"))
+	}
+	return vulnerabilities, nil
+}
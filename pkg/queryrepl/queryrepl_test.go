@@ -0,0 +1,98 @@
+package queryrepl
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	samplePath = filepath.FromSlash("../../assets/queries/terraform/aws/s3_bucket_acl_allows_read_or_write_to_all_users/test/positive1.tf")
+	queryDir   = filepath.FromSlash("../../assets/queries/terraform/aws/s3_bucket_acl_allows_read_or_write_to_all_users")
+)
+
+func newTestSession(t *testing.T) *Session {
+	session, err := NewSession(samplePath, "terraform", "./assets/libraries", 60)
+	require.NoError(t, err)
+	return session
+}
+
+func Test_NewSession(t *testing.T) {
+	session := newTestSession(t)
+	require.Equal(t, "Terraform", session.Platform)
+	require.Len(t, session.Input().Documents, 1)
+
+	_, err := NewSession(samplePath, "unknown", "./assets/libraries", 60)
+	require.EqualError(t, err, "unknown platform unknown, supported platforms are "+
+		"Ansible, AzureResourceManager, Buildah, CICD, CloudFormation, Crossplane, DockerCompose, Dockerfile, "+
+		"GRPC, GoogleDeploymentManager, Knative, Kubernetes, OpenAPI, Pulumi, ServerlessFW, Terraform")
+}
+
+func Test_Eval(t *testing.T) {
+	session := newTestSession(t)
+	ctx := context.Background()
+
+	results, err := session.Eval(ctx, `input.document[0].resource.aws_s3_bucket.positive1.acl`)
+	require.NoError(t, err)
+	require.Equal(t, "public-read", results[0].Expressions[0].Value)
+
+	results, err = session.Eval(ctx, `data.generic.common.valid_key(input.document[0].resource, "aws_s3_bucket")`)
+	require.NoError(t, err)
+	require.Equal(t, true, results[0].Expressions[0].Value)
+
+	session.LineInfo = true
+	results, err = session.Eval(ctx, `input.document[0].resource.aws_s3_bucket.positive1._kics_lines._kics__default._kics_line`)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+}
+
+func Test_Define(t *testing.T) {
+	session := newTestSession(t)
+	ctx := context.Background()
+
+	defined, ok, err := session.Define(`buckets[name] { input.document[_].resource.aws_s3_bucket[name] }`)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"buckets"}, defined)
+
+	defined, ok, err = session.Define(`acl := input.document[0].resource.aws_s3_bucket.positive1.acl`)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"acl"}, defined)
+
+	results, err := session.Eval(ctx, `[buckets, acl]`)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{[]interface{}{"positive1"}, "public-read"}, results[0].Expressions[0].Value)
+
+	// a rule with the same name replaces the previous one
+	_, _, err = session.Define(`acl := "private"`)
+	require.NoError(t, err)
+	results, err = session.Eval(ctx, `acl`)
+	require.NoError(t, err)
+	require.Equal(t, "private", results[0].Expressions[0].Value)
+
+	_, ok, err = session.Define(`count(input.document) > 0`)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = session.Define(`broken { unknown_function(1) }`)
+	require.Error(t, err)
+	results, err = session.Eval(ctx, `acl`)
+	require.NoError(t, err)
+	require.Equal(t, "private", results[0].Expressions[0].Value)
+}
+
+func Test_Run(t *testing.T) {
+	session := newTestSession(t)
+
+	vulnerabilities, err := session.Run(context.Background(), queryDir)
+	require.NoError(t, err)
+	require.Len(t, vulnerabilities, 1)
+	require.Equal(t, "S3 Bucket ACL Allows Read Or Write to All Users", vulnerabilities[0].QueryName)
+	require.Equal(t, 15, vulnerabilities[0].Line)
+
+	_, err = session.Run(context.Background(), filepath.FromSlash("../../assets/queries/k8s/privilege_escalation_allowed"))
+	require.EqualError(t, err, "query privilege_escalation_allowed is a Kubernetes query, the file is parsed as Terraform")
+}
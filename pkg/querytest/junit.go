@@ -0,0 +1,90 @@
+package querytest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Time       string           `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// JUnit returns the JUnit report of the results, a test suite per query with a test case for the positive
+// samples and another for the negative ones
+func JUnit(results []Result) ([]byte, error) {
+	report := junitTestSuites{
+		Name:       "KICS queries tests",
+		TestSuites: make([]junitTestSuite, 0, len(results)),
+	}
+
+	var total time.Duration
+	for i := range results {
+		suite := junitTestSuite{
+			Name:      results[i].Query.String(),
+			TestCases: make([]junitTestCase, 0, len(results[i].Checks)),
+		}
+
+		var suiteTime time.Duration
+		for _, check := range results[i].Checks {
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s %s", results[i].Query.Dir, check.Name),
+				ClassName: results[i].Query.Platform,
+				Time:      seconds(check.Duration),
+			}
+			if len(check.Failures) > 0 {
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d mismatches in the %s samples", len(check.Failures), check.Name),
+					Content: strings.Join(check.Failures, "\n"),
+				}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+			suiteTime += check.Duration
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suite.Time = seconds(suiteTime)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.TestSuites = append(report.TestSuites, suite)
+		total += suiteTime
+	}
+	report.Time = seconds(total)
+
+	content, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func seconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
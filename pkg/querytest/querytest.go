@@ -0,0 +1,333 @@
+// Package querytest runs the positive and negative samples of queries and compares their results with the
+// expected ones, so custom queries can be tested as the KICS queries are
+package querytest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/internal/tracker"
+	"github.com/Checkmarx/kics/pkg/engine"
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/parser"
+	"github.com/Checkmarx/kics/pkg/scan"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	scanID = "test_queries"
+	// ExpectedResultsFileName is the file of the test directory with the expected results of the positive samples
+	ExpectedResultsFileName = "positive_expected_result.json"
+	testDirName             = "test"
+	positivePrefix          = "positive"
+	negativePrefix          = "negative"
+)
+
+// Query is a query directory with its test samples
+type Query struct {
+	Dir      string
+	ID       string
+	Name     string
+	Platform string
+}
+
+// Check is the result of running the positive or negative samples of a query
+type Check struct {
+	Name     string
+	Failures []string
+	Duration time.Duration
+}
+
+// Result is the result of testing a query
+type Result struct {
+	Query  Query
+	Checks []Check
+}
+
+// Passed returns true when all the checks of the query passed
+func (r *Result) Passed() bool {
+	for i := range r.Checks {
+		if len(r.Checks[i].Failures) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Runner runs the queries samples with the scan parsers and the inspector, the coverage of the queries
+// evaluations is aggregated in Coverage when it is set
+type Runner struct {
+	LibrariesPath string
+	QueryTimeout  int
+	Coverage      *coverage.Collector
+	parsers       []*parser.Parser
+}
+
+// NewRunner creates a runner that loads the platform libraries from the libraries path
+func NewRunner(librariesPath string, queryTimeout int) (*Runner, error) {
+	parsers, err := scan.NewParsers("", []string{""}, []string{""})
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{
+		LibrariesPath: librariesPath,
+		QueryTimeout:  queryTimeout,
+		parsers:       parsers,
+	}, nil
+}
+
+// Discover returns the queries found in the paths, sorted by directory
+func Discover(paths []string) ([]Query, error) {
+	queries := make([]Query, 0)
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || entry.Name() != source.QueryFileName {
+				return nil
+			}
+
+			dir := filepath.Dir(p)
+			metadata, err := source.ReadMetadata(dir)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read metadata of query %s", dir)
+			}
+			query := Query{Dir: dir}
+			query.ID, _ = metadata["id"].(string)
+			query.Name, _ = metadata["queryName"].(string)
+			query.Platform, _ = metadata["platform"].(string)
+			queries = append(queries, query)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Dir < queries[j].Dir
+	})
+	return queries, nil
+}
+
+// Run runs the positive samples of the query against its expected results and checks that the negative ones
+// have no results
+func (r *Runner) Run(ctx context.Context, query *Query) Result {
+	result := Result{Query: *query}
+
+	expected, err := readExpectedResults(query.Dir)
+	if err != nil {
+		result.Checks = append(result.Checks, Check{Name: positivePrefix, Failures: []string{err.Error()}})
+	} else {
+		result.Checks = append(result.Checks, r.check(ctx, query, positivePrefix, expected))
+	}
+	result.Checks = append(result.Checks, r.check(ctx, query, negativePrefix, []model.Vulnerability{}))
+
+	return result
+}
+
+func (r *Runner) check(ctx context.Context, query *Query, prefix string, expected []model.Vulnerability) Check {
+	start := time.Now()
+	check := Check{Name: prefix}
+
+	samples, err := sampleFiles(query.Dir, prefix)
+	switch {
+	case err != nil:
+		check.Failures = append(check.Failures, err.Error())
+	case len(samples) == 0:
+		check.Failures = append(check.Failures, fmt.Sprintf("no %s samples found in %s", prefix,
+			filepath.Join(query.Dir, testDirName)))
+	default:
+		actual, failures := r.inspect(ctx, query, samples)
+		check.Failures = append(failures, Compare(expected, actual)...)
+	}
+
+	check.Duration = time.Since(start)
+	return check
+}
+
+// inspect parses the samples and runs the query on them
+func (r *Runner) inspect(ctx context.Context, query *Query, samples []string) (vulnerabilities []model.Vulnerability, failures []string) {
+	files := make(model.FileMetadatas, 0)
+	for _, sample := range samples {
+		sampleFiles, err := scan.ParseFile(r.parsers, scanID, sample)
+		if err != nil {
+			// as in a scan, a sample that can not be parsed has no results
+			log.Warn().Msgf("failed to parse %s: %s", sample, err)
+			continue
+		}
+		files = append(files, sampleFiles...)
+	}
+
+	queriesSource := source.NewFilesystemSource([]string{query.Dir}, []string{""}, []string{""}, r.LibrariesPath, "")
+	inspector, err := engine.NewInspector(ctx,
+		queriesSource,
+		engine.DefaultVulnerabilityBuilder,
+		&tracker.CITracker{},
+		&source.QueryInspectorParameters{
+			IncludeQueries: source.IncludeQueries{ByIDs: []string{query.ID}},
+			ExcludeQueries: source.ExcludeQueries{ByIDs: []string{}, ByCategories: []string{}},
+		},
+		map[string]bool{},
+		r.QueryTimeout,
+		false)
+	if err != nil {
+		return nil, append(failures, fmt.Sprintf("failed to load query: %s", err))
+	}
+
+	if len(inspector.QueryLoader.QueriesMetadata) == 0 {
+		return nil, append(failures, "failed to load query")
+	}
+
+	if r.Coverage != nil {
+		inspector.EnableQueryCoverage(r.Coverage)
+	}
+
+	currentQuery := make(chan int64, len(inspector.QueryLoader.QueriesMetadata))
+	defer close(currentQuery)
+
+	vulnerabilities, err = inspector.Inspect(ctx, scanID, files, []string{query.Dir}, []string{query.Platform}, currentQuery)
+	if err != nil {
+		return nil, append(failures, fmt.Sprintf("failed to run query: %s", err))
+	}
+	for name, err := range inspector.GetFailedQueries() {
+		failures = append(failures, fmt.Sprintf("query %s failed: %s", name, err))
+	}
+
+	return vulnerabilities, failures
+}
+
+// sampleFiles returns the samples of the test directory with the prefix, the files of the sample directories included
+func sampleFiles(queryDir, prefix string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(queryDir, testDirName, prefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if filepath.Base(match) == ExpectedResultsFileName {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			samples = append(samples, match)
+			continue
+		}
+		err = filepath.WalkDir(match, func(p string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() {
+				samples = append(samples, p)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samples, nil
+}
+
+func readExpectedResults(queryDir string) ([]model.Vulnerability, error) {
+	path := filepath.Join(queryDir, testDirName, ExpectedResultsFileName)
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read expected results")
+	}
+
+	var expected []model.Vulnerability
+	if err := json.Unmarshal(content, &expected); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal expected results %s", path)
+	}
+	return expected, nil
+}
+
+// Compare matches the actual results with the expected ones by file name and line, the file name is only
+// compared when the expected result defines it, it returns a message for each mismatch
+func Compare(expected, actual []model.Vulnerability) []string {
+	failures := make([]string, 0)
+	matched := make([]bool, len(actual))
+
+	sortVulnerabilities(expected)
+	sortVulnerabilities(actual)
+
+	for i := range expected {
+		found := -1
+		for j := range actual {
+			if !matched[j] && actual[j].Line == expected[i].Line &&
+				(expected[i].FileName == "" || expected[i].FileName == filepath.Base(actual[j].FileName)) {
+				found = j
+				break
+			}
+		}
+		if found < 0 {
+			failures = append(failures, fmt.Sprintf("missing result %s", location(&expected[i])))
+			continue
+		}
+
+		matched[found] = true
+		if expected[i].Severity != "" && expected[i].Severity != actual[found].Severity {
+			failures = append(failures, fmt.Sprintf("result %s has severity %s, expected %s",
+				location(&expected[i]), actual[found].Severity, expected[i].Severity))
+		}
+		if expected[i].QueryName != "" && expected[i].QueryName != actual[found].QueryName {
+			failures = append(failures, fmt.Sprintf("result %s has query name '%s', expected '%s'",
+				location(&expected[i]), actual[found].QueryName, expected[i].QueryName))
+		}
+	}
+
+	for j := range actual {
+		if !matched[j] {
+			failures = append(failures, fmt.Sprintf("unexpected result %s", location(&actual[j])))
+		}
+	}
+	return failures
+}
+
+func location(vulnerability *model.Vulnerability) string {
+	if vulnerability.FileName == "" {
+		return fmt.Sprintf("in line %d", vulnerability.Line)
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(vulnerability.FileName), vulnerability.Line)
+}
+
+func sortVulnerabilities(vulnerabilities []model.Vulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		fileI, fileJ := filepath.Base(vulnerabilities[i].FileName), filepath.Base(vulnerabilities[j].FileName)
+		if fileI != fileJ {
+			return fileI < fileJ
+		}
+		return vulnerabilities[i].Line < vulnerabilities[j].Line
+	})
+}
+
+// Summary returns the number of queries tested and failed
+func Summary(results []Result) (tested, failed int) {
+	for i := range results {
+		if !results[i].Passed() {
+			failed++
+		}
+	}
+	return len(results), failed
+}
+
+// String returns the name of the query along with its directory
+func (q *Query) String() string {
+	if q.Name == "" {
+		return q.Dir
+	}
+	return fmt.Sprintf("%s (%s)", q.Name, strings.TrimSuffix(filepath.ToSlash(q.Dir), "/"))
+}
@@ -0,0 +1,174 @@
+package querytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+const customQuery = `package Cx
+
+CxPolicy[result] {
+	resource := input.document[i].resource.aws_s3_bucket[name]
+	resource.acl == "public-read"
+
+	result := {
+		"documentId": input.document[i].id,
+		"searchKey": sprintf("aws_s3_bucket[%s].acl", [name]),
+		"issueType": "IncorrectValue",
+		"keyExpectedValue": "acl is private",
+		"keyActualValue": "acl is public-read",
+	}
+}
+`
+
+const customMetadata = `{
+  "id": "7d5b3b8b-6a55-4b9e-9b63-0c2f3b0d5a11",
+  "queryName": "Custom Public Bucket",
+  "severity": "HIGH",
+  "category": "Access Control",
+  "descriptionText": "Buckets should not be public",
+  "descriptionUrl": "https://example.com",
+  "platform": "Terraform",
+  "descriptionID": "7d5b3b8b"
+}
+`
+
+func writeQuery(t *testing.T, expected string) string {
+	dir := filepath.Join(t.TempDir(), "custom_public_bucket")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "test"), os.ModePerm))
+
+	files := map[string]string{
+		"query.rego":                                   customQuery,
+		"metadata.json":                                customMetadata,
+		filepath.Join("test", "positive1.tf"):          "resource \"aws_s3_bucket\" \"b\" {\n  bucket = \"b\"\n  acl    = \"public-read\"\n}\n",
+		filepath.Join("test", "negative1.tf"):          "resource \"aws_s3_bucket\" \"b\" {\n  bucket = \"b\"\n  acl    = \"private\"\n}\n",
+		filepath.Join("test", ExpectedResultsFileName): expected,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), os.ModePerm))
+	}
+	return dir
+}
+
+func Test_Discover(t *testing.T) {
+	dir := writeQuery(t, "[]")
+
+	queries, err := Discover([]string{filepath.Dir(dir)})
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	require.Equal(t, Query{
+		Dir:      dir,
+		ID:       "7d5b3b8b-6a55-4b9e-9b63-0c2f3b0d5a11",
+		Name:     "Custom Public Bucket",
+		Platform: "Terraform",
+	}, queries[0])
+}
+
+func Test_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		expected     string
+		wantFailures []string
+	}{
+		{
+			name:     "expected results",
+			expected: `[{"queryName": "Custom Public Bucket", "severity": "HIGH", "line": 3, "fileName": "positive1.tf"}]`,
+		},
+		{
+			name:     "line mismatch",
+			expected: `[{"queryName": "Custom Public Bucket", "severity": "HIGH", "line": 2, "fileName": "positive1.tf"}]`,
+			wantFailures: []string{
+				"missing result positive1.tf:2",
+				"unexpected result positive1.tf:3",
+			},
+		},
+		{
+			name:         "severity mismatch",
+			expected:     `[{"queryName": "Custom Public Bucket", "severity": "LOW", "line": 3, "fileName": "positive1.tf"}]`,
+			wantFailures: []string{"result positive1.tf:3 has severity HIGH, expected LOW"},
+		},
+	}
+
+	runner, err := NewRunner("./assets/libraries", 60)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries, err := Discover([]string{writeQuery(t, tt.expected)})
+			require.NoError(t, err)
+
+			result := runner.Run(context.Background(), &queries[0])
+			require.Len(t, result.Checks, 2)
+			require.Equal(t, "positive", result.Checks[0].Name)
+			require.ElementsMatch(t, tt.wantFailures, result.Checks[0].Failures)
+			require.Empty(t, result.Checks[1].Failures)
+			require.Equal(t, len(tt.wantFailures) == 0, result.Passed())
+		})
+	}
+}
+
+func Test_RunCoverage(t *testing.T) {
+	runner, err := NewRunner("./assets/libraries", 60)
+	require.NoError(t, err)
+	runner.Coverage = coverage.NewCollector()
+
+	queries, err := Discover([]string{writeQuery(t, `[{"line": 3, "fileName": "positive1.tf"}]`)})
+	require.NoError(t, err)
+	result := runner.Run(context.Background(), &queries[0])
+	require.True(t, result.Passed())
+
+	report := runner.Coverage.Report()
+	require.Len(t, report.Queries, 1)
+	require.Equal(t, "terraform/custom_public_bucket", report.Queries[0].Name)
+	require.Equal(t, float64(100), report.Queries[0].Coverage)
+	require.Len(t, report.Libraries, 2)
+}
+
+func Test_Compare(t *testing.T) {
+	expected := []model.Vulnerability{
+		{QueryName: "q", Line: 3},
+		{QueryName: "q", Line: 5, FileName: "positive2.tf"},
+	}
+	actual := []model.Vulnerability{
+		{QueryName: "q", Line: 5, FileName: filepath.Join("dir", "positive2.tf")},
+		{QueryName: "other", Line: 3, FileName: filepath.Join("dir", "positive1.tf")},
+		{QueryName: "q", Line: 9, FileName: filepath.Join("dir", "positive1.tf")},
+	}
+
+	require.Equal(t, []string{
+		"result in line 3 has query name 'other', expected 'q'",
+		"unexpected result positive1.tf:9",
+	}, Compare(expected, actual))
+}
+
+func Test_JUnit(t *testing.T) {
+	results := []Result{
+		{
+			Query: Query{Dir: "queries/custom", Name: "Custom", Platform: "Terraform"},
+			Checks: []Check{
+				{Name: "positive", Failures: []string{"missing result positive1.tf:2"}},
+				{Name: "negative"},
+			},
+		},
+	}
+
+	content, err := JUnit(results)
+	require.NoError(t, err)
+
+	report := string(content)
+	require.True(t, strings.HasPrefix(report, "<?xml"))
+	require.Contains(t, report, `<testsuites name="KICS queries tests" tests="2" failures="1"`)
+	require.Contains(t, report, `<testcase name="queries/custom positive" classname="Terraform"`)
+	require.Contains(t, report, `<failure message="1 mismatches in the positive samples">missing result positive1.tf:2</failure>`)
+
+	tested, failed := Summary(results)
+	require.Equal(t, 1, tested)
+	require.Equal(t, 1, failed)
+}
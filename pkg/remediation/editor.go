@@ -0,0 +1,219 @@
+package remediation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/detector"
+	"github.com/Checkmarx/kics/pkg/utils"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// errKeyNotFound is returned when the editor can not address the search key, the line based remediation is used instead
+	errKeyNotFound = errors.New("search key not found")
+	// errNotParsed is returned when the file can not be parsed by the editor, e.g. Helm templates, the line based
+	// remediation is used instead
+	errNotParsed = errors.New("file can not be parsed")
+	// errAlreadyRemediated is returned when the remediation is already present in the file
+	errAlreadyRemediated = errors.New("remediation is already done")
+
+	labelSegmentRgx = regexp.MustCompile(`^([^\[\]]+)\[(.+)]$`)
+	placeholderRgx  = regexp.MustCompile(`{{(\d+)}}`)
+)
+
+// Remediation types
+const (
+	ReplacementType = "replacement"
+	AdditionType    = "addition"
+	RemovalType     = "removal"
+	BlockType       = "block"
+)
+
+// segmentKind describes how a search key segment addresses the document
+type segmentKind int
+
+const (
+	// keySegment addresses a key, e.g. 'spec'
+	keySegment segmentKind = iota
+	// labelSegment addresses a labeled block or an indexed item, e.g. 'aws_s3_bucket[b]' or 'containers[0]'
+	labelSegment
+	// valueSegment selects the element where the key has the value, e.g. 'name=nginx'
+	valueSegment
+)
+
+// searchKeySegment is a part of the search key, split by '.'
+type searchKeySegment struct {
+	kind  segmentKind
+	key   string
+	value string
+}
+
+// editor applies remediations to the file content through its syntax, addressing the search key
+// render writes a structured block in the file syntax, so it can be added as a snippet
+type editor interface {
+	replace(content []byte, segments []searchKeySegment, info ReplacementInfo) ([]byte, error)
+	add(content []byte, segments []searchKeySegment, snippet string) ([]byte, error)
+	remove(content []byte, segments []searchKeySegment) ([]byte, error)
+	render(block map[string]interface{}) (string, error)
+}
+
+// getEditor returns the syntax aware editor for the file extension, if any
+func getEditor(filePath string) editor {
+	switch utils.GetExtension(filePath) {
+	case ".tf":
+		return hclEditor{}
+	case ".yaml", ".yml":
+		return yamlEditor{}
+	case ".json":
+		return jsonEditor{}
+	case ".cfg", ".conf", ".ini":
+		return iniEditor{}
+	default:
+		return nil
+	}
+}
+
+// remediateWithEditor applies the remediation through the syntax aware editor of the file, the returned flag is false
+// when the file has no editor, can not be parsed or the search key can not be addressed, so the line based
+// remediation should be used
+func remediateWithEditor(filePath string, lines []string, r *Remediation, remediationType string) ([]string, bool) {
+	e := getEditor(filePath)
+	searchKey := r.SearchKey
+	if r.ValueKey != "" {
+		searchKey = r.ValueKey
+	}
+	if e == nil || searchKey == "" {
+		return nil, false
+	}
+	segments := parseSearchKey(searchKey)
+	content := []byte(strings.Join(lines, "\n"))
+
+	var remediated []byte
+	var err error
+	switch remediationType {
+	case ReplacementType:
+		var info ReplacementInfo
+		if err = json.Unmarshal([]byte(r.Remediation), &info); err != nil || info == (ReplacementInfo{}) {
+			return []string{}, true
+		}
+		remediated, err = e.replace(content, segments, info)
+	case AdditionType:
+		remediated, err = e.add(content, segments, r.Remediation)
+	case RemovalType:
+		remediated, err = e.remove(content, segments)
+	case BlockType:
+		var snippet string
+		if snippet, err = renderBlock(e, r.Remediation); err == nil {
+			remediated, err = e.add(content, segments, snippet)
+		}
+	default:
+		return nil, false
+	}
+
+	switch {
+	case errors.Is(err, errKeyNotFound) || errors.Is(err, errNotParsed):
+		log.Debug().Msgf("search key '%s' not found in '%s', using line remediation", searchKey, filePath)
+		return nil, false
+	case errors.Is(err, errAlreadyRemediated):
+		log.Info().Msgf("remediation '%s' is already done", r.SimilarityID)
+		return []string{}, true
+	case err != nil:
+		log.Error().Msgf("failed to apply remediation '%s': %s", r.SimilarityID, err)
+		return []string{}, true
+	}
+	return strings.Split(string(remediated), "\n"), true
+}
+
+// renderBlock writes the block remediation, a JSON object, in the syntax of the editor
+func renderBlock(e editor, remediation string) (string, error) {
+	var block map[string]interface{}
+	if err := json.Unmarshal([]byte(remediation), &block); err != nil {
+		return "", fmt.Errorf("block remediation is not a JSON object: %w", err)
+	}
+	if len(block) == 0 {
+		return "", errors.New("block remediation is empty")
+	}
+	return e.render(block)
+}
+
+// sortedKeys returns the keys of the block sorted, so blocks are rendered in the same way
+func sortedKeys(block map[string]interface{}) []string {
+	keys := make([]string, 0, len(block))
+	for key := range block {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseSearchKey splits the search key in segments, values inside '{{ }}' are kept as a whole
+func parseSearchKey(searchKey string) []searchKeySegment {
+	extracted := detector.GetBracketValues(searchKey, [][]string{}, "")
+	sanitized := searchKey
+	for idx, str := range extracted {
+		sanitized = strings.Replace(sanitized, str[0], `{{`+strconv.Itoa(idx)+`}}`, -1)
+	}
+	restore := func(value string) string {
+		return placeholderRgx.ReplaceAllStringFunc(value, func(placeholder string) string {
+			idx, err := strconv.Atoi(placeholderRgx.FindStringSubmatch(placeholder)[1])
+			if err != nil || idx >= len(extracted) {
+				return placeholder
+			}
+			return extracted[idx][1]
+		})
+	}
+
+	segments := make([]searchKeySegment, 0)
+	for _, part := range strings.Split(sanitized, ".") {
+		if part == "" {
+			continue
+		}
+		if groups := labelSegmentRgx.FindStringSubmatch(part); groups != nil {
+			segments = append(segments, searchKeySegment{kind: labelSegment, key: restore(groups[1]), value: restore(groups[2])})
+			continue
+		}
+		if keyValue := strings.SplitN(part, "=", 2); len(keyValue) == 2 {
+			segments = append(segments, searchKeySegment{kind: valueSegment, key: restore(keyValue[0]), value: restore(keyValue[1])})
+			continue
+		}
+		segments = append(segments, searchKeySegment{kind: keySegment, key: restore(part)})
+	}
+	return segments
+}
+
+// dedent removes the common indentation of the snippet lines and the trailing blank lines
+func dedent(snippet string) []string {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(snippet, "\t", "  "), " \n"), "\n")
+	indentation := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if lineIndent := len(line) - len(strings.TrimLeft(line, " ")); indentation < 0 || lineIndent < indentation {
+			indentation = lineIndent
+		}
+	}
+	for idx := range lines {
+		if len(lines[idx]) >= indentation && indentation > 0 {
+			lines[idx] = lines[idx][indentation:]
+		}
+	}
+	return lines
+}
+
+// indent prefixes the non blank lines with the indentation
+func indent(lines []string, indentation string) []string {
+	indented := make([]string, len(lines))
+	for idx, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			indented[idx] = indentation + line
+		}
+	}
+	return indented
+}
@@ -0,0 +1,292 @@
+package remediation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const hclIndentation = "  "
+
+// hclTopLevelLabeledBlocks are the block types addressed by their label instead of 'resource' or 'data' blocks
+var hclTopLevelLabeledBlocks = map[string]bool{
+	"module":   true,
+	"variable": true,
+	"output":   true,
+	"provider": true,
+}
+
+// hclEditor edits Terraform files through hclwrite, keeping comments and formatting
+type hclEditor struct{}
+
+// hclTarget is the element addressed by a search key, an attribute or a block
+// parent is the body holding the block and nested is true when the search key addresses a key inside the attribute
+type hclTarget struct {
+	body      *hclwrite.Body
+	parent    *hclwrite.Body
+	block     *hclwrite.Block
+	attribute string
+	nested    bool
+	depth     int
+}
+
+func (e hclEditor) replace(content []byte, segments []searchKeySegment, info ReplacementInfo) ([]byte, error) {
+	file, err := parseHCL(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errNotParsed, err)
+	}
+	target, ok := resolveHCL(file.Body(), segments)
+	if !ok || target.attribute == "" {
+		return nil, errKeyNotFound
+	}
+
+	expression := string(target.body.GetAttribute(target.attribute).Expr().BuildTokens(nil).Bytes())
+	if !strings.Contains(expression, info.Before) {
+		if strings.Contains(expression, info.After) {
+			return nil, errAlreadyRemediated
+		}
+		return nil, errKeyNotFound
+	}
+
+	replaced, err := parseHCL([]byte("value = " + strings.Replace(expression, info.Before, info.After, 1) + "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid replacement expression: %w", err)
+	}
+	target.body.SetAttributeRaw(target.attribute, replaced.Body().GetAttribute("value").Expr().BuildTokens(nil))
+
+	return formatHCL(content, file.Bytes()), nil
+}
+
+func (e hclEditor) add(content []byte, segments []searchKeySegment, snippet string) ([]byte, error) {
+	file, err := parseHCL(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errNotParsed, err)
+	}
+	target, ok := resolveHCL(file.Body(), segments)
+	if !ok {
+		return nil, errKeyNotFound
+	}
+
+	formatted := hclwrite.Format([]byte(strings.Join(dedent(snippet), "\n") + "\n"))
+	addition, err := parseHCL(formatted)
+	if err != nil {
+		return nil, fmt.Errorf("invalid addition: %w", err)
+	}
+	if hclContains(target.body, addition.Body()) {
+		return nil, errAlreadyRemediated
+	}
+
+	lines := indent(strings.Split(strings.TrimRight(string(formatted), "\n"), "\n"),
+		strings.Repeat(hclIndentation, target.depth))
+	indented, err := parseHCL([]byte(strings.Join(lines, "\n") + "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid addition: %w", err)
+	}
+	target.body.AppendUnstructuredTokens(indented.BuildTokens(nil))
+
+	return formatHCL(content, file.Bytes()), nil
+}
+
+func (e hclEditor) remove(content []byte, segments []searchKeySegment) ([]byte, error) {
+	file, err := parseHCL(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errNotParsed, err)
+	}
+	target, ok := resolveHCL(file.Body(), segments)
+	if !ok {
+		if len(segments) > 1 {
+			if parent, found := resolveHCL(file.Body(), segments[:len(segments)-1]); found && parent.attribute == "" {
+				return nil, errAlreadyRemediated
+			}
+		}
+		return nil, errKeyNotFound
+	}
+
+	switch {
+	case target.nested:
+		return nil, errKeyNotFound
+	case target.attribute != "":
+		target.body.RemoveAttribute(target.attribute)
+	default:
+		target.parent.RemoveBlock(target.block)
+	}
+
+	return formatHCL(content, file.Bytes()), nil
+}
+
+// render writes the objects as blocks, lists of objects as repeated blocks and the remaining values as attributes
+func (e hclEditor) render(block map[string]interface{}) (string, error) {
+	file := hclwrite.NewEmptyFile()
+	if err := renderHCLBody(file.Body(), block); err != nil {
+		return "", err
+	}
+	return string(hclwrite.Format(file.Bytes())), nil
+}
+
+func renderHCLBody(body *hclwrite.Body, values map[string]interface{}) error {
+	blocks := make([]string, 0)
+	for _, key := range sortedKeys(values) {
+		if hclBlockValues(values[key]) != nil {
+			blocks = append(blocks, key)
+			continue
+		}
+		encoded, err := json.Marshal(values[key])
+		if err != nil {
+			return err
+		}
+		ctyType, err := ctyjson.ImpliedType(encoded)
+		if err != nil {
+			return err
+		}
+		value, err := ctyjson.Unmarshal(encoded, ctyType)
+		if err != nil {
+			return err
+		}
+		body.SetAttributeValue(key, value)
+	}
+
+	for _, key := range blocks {
+		for _, nested := range hclBlockValues(values[key]) {
+			if err := renderHCLBody(body.AppendNewBlock(key, nil).Body(), nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hclBlockValues returns the objects to be rendered as blocks, or nil when the value is an attribute
+func hclBlockValues(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		objects := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			objects = append(objects, object)
+		}
+		if len(objects) == 0 {
+			return nil
+		}
+		return objects
+	default:
+		return nil
+	}
+}
+
+func parseHCL(content []byte) (*hclwrite.File, error) {
+	file, diagnostics := hclwrite.ParseConfig(content, "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+	return file, nil
+}
+
+// formatHCL formats the remediated content when the original content was already formatted,
+// so unformatted files are not rewritten as a whole
+func formatHCL(original, remediated []byte) []byte {
+	if bytes.Equal(hclwrite.Format(original), original) {
+		return hclwrite.Format(remediated)
+	}
+	return remediated
+}
+
+// hclContains returns true when all the attributes and blocks of the addition are already defined in the body
+func hclContains(body, addition *hclwrite.Body) bool {
+	for name := range addition.Attributes() {
+		if body.GetAttribute(name) == nil {
+			return false
+		}
+	}
+	for _, block := range addition.Blocks() {
+		if body.FirstMatchingBlock(block.Type(), block.Labels()) == nil {
+			return false
+		}
+	}
+	return len(addition.Attributes()) > 0 || len(addition.Blocks()) > 0
+}
+
+// resolveHCL finds the block or attribute addressed by the search key, e.g. 'aws_s3_bucket[b].versioning.enabled'
+func resolveHCL(body *hclwrite.Body, segments []searchKeySegment) (*hclTarget, bool) {
+	if len(segments) == 0 {
+		return nil, false
+	}
+	first := segments[0]
+	rest := segments[1:]
+
+	var candidates []*hclwrite.Block
+	switch {
+	case first.kind == keySegment && (first.key == "resource" || first.key == "data") &&
+		len(rest) > 0 && rest[0].kind == labelSegment:
+		candidates = hclBlocks(body, first.key, []string{rest[0].key, rest[0].value})
+		rest = rest[1:]
+	case first.kind == labelSegment && hclTopLevelLabeledBlocks[first.key]:
+		candidates = hclBlocks(body, first.key, []string{first.value})
+	case first.kind == labelSegment:
+		candidates = append(hclBlocks(body, "resource", []string{first.key, first.value}),
+			hclBlocks(body, "data", []string{first.key, first.value})...)
+	default:
+		candidates = hclBlocks(body, first.key, nil)
+	}
+
+	for _, block := range candidates {
+		if target, ok := resolveHCLBlock(body, block, rest, 1); ok {
+			return target, true
+		}
+	}
+	return nil, false
+}
+
+func resolveHCLBlock(parent *hclwrite.Body, block *hclwrite.Block, segments []searchKeySegment,
+	depth int) (*hclTarget, bool) {
+	body := block.Body()
+	if len(segments) == 0 {
+		return &hclTarget{body: body, parent: parent, block: block, depth: depth}, true
+	}
+	segment := segments[0]
+	// attributes hold objects, which are not addressed further
+	if body.GetAttribute(segment.key) != nil {
+		return &hclTarget{body: body, attribute: segment.key, nested: len(segments) > 1, depth: depth}, true
+	}
+
+	var labels []string
+	if segment.kind == labelSegment {
+		labels = []string{segment.value}
+	}
+	for _, nested := range hclBlocks(body, segment.key, labels) {
+		if target, ok := resolveHCLBlock(body, nested, segments[1:], depth+1); ok {
+			return target, true
+		}
+	}
+	return nil, false
+}
+
+// hclBlocks returns the blocks of the given type whose labels start with the given labels
+func hclBlocks(body *hclwrite.Body, blockType string, labels []string) []*hclwrite.Block {
+	blocks := make([]*hclwrite.Block, 0)
+	for _, block := range body.Blocks() {
+		if block.Type() != blockType || len(block.Labels()) < len(labels) {
+			continue
+		}
+		matches := true
+		for idx := range labels {
+			if block.Labels()[idx] != labels[idx] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
@@ -0,0 +1,255 @@
+package remediation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const hclContent = `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+  versioning {
+    enabled = false
+  }
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "public-read"
+}
+`
+
+func Test_HCLEditorReplace(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		info      ReplacementInfo
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "nested_block_attribute",
+			searchKey: "aws_s3_bucket[b].versioning.enabled",
+			info:      ReplacementInfo{Before: "false", After: "true"},
+			want: `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+  versioning {
+    enabled = true
+  }
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "public-read"
+}
+`,
+		},
+		{
+			name:      "resource_prefix",
+			searchKey: "resource.aws_s3_bucket[c].acl",
+			info:      ReplacementInfo{Before: "public-read", After: "private"},
+			want: `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+  versioning {
+    enabled = false
+  }
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "private"
+}
+`,
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "aws_s3_bucket[b].acl",
+			info:      ReplacementInfo{Before: "private", After: "public-read"},
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "missing_attribute",
+			searchKey: "aws_s3_bucket[b].policy",
+			info:      ReplacementInfo{Before: "a", After: "b"},
+			wantErr:   errKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hclEditor{}.replace([]byte(hclContent), parseSearchKey(tt.searchKey), tt.info)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_HCLEditorAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		snippet   string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "attribute_in_nested_block",
+			searchKey: "aws_s3_bucket[b].versioning",
+			snippet:   "mfa_delete = true",
+			want: `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+  versioning {
+    enabled    = false
+    mfa_delete = true
+  }
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "public-read"
+}
+`,
+		},
+		{
+			name:      "block",
+			searchKey: "aws_s3_bucket[c]",
+			snippet:   "logging {\n\t\ttarget_bucket = \"logs\"\n\t}",
+			want: `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+  versioning {
+    enabled = false
+  }
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "public-read"
+  logging {
+    target_bucket = "logs"
+  }
+}
+`,
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "aws_s3_bucket[b]",
+			snippet:   "versioning {\n  enabled = true\n}",
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "missing_resource",
+			searchKey: "aws_s3_bucket[d]",
+			snippet:   "acl = \"private\"",
+			wantErr:   errKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hclEditor{}.add([]byte(hclContent), parseSearchKey(tt.searchKey), tt.snippet)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_HCLEditorRemove(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "attribute",
+			searchKey: "aws_s3_bucket[b].versioning.enabled",
+			want: `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+  versioning {
+  }
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "public-read"
+}
+`,
+		},
+		{
+			name:      "block",
+			searchKey: "aws_s3_bucket[b].versioning",
+			want: `# buckets
+resource "aws_s3_bucket" "b" {
+  acl = "public-read" # website
+
+}
+
+resource "aws_s3_bucket" "c" {
+  acl = "public-read"
+}
+`,
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "aws_s3_bucket[c].versioning",
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "missing_resource",
+			searchKey: "aws_s3_bucket[d].acl",
+			wantErr:   errKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hclEditor{}.remove([]byte(hclContent), parseSearchKey(tt.searchKey))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_HCLEditorRender(t *testing.T) {
+	got, err := renderBlock(hclEditor{},
+		`{"server_side_encryption_configuration":{"rule":{"apply_server_side_encryption_by_default":{"sse_algorithm":"AES256"},"bucket_key_enabled":true}}}`)
+	require.NoError(t, err)
+	require.Equal(t, `server_side_encryption_configuration {
+  rule {
+    bucket_key_enabled = true
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "AES256"
+    }
+  }
+}
+`, got)
+
+	got, err = renderBlock(hclEditor{}, `{"ingress":[{"from_port":443},{"from_port":80}],"tags":["a","b"]}`)
+	require.NoError(t, err)
+	require.Equal(t, `tags = ["a", "b"]
+ingress {
+  from_port = 443
+}
+ingress {
+  from_port = 80
+}
+`, got)
+
+	_, err = renderBlock(hclEditor{}, `"versioning"`)
+	require.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package remediation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// iniEditor edits INI files, such as ansible.cfg, line by line, the search key addresses the section and the key,
+// e.g. 'defaults.become' or '[galaxy].server'
+type iniEditor struct{}
+
+// iniTarget is the region of the section addressed by a search key and the line of its key, if any
+// start is the section header line, or -1 for the keys defined before any section, and end is its last line
+type iniTarget struct {
+	start int
+	end   int
+	key   int
+}
+
+func (e iniEditor) replace(content []byte, segments []searchKeySegment, info ReplacementInfo) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	target, ok := resolveINI(lines, segments)
+	if !ok || target.key < 0 {
+		return nil, errKeyNotFound
+	}
+
+	separator := strings.IndexAny(lines[target.key], "=:")
+	value := lines[target.key][separator+1:]
+	// INI values are case insensitive for booleans, e.g. 'True' and 'true'
+	idx := strings.Index(strings.ToLower(value), strings.ToLower(info.Before))
+	if idx < 0 {
+		if strings.Contains(strings.ToLower(value), strings.ToLower(info.After)) {
+			return nil, errAlreadyRemediated
+		}
+		return nil, errKeyNotFound
+	}
+	lines[target.key] = lines[target.key][:separator+1] + value[:idx] + info.After + value[idx+len(info.Before):]
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+func (e iniEditor) add(content []byte, segments []searchKeySegment, snippet string) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	target, ok := resolveINI(lines, segments)
+	if !ok {
+		return nil, errKeyNotFound
+	}
+
+	addition := make([]string, 0)
+	present := true
+	for _, line := range dedent(snippet) {
+		key, isKey := iniKey(line)
+		if !isKey {
+			return nil, fmt.Errorf("addition is not an INI option: %s", snippet)
+		}
+		if iniKeyLine(lines, target, key) < 0 {
+			present = false
+		}
+		addition = append(addition, strings.TrimSpace(line))
+	}
+	if len(addition) == 0 {
+		return nil, fmt.Errorf("addition is not an INI option: %s", snippet)
+	}
+	if present {
+		return nil, errAlreadyRemediated
+	}
+
+	// the options are added after the addressed key or the last option of the section
+	position := target.key
+	if position < 0 {
+		position = target.end
+		for position > target.start && strings.TrimSpace(lines[position]) == "" {
+			position--
+		}
+	}
+	remediated := append(append(append([]string{}, lines[:position+1]...), addition...), lines[position+1:]...)
+	return []byte(strings.Join(remediated, "\n")), nil
+}
+
+func (e iniEditor) remove(content []byte, segments []searchKeySegment) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	target, ok := resolveINI(lines, segments)
+	if !ok {
+		return nil, errAlreadyRemediated
+	}
+	start, end := target.key, target.key
+	if target.key < 0 {
+		// the key is already missing from the section, a section is only removed when it is addressed alone
+		if len(segments) > 1 || target.start < 0 {
+			return nil, errAlreadyRemediated
+		}
+		start, end = target.start, target.end
+	}
+	return []byte(strings.Join(append(lines[:start:start], lines[end+1:]...), "\n")), nil
+}
+
+func (e iniEditor) render(block map[string]interface{}) (string, error) {
+	lines := make([]string, 0, len(block))
+	for _, key := range sortedKeys(block) {
+		switch value := block[key].(type) {
+		case map[string]interface{}, []interface{}:
+			return "", fmt.Errorf("INI option '%s' can not hold nested values", key)
+		case nil:
+			lines = append(lines, key+" =")
+		default:
+			lines = append(lines, fmt.Sprintf("%s = %v", key, value))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// resolveINI finds the section addressed by the first segment and the key addressed by the second one, a search key
+// of a single segment addresses a key defined before any section when there is no section with that name
+func resolveINI(lines []string, segments []searchKeySegment) (*iniTarget, bool) {
+	if len(segments) == 0 || len(segments) > 2 {
+		return nil, false
+	}
+	section := strings.Trim(segments[0].key, "[]")
+	target, ok := iniSection(lines, section)
+	if len(segments) == 1 {
+		if ok {
+			return target, true
+		}
+		target, _ = iniSection(lines, "")
+		target.key = iniKeyLine(lines, target, section)
+		return target, target.key >= 0
+	}
+	if !ok {
+		return nil, false
+	}
+	target.key = iniKeyLine(lines, target, segments[1].key)
+	return target, true
+}
+
+// iniSection returns the lines of the section, the empty section name addresses the keys defined before any section
+func iniSection(lines []string, section string) (*iniTarget, bool) {
+	target := &iniTarget{start: -1, end: -1, key: -1}
+	found := section == ""
+	for idx, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			if found {
+				return target, true
+			}
+			if strings.TrimSpace(trimmed[1:len(trimmed)-1]) == section {
+				target.start = idx
+				found = true
+			}
+		}
+		if found {
+			target.end = idx
+		}
+	}
+	return target, found
+}
+
+// iniKeyLine returns the line of the key in the section, or -1
+func iniKeyLine(lines []string, target *iniTarget, key string) int {
+	for idx := target.start + 1; idx <= target.end; idx++ {
+		if lineKey, ok := iniKey(lines[idx]); ok && lineKey == key {
+			return idx
+		}
+	}
+	return -1
+}
+
+// iniKey returns the key of an option line, comments and section headers are not options
+func iniKey(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") ||
+		strings.HasPrefix(trimmed, "[") {
+		return "", false
+	}
+	separator := strings.IndexAny(trimmed, "=:")
+	if separator < 0 {
+		return trimmed, true
+	}
+	return strings.TrimSpace(trimmed[:separator]), true
+}
@@ -0,0 +1,156 @@
+package remediation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const iniContent = `[defaults]
+# privilege escalation
+become=False
+become_user=root
+
+[galaxy]
+server=http://galaxy.ansible.com
+`
+
+func Test_INIEditorReplace(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		info      ReplacementInfo
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "boolean_is_case_insensitive",
+			searchKey: "defaults.become",
+			info:      ReplacementInfo{Before: "false", After: "True"},
+			want:      "[defaults]\n# privilege escalation\nbecome=True\nbecome_user=root\n\n[galaxy]\nserver=http://galaxy.ansible.com\n",
+		},
+		{
+			name:      "bracketed_section",
+			searchKey: "[galaxy].server",
+			info:      ReplacementInfo{Before: "http://", After: "https://"},
+			want:      "[defaults]\n# privilege escalation\nbecome=False\nbecome_user=root\n\n[galaxy]\nserver=https://galaxy.ansible.com\n",
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "defaults.become_user",
+			info:      ReplacementInfo{Before: "admin", After: "root"},
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "key_of_another_section",
+			searchKey: "galaxy.become",
+			info:      ReplacementInfo{Before: "False", After: "True"},
+			wantErr:   errKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := iniEditor{}.replace([]byte(iniContent), parseSearchKey(tt.searchKey), tt.info)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_INIEditorAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		snippet   string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "end_of_section",
+			searchKey: "defaults",
+			snippet:   "no_log = True",
+			want:      "[defaults]\n# privilege escalation\nbecome=False\nbecome_user=root\nno_log = True\n\n[galaxy]\nserver=http://galaxy.ansible.com\n",
+		},
+		{
+			name:      "after_key",
+			searchKey: "galaxy.server",
+			snippet:   "ignore_certs = False",
+			want:      "[defaults]\n# privilege escalation\nbecome=False\nbecome_user=root\n\n[galaxy]\nserver=http://galaxy.ansible.com\nignore_certs = False\n",
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "defaults.become_user",
+			snippet:   "become = True",
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "missing_section",
+			searchKey: "privilege_escalation",
+			snippet:   "become = True",
+			wantErr:   errKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := iniEditor{}.add([]byte(iniContent), parseSearchKey(tt.searchKey), tt.snippet)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_INIEditorRemove(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "key",
+			searchKey: "defaults.become_user",
+			want:      "[defaults]\n# privilege escalation\nbecome=False\n\n[galaxy]\nserver=http://galaxy.ansible.com\n",
+		},
+		{
+			name:      "section",
+			searchKey: "galaxy",
+			want:      "[defaults]\n# privilege escalation\nbecome=False\nbecome_user=root\n",
+		},
+		{
+			name:      "missing_key",
+			searchKey: "galaxy.ignore_certs",
+			wantErr:   errAlreadyRemediated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := iniEditor{}.remove([]byte(iniContent), parseSearchKey(tt.searchKey))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_INIEditorRender(t *testing.T) {
+	got, err := iniEditor{}.render(map[string]interface{}{"become": true, "become_user": "root"})
+	require.NoError(t, err)
+	require.Equal(t, "become = true\nbecome_user = root", got)
+
+	_, err = iniEditor{}.render(map[string]interface{}{"become": map[string]interface{}{"user": "root"}})
+	require.Error(t, err)
+}
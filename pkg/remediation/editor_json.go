@@ -0,0 +1,185 @@
+package remediation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const jsonIndentation = "  "
+
+// jsonEditor edits JSON files keeping the keys order and formatting, the values are addressed through the
+// yaml.v3 nodes positions since JSON documents are also YAML documents
+type jsonEditor struct{}
+
+func (e jsonEditor) replace(content []byte, segments []searchKeySegment, info ReplacementInfo) ([]byte, error) {
+	target, err := resolveYAMLDocuments(content, segments)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(content), "\n")
+	start := lineColumnOffset(lines, target.node.Line-1, target.node.Column-1)
+	end := jsonValueEnd(string(content), start)
+
+	region := string(content[start:end])
+	if !strings.Contains(region, info.Before) {
+		if strings.Contains(region, info.After) {
+			return nil, errAlreadyRemediated
+		}
+		return nil, errKeyNotFound
+	}
+	return validateJSON(string(content[:start]) + strings.Replace(region, info.Before, info.After, 1) + string(content[end:]))
+}
+
+func (e jsonEditor) add(content []byte, segments []searchKeySegment, snippet string) ([]byte, error) {
+	target, err := resolveYAMLDocuments(content, segments)
+	if err != nil {
+		return nil, err
+	}
+	if target.node.Kind != yaml.MappingNode {
+		return nil, errKeyNotFound
+	}
+
+	object := strings.TrimSpace(snippet)
+	if !strings.HasPrefix(object, "{") {
+		object = "{" + object + "}"
+	}
+	if !json.Valid([]byte(object)) {
+		return nil, fmt.Errorf("addition is not a JSON object member: %s", snippet)
+	}
+	var addition yaml.Node
+	if err = yaml.Unmarshal([]byte(object), &addition); err != nil || len(addition.Content) == 0 {
+		return nil, fmt.Errorf("addition is not a JSON object member: %s", snippet)
+	}
+	if yamlContains(target.node, addition.Content[0]) {
+		return nil, errAlreadyRemediated
+	}
+
+	text := string(content)
+	lines := strings.Split(text, "\n")
+	open := lineColumnOffset(lines, target.node.Line-1, target.node.Column-1)
+	objectIndentation := lineIndentation(lines[target.node.Line-1])
+
+	if len(target.node.Content) == 0 {
+		memberIndentation := objectIndentation + jsonIndentation
+		members, err := jsonMembers(object, addition.Content[0], memberIndentation)
+		if err != nil {
+			return nil, err
+		}
+		closing := matchingBracket(text, open)
+		return validateJSON(text[:open+1] + "\n" + memberIndentation + strings.Join(members, ",\n"+memberIndentation) +
+			"\n" + objectIndentation + text[closing:])
+	}
+
+	lastKey := target.node.Content[len(target.node.Content)-2]
+	lastValue := target.node.Content[len(target.node.Content)-1]
+	memberIndentation := lineIndentation(lines[lastKey.Line-1])
+	members, err := jsonMembers(object, addition.Content[0], memberIndentation)
+	if err != nil {
+		return nil, err
+	}
+	end := jsonValueEnd(text, lineColumnOffset(lines, lastValue.Line-1, lastValue.Column-1))
+	separator := ",\n" + memberIndentation
+	if lastKey.Line == target.node.Line {
+		// members in the same line as the object braces are kept in the same line
+		separator = ", "
+	}
+	return validateJSON(text[:end] + separator + strings.Join(members, separator) + text[end:])
+}
+
+func (e jsonEditor) remove(content []byte, segments []searchKeySegment) ([]byte, error) {
+	target, err := resolveYAMLDocuments(content, segments)
+	if err != nil {
+		if len(segments) > 1 {
+			if _, parentErr := resolveYAMLDocuments(content, segments[:len(segments)-1]); parentErr == nil {
+				return nil, errAlreadyRemediated
+			}
+		}
+		return nil, errKeyNotFound
+	}
+	if target.key == nil {
+		return nil, errKeyNotFound
+	}
+
+	text := string(content)
+	lines := strings.Split(text, "\n")
+	start := lineColumnOffset(lines, target.key.Line-1, target.key.Column-1)
+	end := jsonValueEnd(text, lineColumnOffset(lines, target.node.Line-1, target.node.Column-1))
+
+	// the member is removed with its comma, the following one or the previous one when it is the last member
+	if next := len(text) - len(strings.TrimLeft(text[end:], " \t\r\n")); next < len(text) && text[next] == ',' {
+		following := len(text) - len(strings.TrimLeft(text[next+1:], " \t\r\n"))
+		return validateJSON(text[:start] + text[following:])
+	}
+	previous := strings.TrimRight(text[:start], " \t\r\n")
+	return validateJSON(strings.TrimSuffix(previous, ",") + text[end:])
+}
+
+func (e jsonEditor) render(block map[string]interface{}) (string, error) {
+	rendered, err := json.Marshal(block)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// jsonMembers returns the raw members of the addition object, with the values indented for the insertion place
+func jsonMembers(object string, mapping *yaml.Node, indentation string) ([]string, error) {
+	lines := strings.Split(object, "\n")
+	members := make([]string, 0, len(mapping.Content)/2)
+	for idx := 0; idx+1 < len(mapping.Content); idx += 2 {
+		keyStart := lineColumnOffset(lines, mapping.Content[idx].Line-1, mapping.Content[idx].Column-1)
+		valueStart := lineColumnOffset(lines, mapping.Content[idx+1].Line-1, mapping.Content[idx+1].Column-1)
+		keyEnd := jsonValueEnd(object, keyStart)
+
+		var value bytes.Buffer
+		if err := json.Indent(&value, []byte(object[valueStart:jsonValueEnd(object, valueStart)]), indentation,
+			jsonIndentation); err != nil {
+			return nil, err
+		}
+		members = append(members, object[keyStart:keyEnd]+": "+value.String())
+	}
+	return members, nil
+}
+
+// jsonValueEnd returns the offset after the JSON value starting at the start offset
+func jsonValueEnd(content string, start int) int {
+	if start >= len(content) {
+		return len(content)
+	}
+	switch content[start] {
+	case '{', '[':
+		if end := matchingBracket(content, start); end >= 0 {
+			return end + 1
+		}
+		return len(content)
+	case '"':
+		for idx := start + 1; idx < len(content); idx++ {
+			if content[idx] == '\\' {
+				idx++
+			} else if content[idx] == '"' {
+				return idx + 1
+			}
+		}
+		return len(content)
+	default:
+		if end := strings.IndexAny(content[start:], ",}] \t\r\n"); end >= 0 {
+			return start + end
+		}
+		return len(content)
+	}
+}
+
+func lineIndentation(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func validateJSON(content string) ([]byte, error) {
+	if !json.Valid([]byte(content)) {
+		return nil, fmt.Errorf("remediation produces invalid JSON")
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,137 @@
+package remediation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseSearchKey(t *testing.T) {
+	tests := []struct {
+		searchKey string
+		want      []searchKeySegment
+	}{
+		{
+			searchKey: "resource.aws_s3_bucket[b].versioning.enabled",
+			want: []searchKeySegment{
+				{kind: keySegment, key: "resource"},
+				{kind: labelSegment, key: "aws_s3_bucket", value: "b"},
+				{kind: keySegment, key: "versioning"},
+				{kind: keySegment, key: "enabled"},
+			},
+		},
+		{
+			searchKey: "metadata.name={{web.pod}}.spec.containers.name={{nginx}}.securityContext",
+			want: []searchKeySegment{
+				{kind: keySegment, key: "metadata"},
+				{kind: valueSegment, key: "name", value: "web.pod"},
+				{kind: keySegment, key: "spec"},
+				{kind: keySegment, key: "containers"},
+				{kind: valueSegment, key: "name", value: "nginx"},
+				{kind: keySegment, key: "securityContext"},
+			},
+		},
+		{
+			searchKey: "name={{Create bucket}}.{{amazon.aws.s3_bucket}}.encryption",
+			want: []searchKeySegment{
+				{kind: valueSegment, key: "name", value: "Create bucket"},
+				{kind: keySegment, key: "amazon.aws.s3_bucket"},
+				{kind: keySegment, key: "encryption"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.searchKey, func(t *testing.T) {
+			require.Equal(t, tt.want, parseSearchKey(tt.searchKey))
+		})
+	}
+}
+
+func Test_RemediateWithEditor(t *testing.T) {
+	tests := []struct {
+		name            string
+		filePath        string
+		content         string
+		remediation     Remediation
+		remediationType string
+		want            string
+		wantHandled     bool
+	}{
+		{
+			name:     "unsupported_file",
+			filePath: "Dockerfile",
+			content:  "FROM alpine\n",
+			remediation: Remediation{
+				SearchKey:   "FROM={{alpine}}",
+				Remediation: "USER app",
+			},
+			remediationType: AdditionType,
+			wantHandled:     false,
+		},
+		{
+			name:     "search_key_not_found",
+			filePath: "main.tf",
+			content:  "resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n}\n",
+			remediation: Remediation{
+				SearchKey:   "aws_s3_bucket[c].acl",
+				Remediation: `{"before":"private","after":"public"}`,
+			},
+			remediationType: ReplacementType,
+			wantHandled:     false,
+		},
+		{
+			name:     "already_remediated",
+			filePath: "main.tf",
+			content:  "resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n}\n",
+			remediation: Remediation{
+				SearchKey:   "aws_s3_bucket[b]",
+				Remediation: `acl = "private"`,
+			},
+			remediationType: AdditionType,
+			want:            "",
+			wantHandled:     true,
+		},
+		{
+			name:     "hcl_addition",
+			filePath: "main.tf",
+			content:  "resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n}\n",
+			remediation: Remediation{
+				SearchKey:   "aws_s3_bucket[b]",
+				Remediation: "versioning {\n\t\tenabled = true\n\t}",
+			},
+			remediationType: AdditionType,
+			want:            "resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n  versioning {\n    enabled = true\n  }\n}\n",
+			wantHandled:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, handled := remediateWithEditor(tt.filePath, strings.Split(tt.content, "\n"), &tt.remediation, tt.remediationType)
+			require.Equal(t, tt.wantHandled, handled)
+			if tt.wantHandled {
+				require.Equal(t, tt.want, strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+func Test_Removal(t *testing.T) {
+	r := &Remediation{Line: 2, Remediation: "privileged", SimilarityID: "a1"}
+	require.Equal(t, []string{"securityContext:", "  runAsNonRoot: true"},
+		removal(r, []string{"securityContext:", "  privileged: true", "  runAsNonRoot: true"}))
+	require.Empty(t, removal(r, []string{"securityContext:", "  runAsNonRoot: true"}))
+}
+
+func Test_LineChanges(t *testing.T) {
+	set := Set{
+		Addition: []Remediation{{Line: 2, SimilarityID: "a"}},
+		Removal:  []Remediation{{Line: 7, SimilarityID: "r"}},
+		Block:    []Remediation{{Line: 4, SimilarityID: "b"}},
+	}
+	changes := set.lineChanges()
+	require.Len(t, changes, 3)
+	require.Equal(t, RemovalType, changes[0].remediationType)
+	require.Equal(t, BlockType, changes[1].remediationType)
+	require.Equal(t, AdditionType, changes[2].remediationType)
+}
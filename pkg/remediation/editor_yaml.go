@@ -0,0 +1,376 @@
+package remediation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlEditor edits YAML files addressing the yaml.v3 nodes positions, the remaining content, including comments,
+// is kept untouched
+type yamlEditor struct{}
+
+// yamlTarget is a node addressed by a search key and the key node that holds it, if any
+type yamlTarget struct {
+	node *yaml.Node
+	key  *yaml.Node
+}
+
+func (e yamlEditor) replace(content []byte, segments []searchKeySegment, info ReplacementInfo) ([]byte, error) {
+	target, err := resolveYAMLDocuments(content, segments)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(content), "\n")
+	remediated, err := replaceInRegion(lines, target.node.Line-1, target.node.Column-1, yamlNodeEnd(lines, target), info)
+	if err != nil {
+		return nil, err
+	}
+	return validateYAML(remediated)
+}
+
+func (e yamlEditor) add(content []byte, segments []searchKeySegment, snippet string) ([]byte, error) {
+	target, err := resolveYAMLDocuments(content, segments)
+	if err != nil {
+		return nil, err
+	}
+	if target.node.Kind != yaml.MappingNode {
+		return nil, errKeyNotFound
+	}
+
+	snippetLines := dedent(snippet)
+	var addition yaml.Node
+	if err = yaml.Unmarshal([]byte(strings.Join(snippetLines, "\n")), &addition); err != nil ||
+		len(addition.Content) == 0 || addition.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("addition is not a YAML mapping: %s", snippet)
+	}
+	if yamlContains(target.node, addition.Content[0]) {
+		return nil, errAlreadyRemediated
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if target.node.Style&yaml.FlowStyle != 0 {
+		return e.addToFlowMapping(lines, target.node, addition.Content[0])
+	}
+
+	end := yamlNodeEnd(lines, target)
+	remediated := make([]string, 0, len(lines)+len(snippetLines))
+	remediated = append(remediated, lines[:end+1]...)
+	remediated = append(remediated, indent(snippetLines, strings.Repeat(" ", target.node.Column-1))...)
+	remediated = append(remediated, lines[end+1:]...)
+	return validateYAML([]byte(strings.Join(remediated, "\n")))
+}
+
+func (e yamlEditor) remove(content []byte, segments []searchKeySegment) ([]byte, error) {
+	target, err := resolveYAMLDocuments(content, segments)
+	if errors.Is(err, errKeyNotFound) && len(segments) > 1 {
+		if _, parentErr := resolveYAMLDocuments(content, segments[:len(segments)-1]); parentErr == nil {
+			return nil, errAlreadyRemediated
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target.key == nil {
+		return nil, errKeyNotFound
+	}
+
+	lines := strings.Split(string(content), "\n")
+	start := target.key.Line - 1
+	end := yamlNodeEnd(lines, target)
+	prefix := string([]rune(lines[start])[:target.key.Column-1])
+	switch strings.TrimSpace(prefix) {
+	case "":
+	case "-":
+		// the key starts a sequence item, so the next key of the item takes its place
+		next := end + 1
+		if next >= len(lines) || len(lines[next])-len(strings.TrimLeft(lines[next], " ")) != target.key.Column-1 {
+			return nil, errKeyNotFound
+		}
+		lines[next] = prefix + strings.TrimLeft(lines[next], " ")
+	default:
+		// keys of flow mappings are not removed
+		return nil, errKeyNotFound
+	}
+
+	remediated := make([]string, 0, len(lines))
+	remediated = append(remediated, lines[:start]...)
+	remediated = append(remediated, lines[end+1:]...)
+	return validateYAML([]byte(strings.Join(remediated, "\n")))
+}
+
+func (e yamlEditor) render(block map[string]interface{}) (string, error) {
+	var rendered bytes.Buffer
+	encoder := yaml.NewEncoder(&rendered)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(block); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// addToFlowMapping appends the addition pairs to a flow style mapping, e.g. '{app: web}', rendering it again
+func (e yamlEditor) addToFlowMapping(lines []string, mapping, addition *yaml.Node) ([]byte, error) {
+	content := strings.Join(lines, "\n")
+	start := lineColumnOffset(lines, mapping.Line-1, mapping.Column-1)
+	end := matchingBracket(content, start)
+	if end < 0 {
+		return nil, errKeyNotFound
+	}
+	for idx := 0; idx+1 < len(addition.Content); idx += 2 {
+		mapping.Content = append(mapping.Content, addition.Content[idx], addition.Content[idx+1])
+	}
+	rendered, err := yaml.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+	return validateYAML([]byte(content[:start] + strings.TrimRight(string(rendered), "\n") + content[end+1:]))
+}
+
+// resolveYAMLDocuments finds the node addressed by the search key in the first document where it is defined
+func resolveYAMLDocuments(content []byte, segments []searchKeySegment) (*yamlTarget, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return nil, errKeyNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errNotParsed, err)
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		if target, ok := resolveYAML(document.Content[0], segments); ok {
+			return target, nil
+		}
+	}
+}
+
+// resolveYAML walks the search key segments, keys that are not found in the current node are searched in its
+// ancestors, since search keys such as 'metadata.name={{pod}}.spec' go back to the parent after selecting the name
+func resolveYAML(root *yaml.Node, segments []searchKeySegment) (*yamlTarget, bool) {
+	path := []*yamlTarget{{node: root}}
+	for _, segment := range segments {
+		current := path[len(path)-1].node
+		switch segment.kind {
+		case valueSegment:
+			if current.Kind == yaml.SequenceNode {
+				item := yamlItemWithValue(current, segment.key, segment.value)
+				if item == nil {
+					return nil, false
+				}
+				path = append(path, &yamlTarget{node: item})
+				continue
+			}
+			if value := yamlMappingValue(current, segment.key); value == nil || value.Value != segment.value {
+				return nil, false
+			}
+		case labelSegment:
+			_, value := yamlMappingEntry(current, segment.key)
+			if value == nil {
+				return nil, false
+			}
+			item := yamlLabeledItem(value, segment.value)
+			if item == nil {
+				return nil, false
+			}
+			path = append(path, item)
+		default:
+			found := false
+			for idx := len(path) - 1; idx >= 0 && !found; idx-- {
+				// the items of an ancestor sequence are siblings of the selected item, so they are not searched
+				if idx < len(path)-1 && path[idx].node.Kind == yaml.SequenceNode {
+					continue
+				}
+				if key, value := yamlMappingEntry(path[idx].node, segment.key); value != nil {
+					path = append(path[:idx+1], &yamlTarget{node: value, key: key})
+					found = true
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		}
+	}
+	return path[len(path)-1], true
+}
+
+// yamlMappingEntry returns the key and value nodes of a mapping key, sequences are searched for the first item
+// holding the key
+func yamlMappingEntry(node *yaml.Node, key string) (keyNode, valueNode *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			if node.Content[idx].Value == key {
+				return node.Content[idx], node.Content[idx+1]
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if keyNode, valueNode = yamlMappingEntry(item, key); valueNode != nil {
+				return keyNode, valueNode
+			}
+		}
+	}
+	return nil, nil
+}
+
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	_, value := yamlMappingEntry(node, key)
+	return value
+}
+
+// yamlItemWithValue returns the sequence item where the key has the given value
+func yamlItemWithValue(sequence *yaml.Node, key, value string) *yaml.Node {
+	for _, item := range sequence.Content {
+		if itemValue := yamlMappingValue(item, key); itemValue != nil && itemValue.Value == value {
+			return item
+		}
+	}
+	return nil
+}
+
+// yamlLabeledItem returns the item of a sequence by index or the value of a mapping by key
+func yamlLabeledItem(node *yaml.Node, label string) *yamlTarget {
+	if node.Kind == yaml.SequenceNode {
+		if idx, err := strconv.Atoi(label); err == nil && idx >= 0 && idx < len(node.Content) {
+			return &yamlTarget{node: node.Content[idx]}
+		}
+		return nil
+	}
+	if key, value := yamlMappingEntry(node, label); value != nil {
+		return &yamlTarget{node: value, key: key}
+	}
+	return nil
+}
+
+// yamlContains returns true when all the addition keys are already defined in the mapping
+func yamlContains(mapping, addition *yaml.Node) bool {
+	for idx := 0; idx+1 < len(addition.Content); idx += 2 {
+		if yamlMappingValue(mapping, addition.Content[idx].Value) == nil {
+			return false
+		}
+	}
+	return len(addition.Content) > 0
+}
+
+// yamlNodeEnd returns the index of the last line of the node, the lines indented deeper than its key
+// or, for sequences that are not indented, the following items
+func yamlNodeEnd(lines []string, target *yamlTarget) int {
+	start := target.node.Line - 1
+	if target.node.Kind == yaml.ScalarNode && target.node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 &&
+		!strings.Contains(target.node.Value, "\n") {
+		return start
+	}
+
+	keyIndentation := -1
+	if target.key != nil {
+		keyIndentation = target.key.Column - 1
+	} else if target.node.Kind != yaml.ScalarNode {
+		keyIndentation = target.node.Column - 2
+	}
+	end := start
+	for idx := start + 1; idx < len(lines); idx++ {
+		trimmed := strings.TrimSpace(lines[idx])
+		if trimmed == "---" || trimmed == "..." {
+			break
+		}
+		if trimmed == "" {
+			continue
+		}
+		lineIndentation := len(lines[idx]) - len(strings.TrimLeft(lines[idx], " "))
+		notIndentedItem := target.node.Kind == yaml.SequenceNode && lineIndentation == keyIndentation &&
+			strings.HasPrefix(trimmed, "-")
+		if lineIndentation <= keyIndentation && !notIndentedItem {
+			break
+		}
+		end = idx
+	}
+	return end
+}
+
+// replaceInRegion replaces the first occurrence of the replacement 'before' in the region starting at the given line
+// and column and ending at the end line
+func replaceInRegion(lines []string, startLine, startColumn, endLine int, info ReplacementInfo) ([]byte, error) {
+	content := strings.Join(lines, "\n")
+	start := lineColumnOffset(lines, startLine, startColumn)
+	end := lineColumnOffset(lines, endLine, len([]rune(lines[endLine])))
+	region := content[start:end]
+	if !strings.Contains(region, info.Before) {
+		if strings.Contains(region, info.After) {
+			return nil, errAlreadyRemediated
+		}
+		return nil, errKeyNotFound
+	}
+	return []byte(content[:start] + strings.Replace(region, info.Before, info.After, 1) + content[end:]), nil
+}
+
+// lineColumnOffset returns the byte offset of a line and column, the column counts characters
+func lineColumnOffset(lines []string, line, column int) int {
+	offset := 0
+	for idx := 0; idx < line && idx < len(lines); idx++ {
+		offset += len(lines[idx]) + 1
+	}
+	if line < len(lines) {
+		runes := []rune(lines[line])
+		if column > len(runes) {
+			column = len(runes)
+		}
+		offset += len(string(runes[:column]))
+	}
+	return offset
+}
+
+// matchingBracket returns the offset of the bracket that closes the one at the start offset, ignoring quoted text
+func matchingBracket(content string, start int) int {
+	depth := 0
+	var quote byte
+	for idx := start; idx < len(content); idx++ {
+		char := content[idx]
+		switch {
+		case quote != 0:
+			if char == '\\' && quote == '"' {
+				idx++
+			} else if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '{' || char == '[':
+			depth++
+		case char == '}' || char == ']':
+			depth--
+			if depth == 0 {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+func validateYAML(content []byte) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return content, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("remediation produces invalid YAML: %w", err)
+		}
+	}
+}
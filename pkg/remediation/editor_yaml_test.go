@@ -0,0 +1,265 @@
+package remediation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const yamlContent = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # pod
+  labels: {app: web}
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+    securityContext:
+      privileged: true
+  - name: sidecar
+    image: busybox
+`
+
+func Test_YAMLEditorReplace(t *testing.T) {
+	got, err := yamlEditor{}.replace([]byte(yamlContent),
+		parseSearchKey("metadata.name={{web}}.spec.containers.name={{nginx}}.securityContext.privileged"),
+		ReplacementInfo{Before: "true", After: "false"})
+	require.NoError(t, err)
+	require.Contains(t, string(got), "      privileged: false\n")
+	require.Contains(t, string(got), "  name: web # pod\n")
+
+	_, err = yamlEditor{}.replace(got,
+		parseSearchKey("metadata.name={{web}}.spec.containers.name={{nginx}}.securityContext.privileged"),
+		ReplacementInfo{Before: "true", After: "false"})
+	require.ErrorIs(t, err, errAlreadyRemediated)
+}
+
+func Test_YAMLEditorAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		snippet   string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "sequence_item",
+			searchKey: "metadata.name={{web}}.spec.containers.name={{sidecar}}",
+			snippet:   "securityContext:\n\treadOnlyRootFilesystem: true",
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # pod
+  labels: {app: web}
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+    securityContext:
+      privileged: true
+  - name: sidecar
+    image: busybox
+    securityContext:
+      readOnlyRootFilesystem: true
+`,
+		},
+		{
+			name:      "flow_mapping",
+			searchKey: "metadata.labels",
+			snippet:   "tier: frontend",
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # pod
+  labels: {app: web, tier: frontend}
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+    securityContext:
+      privileged: true
+  - name: sidecar
+    image: busybox
+`,
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "metadata.name={{web}}.spec.containers.name={{nginx}}",
+			snippet:   "securityContext:\n  privileged: false",
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "missing_container",
+			searchKey: "metadata.name={{web}}.spec.containers.name={{redis}}",
+			snippet:   "image: redis",
+			wantErr:   errKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlEditor{}.add([]byte(yamlContent), parseSearchKey(tt.searchKey), tt.snippet)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_JSONEditor(t *testing.T) {
+	content := `{
+  "Resources": {
+    "Bucket": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {}
+    },
+    "Queue": {
+      "Type": "AWS::SQS::Queue",
+      "Properties": {
+        "KmsMasterKeyId": ""
+      }
+    }
+  }
+}
+`
+	got, err := jsonEditor{}.add([]byte(content), parseSearchKey("Resources.Bucket.Properties"),
+		`"AccessControl": "Private"`)
+	require.NoError(t, err)
+	require.Contains(t, string(got), "      \"Properties\": {\n        \"AccessControl\": \"Private\"\n      }\n")
+
+	got, err = jsonEditor{}.add(got, parseSearchKey("Resources.Queue.Properties"), `"KmsDataKeyReusePeriodSeconds": 300`)
+	require.NoError(t, err)
+	require.Contains(t, string(got), "\"KmsMasterKeyId\": \"\",\n        \"KmsDataKeyReusePeriodSeconds\": 300\n")
+
+	_, err = jsonEditor{}.replace(got, parseSearchKey("Resources.Bucket.Properties.AccessControl"),
+		ReplacementInfo{Before: "Public", After: "Private"})
+	require.ErrorIs(t, err, errAlreadyRemediated)
+}
+
+func Test_YAMLEditorRemove(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchKey string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "mapping",
+			searchKey: "metadata.name={{web}}.spec.containers.name={{nginx}}.securityContext",
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # pod
+  labels: {app: web}
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+  - name: sidecar
+    image: busybox
+`,
+		},
+		{
+			name:      "first_key_of_item",
+			searchKey: "spec.containers[1].name",
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # pod
+  labels: {app: web}
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+    securityContext:
+      privileged: true
+  - image: busybox
+`,
+		},
+		{
+			name:      "already_remediated",
+			searchKey: "metadata.name={{web}}.spec.containers.name={{sidecar}}.securityContext",
+			wantErr:   errAlreadyRemediated,
+		},
+		{
+			name:      "flow_mapping",
+			searchKey: "metadata.labels.app",
+			wantErr:   errKeyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlEditor{}.remove([]byte(yamlContent), parseSearchKey(tt.searchKey))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_YAMLEditorBlock(t *testing.T) {
+	snippet, err := renderBlock(yamlEditor{}, `{"securityContext":{"capabilities":{"drop":["ALL"]},"runAsNonRoot":true}}`)
+	require.NoError(t, err)
+
+	got, err := yamlEditor{}.add([]byte(yamlContent),
+		parseSearchKey("metadata.name={{web}}.spec.containers.name={{sidecar}}"), snippet)
+	require.NoError(t, err)
+	require.Contains(t, string(got), `  - name: sidecar
+    image: busybox
+    securityContext:
+      capabilities:
+        drop:
+          - ALL
+      runAsNonRoot: true
+`)
+}
+
+func Test_JSONEditorRemove(t *testing.T) {
+	content := `{
+  "Properties": {
+    "BucketName": "logs",
+    "AccessControl": "PublicRead",
+    "Tags": []
+  }
+}
+`
+	got, err := jsonEditor{}.remove([]byte(content), parseSearchKey("Properties.AccessControl"))
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "Properties": {
+    "BucketName": "logs",
+    "Tags": []
+  }
+}
+`, string(got))
+
+	got, err = jsonEditor{}.remove(got, parseSearchKey("Properties.Tags"))
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "Properties": {
+    "BucketName": "logs"
+  }
+}
+`, string(got))
+
+	_, err = jsonEditor{}.remove(got, parseSearchKey("Properties.Tags"))
+	require.ErrorIs(t, err, errAlreadyRemediated)
+
+	got, err = jsonEditor{}.add(got, parseSearchKey("Properties"), `{"VersioningConfiguration":{"Status":"Enabled"}}`)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "Properties": {
+    "BucketName": "logs",
+    "VersioningConfiguration": {
+      "Status": "Enabled"
+    }
+  }
+}
+`, string(got))
+}
@@ -0,0 +1,279 @@
+package remediation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	patchContextLines = 3
+	noNewlineMarker   = "\\ No newline at end of file"
+)
+
+// patchLine tracks where a line of the remediated file comes from
+// origin is the index of the line in the original file or -1 when it was added by a remediation
+// owners are the remediations that added the line
+type patchLine struct {
+	origin int
+	owners []string
+}
+
+// FilePatch collects the remediations accepted for a file and the changes they make to its content
+type FilePatch struct {
+	FilePath     string
+	Remediations []Remediation
+	name         string
+	original     []string
+	current      []string
+	lines        []patchLine
+	deletedBy    map[int][]string
+}
+
+func newFilePatch(filePath, name string, lines []string) *FilePatch {
+	p := &FilePatch{
+		FilePath:  filePath,
+		name:      name,
+		original:  append([]string{}, lines...),
+		current:   append([]string{}, lines...),
+		lines:     make([]patchLine, len(lines)),
+		deletedBy: make(map[int][]string),
+	}
+	for idx := range p.lines {
+		p.lines[idx] = patchLine{origin: idx}
+	}
+	return p
+}
+
+// record registers the content after the remediation, keeping track of the lines it adds and removes
+func (p *FilePatch) record(remediated []string, r *Remediation) {
+	label := r.annotation()
+	lines := make([]patchLine, 0, len(remediated))
+
+	for _, opCode := range difflib.NewMatcher(p.current, remediated).GetOpCodes() {
+		owners := []string{label}
+		if opCode.Tag == 'r' || opCode.Tag == 'd' {
+			for _, removed := range p.lines[opCode.I1:opCode.I2] {
+				if removed.origin >= 0 {
+					p.deletedBy[removed.origin] = appendOwner(p.deletedBy[removed.origin], label)
+					continue
+				}
+				for _, owner := range removed.owners {
+					owners = appendOwner(owners, owner)
+				}
+			}
+		}
+		switch opCode.Tag {
+		case 'e':
+			lines = append(lines, p.lines[opCode.I1:opCode.I2]...)
+		case 'r', 'i':
+			for idx := opCode.J1; idx < opCode.J2; idx++ {
+				lines = append(lines, patchLine{origin: -1, owners: owners})
+			}
+		}
+	}
+
+	p.current = append([]string{}, remediated...)
+	p.lines = lines
+	p.Remediations = append(p.Remediations, *r)
+}
+
+// Diff returns the git compatible unified diff of the file, each hunk is annotated with the
+// similarity ids and query names of the remediations that changed it
+func (p *FilePatch) Diff(colored bool) string {
+	before := patchContent(p.original)
+	after := patchContent(p.current)
+	groups := difflib.NewMatcher(before, after).GetGroupedOpCodes(patchContextLines)
+	if len(groups) == 0 {
+		return ""
+	}
+
+	paint := func(style color.Color, text string) string {
+		if colored {
+			return style.Sprint(text)
+		}
+		return text
+	}
+
+	name := p.name
+	var diff strings.Builder
+	diff.WriteString(paint(color.Bold, fmt.Sprintf("diff --git a/%s b/%s\n--- a/%s\n+++ b/%s", name, name, name, name)) + "\n")
+
+	for _, group := range groups {
+		first, last := group[0], group[len(group)-1]
+		hunk := make([]string, 0)
+		owners := make([]string, 0)
+
+		for _, opCode := range group {
+			if opCode.Tag == 'e' {
+				for _, line := range before[opCode.I1:opCode.I2] {
+					hunk = append(hunk, patchLines(" ", line)...)
+				}
+				continue
+			}
+			if opCode.Tag == 'r' || opCode.Tag == 'd' {
+				for idx, line := range before[opCode.I1:opCode.I2] {
+					for _, owner := range p.deletedBy[opCode.I1+idx] {
+						owners = appendOwner(owners, owner)
+					}
+					hunk = append(hunk, paint(color.Red, strings.Join(patchLines("-", line), "\n")))
+				}
+			}
+			if opCode.Tag == 'r' || opCode.Tag == 'i' {
+				for idx, line := range after[opCode.J1:opCode.J2] {
+					if opCode.J1+idx < len(p.lines) {
+						for _, owner := range p.lines[opCode.J1+idx].owners {
+							owners = appendOwner(owners, owner)
+						}
+					}
+					hunk = append(hunk, paint(color.Green, strings.Join(patchLines("+", line), "\n")))
+				}
+			}
+		}
+
+		header := fmt.Sprintf("@@ -%s +%s @@", formatRange(first.I1, last.I2), formatRange(first.J1, last.J2))
+		diff.WriteString(paint(color.Cyan, header))
+		if len(owners) > 0 {
+			diff.WriteString(" " + strings.Join(owners, ", "))
+		}
+		diff.WriteString("\n" + strings.Join(hunk, "\n") + "\n")
+	}
+
+	return diff.String()
+}
+
+// Patch returns the unified diff of all the files remediated, sorted by file path
+func (s *Summary) Patch(colored bool) string {
+	var patch strings.Builder
+	for _, p := range s.FilePatches() {
+		patch.WriteString(p.Diff(colored))
+	}
+	return patch.String()
+}
+
+// FilePatches returns the patches of the files with accepted remediations, sorted by file path
+func (s *Summary) FilePatches() []*FilePatch {
+	patches := make([]*FilePatch, 0, len(s.patches))
+	for _, p := range s.patches {
+		if len(p.Remediations) > 0 {
+			patches = append(patches, p)
+		}
+	}
+	sort.Slice(patches, func(i, j int) bool {
+		return patches[i].FilePath < patches[j].FilePath
+	})
+	return patches
+}
+
+func (s *Summary) filePatch(filePath string, lines []string) *FilePatch {
+	if s.patches == nil {
+		s.patches = make(map[string]*FilePatch)
+	}
+	if _, ok := s.patches[filePath]; !ok {
+		s.patches[filePath] = newFilePatch(filePath, s.patchPath(filePath), lines)
+	}
+	return s.patches[filePath]
+}
+
+// patchPath returns the path of the file in the patch headers, relative to the root of its git repository,
+// or else to the scanned path including it or to the working directory, so the patch applies from that root
+func (s *Summary) patchPath(filePath string) string {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	roots := make([]string, 0, len(s.ScannedPaths)+2)
+	if root, ok := repositoryRoot(filepath.Dir(absPath)); ok {
+		roots = append(roots, root)
+	}
+	for _, scanned := range s.ScannedPaths {
+		if info, err := os.Stat(scanned); err == nil && !info.IsDir() {
+			scanned = filepath.Dir(scanned)
+		}
+		roots = append(roots, scanned)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		roots = append(roots, wd)
+	}
+	for _, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(absRoot, absPath); err == nil && rel != ".." &&
+			!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(absPath)
+}
+
+// repositoryRoot returns the closest directory with a .git entry, the directory itself included
+func repositoryRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func (r *Remediation) annotation() string {
+	if r.QueryName == "" {
+		return r.SimilarityID
+	}
+	return fmt.Sprintf("%s (%s)", r.SimilarityID, r.QueryName)
+}
+
+// patchContent splits the lines keeping the line breaks, so a missing line break at the end of the file is a change
+func patchContent(lines []string) []string {
+	content := make([]string, 0, len(lines))
+	for idx, line := range lines {
+		switch {
+		case idx < len(lines)-1:
+			content = append(content, line+"\n")
+		case line != "":
+			content = append(content, line)
+		}
+	}
+	return content
+}
+
+// patchLines returns the diff lines of a line, followed by the marker when it has no line break
+func patchLines(prefix, line string) []string {
+	if strings.HasSuffix(line, "\n") {
+		return []string{prefix + strings.TrimSuffix(line, "\n")}
+	}
+	return []string{prefix + line, noNewlineMarker}
+}
+
+// formatRange formats a hunk range as in the unified diff format
+func formatRange(start, stop int) string {
+	beginning := start + 1
+	length := stop - start
+	if length == 1 {
+		return fmt.Sprintf("%d", beginning)
+	}
+	if length == 0 {
+		beginning--
+	}
+	return fmt.Sprintf("%d,%d", beginning, length)
+}
+
+func appendOwner(owners []string, owner string) []string {
+	for _, o := range owners {
+		if o == owner {
+			return owners
+		}
+	}
+	return append(owners, owner)
+}
@@ -0,0 +1,80 @@
+package remediation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FilePatchDiff(t *testing.T) {
+	original := strings.Split("resource \"aws_s3_bucket\" \"b\" {\n  acl = \"public-read\"\n}\n", "\n")
+	replaced := strings.Split("resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n}\n", "\n")
+	added := strings.Split("resource \"aws_s3_bucket\" \"b\" {\n  acl = \"private\"\n  versioning {\n    enabled = true\n  }\n}\n", "\n")
+
+	s := &Summary{}
+	patch := s.filePatch(filepath.Join(newRepository(t), "main.tf"), original)
+	patch.record(replaced, &Remediation{SimilarityID: "a1", QueryName: "S3 Bucket ACL Allows Read Or Write to All Users"})
+	patch.record(added, &Remediation{SimilarityID: "b2"})
+
+	want := `diff --git a/main.tf b/main.tf
+--- a/main.tf
++++ b/main.tf
+@@ -1,3 +1,6 @@ a1 (S3 Bucket ACL Allows Read Or Write to All Users), b2
+ resource "aws_s3_bucket" "b" {
+-  acl = "public-read"
++  acl = "private"
++  versioning {
++    enabled = true
++  }
+ }
+`
+	require.Equal(t, want, patch.Diff(false))
+	require.Equal(t, want, s.Patch(false))
+	require.Len(t, s.FilePatches(), 1)
+	require.Len(t, s.FilePatches()[0].Remediations, 2)
+}
+
+func Test_FilePatchDiffNoNewline(t *testing.T) {
+	s := &Summary{}
+	patch := s.filePatch(filepath.Join(newRepository(t), "Dockerfile.yaml"), []string{"a: 1", "b: 2"})
+	patch.record([]string{"a: 1", "b: 3"}, &Remediation{SimilarityID: "c3"})
+
+	want := `diff --git a/Dockerfile.yaml b/Dockerfile.yaml
+--- a/Dockerfile.yaml
++++ b/Dockerfile.yaml
+@@ -1,2 +1,2 @@ c3
+ a: 1
+-b: 2
+\ No newline at end of file
++b: 3
+\ No newline at end of file
+`
+	require.Equal(t, want, patch.Diff(false))
+}
+
+func Test_PatchPath(t *testing.T) {
+	repository := newRepository(t)
+	s := &Summary{}
+	require.Equal(t, "modules/s3/main.tf", s.patchPath(filepath.Join(repository, "modules", "s3", "main.tf")))
+	require.Equal(t, "main.tf", s.patchPath(filepath.Join(repository, "modules", "..", "main.tf")))
+
+	scanned := t.TempDir()
+	s = &Summary{ScannedPaths: []string{filepath.Join(t.TempDir(), "other"), scanned}}
+	require.Equal(t, "k8s/pod.yaml", s.patchPath(filepath.Join(scanned, "k8s", "pod.yaml")))
+}
+
+// newRepository returns a temporary directory with a .git directory
+func newRepository(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), os.ModePerm))
+	return dir
+}
+
+func Test_FormatRange(t *testing.T) {
+	require.Equal(t, "3", formatRange(2, 3))
+	require.Equal(t, "2,0", formatRange(2, 2))
+	require.Equal(t, "1,4", formatRange(0, 4))
+}
@@ -12,13 +12,16 @@ import (
 
 // Report includes all query results
 type Report struct {
-	Queries []Query `json:"queries"`
+	ScannedPaths []string `json:"paths"`
+	Queries      []Query  `json:"queries"`
 }
 
 // Query includes all the files that presents a result related to the queryID
 type Query struct {
-	Files   []File `json:"files"`
-	QueryID string `json:"query_id"`
+	Files     []File `json:"files"`
+	QueryID   string `json:"query_id"`
+	QueryName string `json:"query_name"`
+	Severity  string `json:"severity"`
 }
 
 // File presents the result information related to the file
@@ -39,18 +42,50 @@ type Remediation struct {
 	Remediation   string
 	SimilarityID  string
 	QueryID       string
+	QueryName     string
 	SearchKey     string
 	ExpectedValue string
 	ActualValue   string
+	ResultFile    string
+	ValueKey      string
 }
 
-// Set includes all the replacements and additions related to a file
+// Set includes all the replacements, additions, removals and blocks related to a file
 type Set struct {
 	Replacement []Remediation
 	Addition    []Remediation
+	Removal     []Remediation
+	Block       []Remediation
 }
 
-// RemediateFile remediationSets the replacements first and secondly, the additions sorted down
+// typedRemediation is a remediation of the set along with its type
+type typedRemediation struct {
+	remediation     Remediation
+	remediationType string
+}
+
+// lineChanges returns the additions, removals and blocks sorted down, so each one keeps the lines of the next ones
+func (set *Set) lineChanges() []typedRemediation {
+	changes := make([]typedRemediation, 0, len(set.Addition)+len(set.Removal)+len(set.Block))
+	for i := range set.Addition {
+		changes = append(changes, typedRemediation{remediation: set.Addition[i], remediationType: AdditionType})
+	}
+	for i := range set.Removal {
+		changes = append(changes, typedRemediation{remediation: set.Removal[i], remediationType: RemovalType})
+	}
+	for i := range set.Block {
+		changes = append(changes, typedRemediation{remediation: set.Block[i], remediationType: BlockType})
+	}
+
+	// descending order
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].remediation.Line > changes[j].remediation.Line
+	})
+	return changes
+}
+
+// RemediateFile remediationSets the replacements first and secondly, the additions, removals and blocks sorted down
+// the remediations are applied through the file syntax when the search key can be addressed, otherwise by line
 func (s *Summary) RemediateFile(filePath string, remediationSet Set) error {
 	filepath.Clean(filePath)
 	content, err := os.ReadFile(filePath)
@@ -61,37 +96,61 @@ func (s *Summary) RemediateFile(filePath string, remediationSet Set) error {
 	}
 
 	lines := strings.Split(string(content), "\n")
+	patch := s.filePatch(filePath, lines)
 
 	// do replacements first
-	if len(remediationSet.Replacement) > 0 {
-		for i := range remediationSet.Replacement {
-			r := remediationSet.Replacement[i]
-			remediatedLines := replacement(&r, lines)
-			if len(remediatedLines) > 0 && willRemediate(remediatedLines, filePath, &r) {
-				lines = s.writeRemediation(remediatedLines, lines, filePath, r.SimilarityID)
-			}
-		}
+	for i := range remediationSet.Replacement {
+		lines = s.remediate(patch, lines, remediationSet.Replacement[i], ReplacementType)
 	}
 
-	// do additions after
-	if len(remediationSet.Addition) > 0 {
-		// descending order
-		sort.Slice(remediationSet.Addition, func(i, j int) bool {
-			return remediationSet.Addition[i].Line > remediationSet.Addition[j].Line
-		})
-
-		for i := range remediationSet.Addition {
-			a := remediationSet.Addition[i]
-			remediatedLines := addition(&a, &lines)
-			if len(remediatedLines) > 0 && willRemediate(remediatedLines, filePath, &a) {
-				lines = s.writeRemediation(remediatedLines, lines, filePath, a.SimilarityID)
-			}
-		}
+	// do additions, removals and blocks after
+	changes := remediationSet.lineChanges()
+	for i := range changes {
+		lines = s.remediate(patch, lines, changes[i].remediation, changes[i].remediationType)
 	}
 
 	return nil
 }
 
+// remediate applies the remediation to the lines, it is written when the rescan confirms that it removes the result
+func (s *Summary) remediate(patch *FilePatch, lines []string, r Remediation, remediationType string) []string {
+	remediatedLines, ok := remediateWithEditor(patch.FilePath, lines, &r, remediationType)
+	if !ok {
+		remediatedLines = remediateLines(patch.FilePath, append([]string{}, lines...), &r, remediationType)
+	}
+	if len(remediatedLines) > 0 && willRemediate(s.getParsers(), remediatedLines, patch.FilePath, &r) {
+		return s.writeRemediation(remediatedLines, lines, patch, &r)
+	}
+	return lines
+}
+
+// remediateLines applies the remediation to the line of the result
+func remediateLines(filePath string, lines []string, r *Remediation, remediationType string) []string {
+	switch remediationType {
+	case ReplacementType:
+		return replacement(r, lines)
+	case AdditionType:
+		return addition(r, &lines)
+	case RemovalType:
+		return removal(r, lines)
+	case BlockType:
+		e := getEditor(filePath)
+		if e == nil {
+			return []string{}
+		}
+		snippet, err := renderBlock(e, r.Remediation)
+		if err != nil {
+			log.Error().Msgf("failed to render remediation '%s': %s", r.SimilarityID, err)
+			return []string{}
+		}
+		block := *r
+		block.Remediation = strings.TrimRight(snippet, "\n")
+		return addition(&block, &lines)
+	default:
+		return []string{}
+	}
+}
+
 // ReplacementInfo presents the relevant information to do the replacement
 type ReplacementInfo struct {
 	Before string `json:"before"`
@@ -153,15 +212,33 @@ func addition(r *Remediation, lines *[]string) []string {
 	return remediation
 }
 
-func (s *Summary) writeRemediation(remediatedLines, lines []string, filePath, similarityID string) []string {
-	remediated := []byte(strings.Join(remediatedLines, "\n"))
+// removal removes the line of the result when it holds the attribute to remove
+func removal(r *Remediation, lines []string) []string {
+	if r.Line < 1 || r.Line > len(lines) {
+		return []string{}
+	}
+
+	if !strings.Contains(lines[r.Line-1], r.Remediation) {
+		log.Info().Msgf("remediation '%s' is already done", r.SimilarityID)
+		return []string{}
+	}
+
+	return append(lines[:r.Line-1], lines[r.Line:]...)
+}
+
+func (s *Summary) writeRemediation(remediatedLines, lines []string, patch *FilePatch, r *Remediation) []string {
+	if !s.DryRun {
+		remediated := []byte(strings.Join(remediatedLines, "\n"))
+
+		if err := os.WriteFile(patch.FilePath, remediated, os.ModePerm); err != nil {
+			log.Error().Msgf("failed to write file: %s", err)
+			return lines
+		}
 
-	if err := os.WriteFile(filePath, remediated, os.ModePerm); err != nil {
-		log.Error().Msgf("failed to write file: %s", err)
-		return lines
+		log.Info().Msgf("file '%s' was remediated with '%s'", patch.FilePath, r.SimilarityID)
 	}
 
-	log.Info().Msgf("file '%s' was remediated with '%s'", filePath, similarityID)
+	patch.record(remediatedLines, r)
 	s.ActualRemediationDoneNumber++
 
 	return remediatedLines
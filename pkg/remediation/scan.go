@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -18,13 +19,8 @@ import (
 	"github.com/Checkmarx/kics/internal/tracker"
 	"github.com/Checkmarx/kics/pkg/engine/source"
 	"github.com/Checkmarx/kics/pkg/parser"
-	buildahParser "github.com/Checkmarx/kics/pkg/parser/buildah"
-	dockerParser "github.com/Checkmarx/kics/pkg/parser/docker"
-	protoParser "github.com/Checkmarx/kics/pkg/parser/grpc"
-	jsonParser "github.com/Checkmarx/kics/pkg/parser/json"
-	terraformParser "github.com/Checkmarx/kics/pkg/parser/terraform"
-	yamlParser "github.com/Checkmarx/kics/pkg/parser/yaml"
 	"github.com/Checkmarx/kics/pkg/utils"
+	"github.com/google/uuid"
 	"github.com/open-policy-agent/opa/rego"
 	"github.com/rs/zerolog/log"
 )
@@ -37,10 +33,10 @@ type runQueryInfo struct {
 	files     model.FileMetadatas
 }
 
-// scanTmpFile scans a temporary file against a specific query
-func scanTmpFile(tmpFile, queryID string, remediated []byte) ([]model.Vulnerability, error) {
+// scanTmpFile scans the remediated file of the temporary workspace against a specific query
+func scanTmpFile(parsers []*parser.Parser, w *workspace, queryID string) ([]model.Vulnerability, error) {
 	// get payload
-	files, err := getPayload(tmpFile, remediated)
+	files, err := getPayload(parsers, w)
 
 	if err != nil {
 		log.Err(err)
@@ -75,53 +71,46 @@ func scanTmpFile(tmpFile, queryID string, remediated []byte) ([]model.Vulnerabil
 		payload:   payload,
 		query:     query,
 		inspector: inspector,
-		tmpFile:   tmpFile,
+		tmpFile:   w.file,
 		files:     files,
 	}
 
-	return runQuery(info), nil
-}
-
-// getPayload gets the payload of a file
-func getPayload(filePath string, content []byte) (model.FileMetadatas, error) {
-	ext := utils.GetExtension(filePath)
-	var p []*parser.Parser
-	var err error
-
-	switch ext {
-	case ".dockerfile", "Dockerfile", "possibleDockerfile", ".ubi8", ".debian":
-		p, err = parser.NewBuilder().Add(&dockerParser.Parser{}).Build([]string{""}, []string{""})
-
-	case ".tf":
-		p, err = parser.NewBuilder().Add(terraformParser.NewDefault()).Build([]string{""}, []string{""})
+	results := runQuery(info)
 
-	case ".proto":
-		p, err = parser.NewBuilder().Add(&protoParser.Parser{}).Build([]string{""}, []string{""})
-
-	case ".yaml", ".yml":
-		p, err = parser.NewBuilder().Add(&yamlParser.Parser{}).Build([]string{""}, []string{""})
-
-	case ".json":
-		p, err = parser.NewBuilder().Add(&jsonParser.Parser{}).Build([]string{""}, []string{""})
+	// the files the remediated file depends on are scanned together, only the results of the remediated file
+	// (or of the template, for chart values) are kept
+	fileResults := make([]model.Vulnerability, 0, len(results))
+	for i := range results {
+		if filepath.Clean(results[i].FileName) == filepath.Clean(w.resultFile) {
+			fileResults = append(fileResults, results[i])
+		}
+	}
+	return fileResults, nil
+}
 
-	case ".sh":
-		p, err = parser.NewBuilder().Add(&buildahParser.Parser{}).Build([]string{""}, []string{""})
+// getPayload gets the payload of the result file with the parsers of the scan, the remediated file itself or the
+// Terraform file taking its variables from the remediated tfvars, the Helm charts are rendered again with the scan
+// resolver
+func getPayload(parsers []*parser.Parser, w *workspace) (model.FileMetadatas, error) {
+	if w.chart != "" {
+		return getHelmPayload(w.chart, parsers)
 	}
 
+	content, err := os.ReadFile(w.resultFile)
 	if err != nil {
-		log.Error().Msgf("failed to get parser: %s", err)
 		return model.FileMetadatas{}, err
 	}
 
-	if len(p) == 0 {
+	p := getParser(parsers, w.resultFile)
+	if p == nil {
 		log.Info().Msg("failed to get parser")
 		return model.FileMetadatas{}, errors.New("failed to get parser")
 	}
 
-	documents, er := p[0].Parse(filePath, content)
+	documents, er := p.Parse(w.resultFile, content)
 
 	if er != nil {
-		log.Error().Msgf("failed to parse file '%s': %s", filePath, er)
+		log.Error().Msgf("failed to parse file '%s': %s", w.resultFile, er)
 		return model.FileMetadatas{}, er
 	}
 
@@ -134,12 +123,15 @@ func getPayload(filePath string, content []byte) (model.FileMetadatas, error) {
 		}
 
 		file := model.FileMetadata{
-			FilePath:          filePath,
+			ID:                uuid.New().String(),
+			FilePath:          w.resultFile,
 			Document:          kics.PrepareScanDocument(document, documents.Kind),
 			LineInfoDocument:  document,
-			Commands:          p[0].CommentsCommands(filePath, content),
+			Kind:              documents.Kind,
+			Commands:          p.CommentsCommands(w.resultFile, content),
 			OriginalData:      string(content),
 			LinesOriginalData: utils.SplitLines(string(content)),
+			ResolvedFiles:     documents.ResolvedFiles,
 		}
 
 		files = append(files, file)
@@ -148,6 +140,68 @@ func getPayload(filePath string, content []byte) (model.FileMetadatas, error) {
 	return files, nil
 }
 
+// getHelmPayload renders the chart and gets the payload of its templates, keeping the IDInfo of the resolver
+// so the results lines are mapped back to the templates
+func getHelmPayload(chart string, parsers []*parser.Parser) (model.FileMetadatas, error) {
+	combinedResolver, err := scan.NewResolver()
+	if err != nil {
+		return model.FileMetadatas{}, err
+	}
+
+	resolved, err := combinedResolver.Resolve(chart, model.KindHELM)
+	if err != nil {
+		return model.FileMetadatas{}, err
+	}
+
+	var files model.FileMetadatas
+
+	for _, rfile := range resolved.File {
+		p := getParser(parsers, rfile.FileName)
+		if p == nil {
+			continue
+		}
+		documents, err := p.Parse(rfile.FileName, rfile.Content)
+		if err != nil {
+			log.Error().Msgf("failed to parse file '%s': %s", rfile.FileName, err)
+			continue
+		}
+
+		for _, document := range documents.Docs {
+			if _, err = json.Marshal(document); err != nil {
+				continue
+			}
+
+			files = append(files, model.FileMetadata{
+				ID:                uuid.New().String(),
+				FilePath:          rfile.FileName,
+				Document:          kics.PrepareScanDocument(document, model.KindHELM),
+				LineInfoDocument:  document,
+				Kind:              model.KindHELM,
+				Content:           string(rfile.Content),
+				OriginalData:      string(rfile.OriginalData),
+				Commands:          p.CommentsCommands(rfile.FileName, rfile.OriginalData),
+				HelmID:            rfile.SplitID,
+				IDInfo:            rfile.IDInfo,
+				LinesIgnore:       documents.IgnoreLines,
+				LinesOriginalData: utils.SplitLines(string(rfile.OriginalData)),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// getParser returns the parser that supports the file extension
+func getParser(parsers []*parser.Parser, filePath string) *parser.Parser {
+	ext := utils.GetExtension(filePath)
+	for _, p := range parsers {
+		if _, ok := p.SupportedExtensions()[ext]; ok {
+			return p
+		}
+	}
+	return nil
+}
+
 // runQuery runs a query and returns its results
 func runQuery(r *runQueryInfo) []model.Vulnerability {
 	queryExecTimeout := time.Duration(flags.GetIntFlag(flags.QueryExecTimeoutFlag)) * time.Second
@@ -0,0 +1,120 @@
+package remediation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// Selection is the set of remediations chosen in the interactive mode, it can be saved and reused
+// to remediate the same results again without prompts
+type Selection struct {
+	Results      string                `json:"results,omitempty"`
+	Remediations []SelectedRemediation `json:"remediations"`
+}
+
+// SelectedRemediation is a remediation accepted for a result, Remediation is only set when it was edited
+type SelectedRemediation struct {
+	SimilarityID string `json:"similarity_id"`
+	QueryID      string `json:"query_id"`
+	QueryName    string `json:"query_name"`
+	FilePath     string `json:"file_name"`
+	Line         int    `json:"line"`
+	Remediation  string `json:"remediation,omitempty"`
+}
+
+// LoadSelection reads a selection file
+func LoadSelection(path string) (*Selection, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &Selection{}
+	if err := json.Unmarshal(content, selection); err != nil {
+		return nil, err
+	}
+	return selection, nil
+}
+
+// Save writes the selection file
+func (s *Selection) Save(path string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), content, os.ModePerm)
+}
+
+// Add accepts the remediation of a result, edited is the remediation changed by the user or an empty string
+func (s *Selection) Add(query *Query, file *File, edited string) {
+	s.Remediations = append(s.Remediations, SelectedRemediation{
+		SimilarityID: file.SimilarityID,
+		QueryID:      query.QueryID,
+		QueryName:    query.QueryName,
+		FilePath:     file.FilePath,
+		Line:         file.Line,
+		Remediation:  edited,
+	})
+}
+
+// IDs returns the similarity ids of the selected remediations, to be used as the included ids
+func (s *Selection) IDs() []string {
+	ids := make([]string, 0, len(s.Remediations))
+	for i := range s.Remediations {
+		ids = append(ids, s.Remediations[i].SimilarityID)
+	}
+	return ids
+}
+
+// Apply replaces the remediations of the report results with the ones edited in the selection
+func (s *Selection) Apply(report *Report) {
+	edited := make(map[string]string)
+	for i := range s.Remediations {
+		if s.Remediations[i].Remediation != "" {
+			edited[s.Remediations[i].SimilarityID] = s.Remediations[i].Remediation
+		}
+	}
+
+	for i := range report.Queries {
+		for j := range report.Queries[i].Files {
+			file := &report.Queries[i].Files[j]
+			if remediation, ok := edited[file.SimilarityID]; ok {
+				file.Remediation = remediation
+			}
+		}
+	}
+}
+
+// Vulnerability returns the result of the report as a vulnerability, as collected by the remediation sets
+func (q *Query) Vulnerability(file *File) model.Vulnerability {
+	return model.Vulnerability{
+		FileName:         file.FilePath,
+		Line:             file.Line,
+		Remediation:      file.Remediation,
+		RemediationType:  file.RemediationType,
+		SimilarityID:     file.SimilarityID,
+		QueryID:          q.QueryID,
+		QueryName:        q.QueryName,
+		SearchKey:        file.SearchKey,
+		KeyExpectedValue: file.ExpectedValue,
+		KeyActualValue:   file.ActualValue,
+	}
+}
+
+// Preview returns the diff of the remediation of a single result without changing the file, or an empty
+// string when the remediation does not remove the result
+func Preview(vuln *model.Vulnerability, colored bool) string {
+	summary := &Summary{DryRun: true}
+
+	remediationSets := summary.GetRemediationSetsFromVulns([]model.Vulnerability{*vuln}, []string{"all"})
+	for filePath := range remediationSets {
+		if err := summary.RemediateFile(filePath, remediationSets[filePath].(Set)); err != nil {
+			return ""
+		}
+	}
+	return strings.TrimRight(summary.Patch(colored), "\n")
+}
@@ -0,0 +1,42 @@
+package remediation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Selection(t *testing.T) {
+	report := &Report{
+		Queries: []Query{
+			{
+				QueryID:   "41a38329-d81b-4be4-aef4-55b2615d3282",
+				QueryName: "RAM Account Password Policy Not Required Symbols",
+				Files: []File{
+					{FilePath: "main.tf", Line: 5, SimilarityID: "id-1", Remediation: "{\"after\":\"true\",\"before\":\"false\"}"},
+					{FilePath: "main.tf", Line: 9, SimilarityID: "id-2", Remediation: "{\"after\":\"true\",\"before\":\"false\"}"},
+				},
+			},
+		},
+	}
+
+	selection := &Selection{Results: "results.json"}
+	selection.Add(&report.Queries[0], &report.Queries[0].Files[0], "")
+	selection.Add(&report.Queries[0], &report.Queries[0].Files[1], "{\"after\":\"yes\",\"before\":\"false\"}")
+
+	path := filepath.Join(t.TempDir(), "selection.json")
+	require.NoError(t, selection.Save(path))
+
+	loaded, err := LoadSelection(path)
+	require.NoError(t, err)
+	require.Equal(t, selection, loaded)
+	require.Equal(t, []string{"id-1", "id-2"}, loaded.IDs())
+
+	loaded.Apply(report)
+	require.Equal(t, "{\"after\":\"true\",\"before\":\"false\"}", report.Queries[0].Files[0].Remediation)
+	require.Equal(t, "{\"after\":\"yes\",\"before\":\"false\"}", report.Queries[0].Files[1].Remediation)
+
+	_, err = LoadSelection(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -7,14 +7,21 @@ import (
 	"strings"
 
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/parser"
+	"github.com/Checkmarx/kics/pkg/scan"
 	"github.com/Checkmarx/kics/pkg/utils"
 	"github.com/rs/zerolog/log"
 )
 
 // Summary represents the information about the number of selected remediation and remediation done
+// DryRun keeps the files untouched, the accepted remediations are only collected in the file patches
 type Summary struct {
 	SelectedRemediationNumber   int
 	ActualRemediationDoneNumber int
+	DryRun                      bool
+	ScannedPaths                []string
+	patches                     map[string]*FilePatch
+	parsers                     []*parser.Parser
 }
 
 // GetRemediationSets collects all the replacements and additions per file
@@ -30,17 +37,36 @@ func (s *Summary) GetRemediationSets(results Report, include []string) map[strin
 	return remediationSets
 }
 
-func shouldRemediate(file *File, include []string) bool {
+func shouldRemediate(parsers []*parser.Parser, file *File, include []string) bool {
 	if len(file.Remediation) > 0 &&
 		len(file.RemediationType) > 0 &&
 		(include[0] == "all" || utils.Contains(file.SimilarityID, include)) &&
-		filepath.Ext(file.FilePath) == ".tf" { // temporary
+		isSupportedFile(parsers, file.FilePath) {
 		return true
 	}
 
 	return false
 }
 
+// isSupportedFile returns true when the file can be edited by syntax or parsed by the scan, so its remediation
+// can be verified
+func isSupportedFile(parsers []*parser.Parser, filePath string) bool {
+	return getEditor(filePath) != nil || getParser(parsers, filePath) != nil
+}
+
+// getParsers returns the parsers of the scan, built once per remediation run
+func (s *Summary) getParsers() []*parser.Parser {
+	if s.parsers == nil {
+		parsers, err := scan.NewParsers("", []string{""}, []string{""})
+		if err != nil {
+			log.Error().Msgf("failed to get parsers: %s", err)
+			parsers = []*parser.Parser{}
+		}
+		s.parsers = parsers
+	}
+	return s.parsers
+}
+
 func getBefore(line string) string {
 	re := regexp.MustCompile(`^[\s-]*`)
 	before := re.FindAll([]byte(line), -1)
@@ -49,45 +75,26 @@ func getBefore(line string) string {
 }
 
 // willRemediate verifies if the remediation actually removes the result
-func willRemediate(remediated []string, originalFileName string, remediation *Remediation) bool {
+func willRemediate(parsers []*parser.Parser, remediated []string, originalFileName string, remediation *Remediation) bool {
 	filepath.Clean(originalFileName)
-	// create temporary file
-	tmpFile := filepath.Join(os.TempDir(), "temporary-remediation-"+utils.NextRandom()+"-"+filepath.Base(originalFileName))
-	f, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
-
-	if err != nil {
-		log.Error().Msgf("failed to open temporary file for remediation '%s': %s", remediation.SimilarityID, err)
-		return false
-	}
-
 	content := []byte(strings.Join(remediated, "\n"))
 
-	defer func(f *os.File) {
-		err = f.Close()
-		if err != nil {
-			log.Err(err).Msgf("failed to close file: %s", tmpFile)
-		}
-	}(f)
-
-	if _, err = f.Write(content); err != nil {
-		log.Error().Msgf("failed to write temporary file for remediation '%s': %s", remediation.SimilarityID, err)
+	// create the temporary workspace with the remediated file
+	w, err := newWorkspace(originalFileName, content, remediation)
+	if err != nil {
+		log.Error().Msgf("failed to create temporary file for remediation '%s': %s", remediation.SimilarityID, err)
 		return false
 	}
+	defer w.remove()
 
 	// scan the temporary file to verify if the remediation removed the result
-	results, err := scanTmpFile(tmpFile, remediation.QueryID, content)
+	results, err := scanTmpFile(parsers, w, remediation.QueryID)
 
 	if err != nil {
 		log.Error().Msgf("failed to get results of query %s: %s", remediation.QueryID, err)
 		return false
 	}
 
-	err = os.Remove(tmpFile)
-
-	if err != nil {
-		log.Err(err)
-	}
-
 	return removedResult(results, remediation)
 }
 
@@ -155,26 +162,37 @@ func (s *Summary) GetRemediationSetsFromVulns(vulnerabilities []model.Vulnerabil
 
 		var remediationSet Set
 
-		if shouldRemediate(&file, include) {
+		if shouldRemediate(s.getParsers(), &file, include) {
 			s.SelectedRemediationNumber++
 			r := &Remediation{
 				Line:          file.Line,
 				Remediation:   file.Remediation,
 				SimilarityID:  file.SimilarityID,
 				QueryID:       vuln.QueryID,
+				QueryName:     vuln.QueryName,
 				SearchKey:     vuln.SearchKey,
 				ExpectedValue: vuln.KeyExpectedValue,
 				ActualValue:   vuln.KeyActualValue,
 			}
 
-			if file.RemediationType == "replacement" {
+			if file.RemediationType == ReplacementType {
+				// values of Helm charts and Terraform variables are remediated where they are defined
+				file.FilePath = mapValueSource(file.FilePath, r)
 				remediationSet.Replacement = append(remediationSet.Replacement, *r)
 			}
 
-			if file.RemediationType == "addition" {
+			if file.RemediationType == AdditionType {
 				remediationSet.Addition = append(remediationSet.Addition, *r)
 			}
 
+			if file.RemediationType == RemovalType {
+				remediationSet.Removal = append(remediationSet.Removal, *r)
+			}
+
+			if file.RemediationType == BlockType {
+				remediationSet.Block = append(remediationSet.Block, *r)
+			}
+
 			if _, ok := remediationSets[file.FilePath]; !ok {
 				remediationSets[file.FilePath] = remediationSet
 				continue
@@ -184,6 +202,8 @@ func (s *Summary) GetRemediationSetsFromVulns(vulnerabilities []model.Vulnerabil
 
 			updatedRemediationSet.Addition = append(updatedRemediationSet.Addition, remediationSet.Addition...)
 			updatedRemediationSet.Replacement = append(updatedRemediationSet.Replacement, remediationSet.Replacement...)
+			updatedRemediationSet.Removal = append(updatedRemediationSet.Removal, remediationSet.Removal...)
+			updatedRemediationSet.Block = append(updatedRemediationSet.Block, remediationSet.Block...)
 
 			remediationSets[file.FilePath] = updatedRemediationSet
 		}
@@ -197,20 +217,7 @@ func getVulns(results Report) []model.Vulnerability {
 		query := results.Queries[i]
 
 		for j := range query.Files {
-			file := query.Files[j]
-			vuln := &model.Vulnerability{
-				FileName:         file.FilePath,
-				Line:             file.Line,
-				Remediation:      file.Remediation,
-				RemediationType:  file.RemediationType,
-				SimilarityID:     file.SimilarityID,
-				QueryID:          query.QueryID,
-				SearchKey:        file.SearchKey,
-				KeyExpectedValue: file.ExpectedValue,
-				KeyActualValue:   file.ActualValue,
-			}
-
-			vulns = append(vulns, *vuln)
+			vulns = append(vulns, query.Vulnerability(&query.Files[j]))
 		}
 	}
 	return vulns
@@ -0,0 +1,407 @@
+package remediation
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/resolver"
+	"github.com/Checkmarx/kics/pkg/scan"
+	"github.com/Checkmarx/kics/pkg/utils"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	helmIDPrefix = "# KICS_HELM_ID_"
+	// helmValueSentinel is the value set to a chart values key to find out whether it is rendered at a result
+	helmValueSentinel = "kics-remediation-sentinel"
+)
+
+// workspace is the temporary directory where a remediated file is scanned again, along with the files its scan
+// depends on, the variables of the Terraform module or the whole Helm chart
+// resultFile is the file of the workspace where the result is reported, a template when the chart values are remediated
+// or the Terraform file when the tfvars are
+type workspace struct {
+	dir        string
+	file       string
+	chart      string
+	resultFile string
+}
+
+func newWorkspace(originalFile string, content []byte, r *Remediation) (*workspace, error) {
+	dir, err := os.MkdirTemp("", "kics-remediation-")
+	if err != nil {
+		return nil, err
+	}
+	w := &workspace{dir: dir}
+
+	if chartRoot := helmChartRoot(originalFile); chartRoot != "" {
+		w.chart = filepath.Join(dir, filepath.Base(chartRoot))
+		if err = copyDir(chartRoot, w.chart, nil); err != nil {
+			w.remove()
+			return nil, err
+		}
+		w.file = filepath.Join(w.chart, relativePath(chartRoot, originalFile))
+		w.resultFile = w.file
+		if r.ResultFile != "" {
+			w.resultFile = filepath.Join(w.chart, relativePath(chartRoot, r.ResultFile))
+		}
+	} else {
+		w.file = filepath.Join(dir, filepath.Base(originalFile))
+		w.resultFile = w.file
+		if r.ResultFile != "" {
+			w.resultFile = filepath.Join(dir, filepath.Base(r.ResultFile))
+		}
+		if isTerraformVariablesFile(originalFile) {
+			// the variables of the module are defined in the other files of the directory
+			if err = copyDir(filepath.Dir(originalFile), dir, isTerraformVariablesFile); err != nil {
+				w.remove()
+				return nil, err
+			}
+		}
+	}
+
+	if err = os.WriteFile(w.file, content, os.ModePerm); err != nil {
+		w.remove()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *workspace) remove() {
+	if err := os.RemoveAll(w.dir); err != nil {
+		log.Err(err).Msgf("failed to remove temporary directory: %s", w.dir)
+	}
+}
+
+// helmChartRoot returns the directory of the Helm chart holding the file, or an empty string
+func helmChartRoot(filePath string) string {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return ""
+	}
+	for {
+		if resolver.GetKind(dir) == model.KindHELM {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// mapValueSource maps a replacement to the file defining the value when the result line takes it from elsewhere,
+// the chart values of a Helm template or the tfvars of a Terraform module, so the fix is done where it is defined
+func mapValueSource(filePath string, r *Remediation) string {
+	var info ReplacementInfo
+	if r.Line < 1 || json.Unmarshal([]byte(r.Remediation), &info) != nil || info.Before == "" {
+		return filePath
+	}
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return filePath
+	}
+	lines := strings.Split(string(content), "\n")
+	if r.Line > len(lines) || strings.Contains(lines[r.Line-1], info.Before) {
+		return filePath
+	}
+	if chartRoot := helmChartRoot(filePath); chartRoot != "" {
+		return mapHelmValues(chartRoot, filePath, r)
+	}
+	if utils.GetExtension(filePath) == ".tf" {
+		return mapTerraformVariables(filePath, content, r, info)
+	}
+	return filePath
+}
+
+// mapHelmValues maps a replacement of a chart template to the key of the chart values rendered at the result,
+// the rendered document of the result is selected with the IDInfo of the resolver and each values key holding the
+// rendered value is verified by rendering the chart again with the key changed
+func mapHelmValues(chartRoot, template string, r *Remediation) string {
+	document, ok := renderedHelmDocument(chartRoot, template, r.Line)
+	if !ok {
+		return template
+	}
+	target, err := resolveYAMLDocuments(document.Content, parseSearchKey(r.SearchKey))
+	if err != nil || target.node.Kind != yaml.ScalarNode {
+		return template
+	}
+
+	valuesFile := filepath.Join(chartRoot, "values.yaml")
+	values, err := os.ReadFile(filepath.Clean(valuesFile))
+	if err != nil {
+		return template
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal(values, &root); err != nil || len(root.Content) == 0 {
+		return template
+	}
+	for _, leaf := range yamlLeaves(root.Content[0], "") {
+		if leaf.node.Value != target.node.Value || !rendersHelmValue(chartRoot, template, document, leaf, r.SearchKey) {
+			continue
+		}
+		log.Debug().Msgf("remediation '%s' is mapped to '%s' in '%s'", r.SimilarityID, leaf.key, valuesFile)
+		r.Line = leaf.node.Line
+		r.ResultFile = template
+		r.ValueKey = leaf.key
+		return valuesFile
+	}
+	return template
+}
+
+// renderedHelmDocument renders the chart and returns the document of the template including the template line,
+// the lines of the resolver IDInfo count the auxiliary ID lines it adds to the template, the result line does not
+func renderedHelmDocument(chartRoot, template string, line int) (model.ResolvedHelm, bool) {
+	for _, rfile := range renderHelmChart(chartRoot) {
+		if relativePath(template, rfile.FileName) != "." {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(rfile.SplitID), helmIDPrefix), ":"))
+		if err != nil {
+			continue
+		}
+		lines, ok := rfile.IDInfo[id].(map[int]int)
+		if !ok {
+			continue
+		}
+		count := 0
+		for idx, text := range strings.Split(string(rfile.OriginalData), "\n") {
+			if strings.Contains(text, helmIDPrefix) {
+				continue
+			}
+			if count++; count == line {
+				if _, ok := lines[idx]; ok {
+					return rfile, true
+				}
+				break
+			}
+		}
+	}
+	return model.ResolvedHelm{}, false
+}
+
+// rendersHelmValue renders a copy of the chart with a sentinel value at the values key and returns true when the
+// sentinel is rendered at the search key of the same document of the template
+func rendersHelmValue(chartRoot, template string, document model.ResolvedHelm, leaf yamlLeaf, searchKey string) bool {
+	dir, err := os.MkdirTemp("", "kics-remediation-")
+	if err != nil {
+		return false
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Err(err).Msgf("failed to remove temporary directory: %s", dir)
+		}
+	}()
+	chart := filepath.Join(dir, filepath.Base(chartRoot))
+	if err = copyDir(chartRoot, chart, nil); err != nil {
+		return false
+	}
+	valuesFile := filepath.Join(chart, "values.yaml")
+	values, err := os.ReadFile(filepath.Clean(valuesFile))
+	if err != nil {
+		return false
+	}
+	changed, err := yamlEditor{}.replace(values, parseSearchKey(leaf.key),
+		ReplacementInfo{Before: leaf.node.Value, After: helmValueSentinel})
+	if err != nil || os.WriteFile(valuesFile, changed, os.ModePerm) != nil {
+		return false
+	}
+
+	rendered, ok := renderedHelmSplit(chart, filepath.Join(chart, relativePath(chartRoot, template)), document.SplitID)
+	if !ok {
+		return false
+	}
+	target, err := resolveYAMLDocuments(rendered.Content, parseSearchKey(searchKey))
+	return err == nil && target.node.Value == helmValueSentinel
+}
+
+// renderedHelmSplit renders the chart and returns the document of the template with the given split ID
+func renderedHelmSplit(chartRoot, template, splitID string) (model.ResolvedHelm, bool) {
+	for _, rfile := range renderHelmChart(chartRoot) {
+		if rfile.SplitID == splitID && relativePath(template, rfile.FileName) == "." {
+			return rfile, true
+		}
+	}
+	return model.ResolvedHelm{}, false
+}
+
+// renderHelmChart renders the chart with the resolver of the scan, a chart failing to render has no documents
+func renderHelmChart(chartRoot string) []model.ResolvedHelm {
+	combinedResolver, err := scan.NewResolver()
+	if err != nil {
+		return nil
+	}
+	resolved, err := combinedResolver.Resolve(chartRoot, model.KindHELM)
+	if err != nil {
+		log.Debug().Msgf("failed to render helm chart '%s': %s", chartRoot, err)
+		return nil
+	}
+	return resolved.File
+}
+
+// yamlLeaf is a scalar of the chart values and its search key
+type yamlLeaf struct {
+	key  string
+	node *yaml.Node
+}
+
+// yamlLeaves returns the scalars of the nested mappings of the node, keys holding dots can not be addressed
+// by a search key, so they are left out
+func yamlLeaves(node *yaml.Node, prefix string) []yamlLeaf {
+	leaves := make([]yamlLeaf, 0)
+	if node.Kind != yaml.MappingNode {
+		return leaves
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		key, value := node.Content[idx].Value, node.Content[idx+1]
+		if strings.Contains(key, ".") {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value.Kind {
+		case yaml.ScalarNode:
+			leaves = append(leaves, yamlLeaf{key: key, node: value})
+		case yaml.MappingNode:
+			leaves = append(leaves, yamlLeaves(value, key)...)
+		}
+	}
+	return leaves
+}
+
+// mapTerraformVariables maps a replacement of a Terraform attribute set from a variable to the tfvars file assigning
+// the variable, the files are searched in the reverse order KICS merges them, so the assignment in use is fixed
+func mapTerraformVariables(filePath string, content []byte, r *Remediation, info ReplacementInfo) string {
+	file, err := parseHCL(content)
+	if err != nil {
+		return filePath
+	}
+	target, ok := resolveHCL(file.Body(), parseSearchKey(r.SearchKey))
+	if !ok || target.attribute == "" {
+		return filePath
+	}
+	tokens := target.body.GetAttribute(target.attribute).Expr().BuildTokens(nil).Bytes()
+	expression, diagnostics := hclsyntax.ParseExpression(tokens, filePath, hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return filePath
+	}
+
+	for _, traversal := range expression.Variables() {
+		if traversal.RootName() != "var" || len(traversal) < 2 {
+			continue
+		}
+		name, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		for _, variablesFile := range terraformVariablesFiles(filepath.Dir(filePath)) {
+			line, defined := tfvarsAssignment(variablesFile, name.Name, info.Before)
+			if !defined {
+				continue
+			}
+			if line == 0 {
+				break
+			}
+			log.Debug().Msgf("remediation '%s' is mapped to '%s' in '%s'", r.SimilarityID, name.Name, variablesFile)
+			r.Line = line
+			r.ResultFile = filePath
+			r.ValueKey = name.Name
+			return variablesFile
+		}
+	}
+	return filePath
+}
+
+// terraformVariablesFiles returns the tfvars files of the module from the last merged to the first
+func terraformVariablesFiles(dir string) []string {
+	files := make([]string, 0)
+	if _, err := os.Stat(filepath.Join(dir, "terraform.tfvars")); err == nil {
+		files = append(files, filepath.Join(dir, "terraform.tfvars"))
+	}
+	autoFiles, err := filepath.Glob(filepath.Join(dir, "*.auto.tfvars"))
+	if err != nil {
+		return files
+	}
+	for idx := len(autoFiles) - 1; idx >= 0; idx-- {
+		files = append(files, autoFiles[idx])
+	}
+	return files
+}
+
+// tfvarsAssignment returns whether the variable is assigned in the tfvars file and the line of the assignment
+// when it holds the value to replace, or zero
+func tfvarsAssignment(variablesFile, name, value string) (line int, defined bool) {
+	content, err := os.ReadFile(filepath.Clean(variablesFile))
+	if err != nil {
+		return 0, false
+	}
+	file, diagnostics := hclsyntax.ParseConfig(content, variablesFile, hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return 0, false
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return 0, false
+	}
+	attribute, ok := body.Attributes[name]
+	if !ok {
+		return 0, false
+	}
+	if !strings.Contains(string(attribute.Expr.Range().SliceBytes(content)), value) {
+		return 0, true
+	}
+	return attribute.Expr.Range().Start.Line, true
+}
+
+func isTerraformVariablesFile(path string) bool {
+	base := filepath.Base(path)
+	return filepath.Ext(base) == ".tf" || strings.HasSuffix(base, ".auto.tfvars") || base == "terraform.tfvars"
+}
+
+// copyDir copies the files of the source directory accepted by the filter, or all of them when there is no filter,
+// the filter only copies the files of the source directory itself
+func copyDir(source, destination string, filter func(path string) bool) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath(source, path))
+		if entry.IsDir() {
+			if filter != nil && path != source {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, os.ModePerm)
+		}
+		if filter != nil && !filter(path) {
+			return nil
+		}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, os.ModePerm)
+	})
+}
+
+func relativePath(base, path string) string {
+	absBase, errBase := filepath.Abs(base)
+	absPath, errPath := filepath.Abs(path)
+	if errBase != nil || errPath != nil {
+		return filepath.Base(path)
+	}
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return rel
+}
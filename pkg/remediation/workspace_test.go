@@ -0,0 +1,171 @@
+package remediation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeChart(t *testing.T) string {
+	chart := filepath.Join(t.TempDir(), "chart")
+	require.NoError(t, os.MkdirAll(filepath.Join(chart, "templates"), os.ModePerm))
+
+	files := map[string]string{
+		"Chart.yaml":  "apiVersion: v2\nname: chart\nversion: 0.1.0\n",
+		"values.yaml": "securityContext:\n  privileged: true\nreplicaCount: 1\n",
+		filepath.Join("templates", "pod.yaml"): "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\nspec:\n" +
+			"  containers:\n    - name: app\n      securityContext:\n        privileged: {{ .Values.securityContext.privileged }}\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(chart, name), []byte(content), os.ModePerm))
+	}
+	return chart
+}
+
+func Test_HelmChartRoot(t *testing.T) {
+	chart := writeChart(t)
+
+	require.Equal(t, chart, helmChartRoot(filepath.Join(chart, "templates", "pod.yaml")))
+	require.Equal(t, chart, helmChartRoot(filepath.Join(chart, "values.yaml")))
+	require.Equal(t, "", helmChartRoot(filepath.Join(t.TempDir(), "main.tf")))
+}
+
+func Test_MapValueSource(t *testing.T) {
+	chart := writeChart(t)
+	template := filepath.Join(chart, "templates", "pod.yaml")
+
+	module := t.TempDir()
+	for name, content := range map[string]string{
+		"main.tf":          "resource \"aws_s3_bucket\" \"b\" {\n  acl = var.acl\n}\n",
+		"variables.tf":     "variable \"acl\" {}\n",
+		"a.auto.tfvars":    "acl = \"public-read\"\n",
+		"terraform.tfvars": "\nacl = \"public-read\"\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(module, name), []byte(content), os.ModePerm))
+	}
+	mainFile := filepath.Join(module, "main.tf")
+
+	tests := []struct {
+		name        string
+		filePath    string
+		remediation Remediation
+		wantFile    string
+		wantLine    int
+		wantKey     string
+	}{
+		{
+			name:     "value taken from the chart values",
+			filePath: template,
+			remediation: Remediation{
+				Line:        9,
+				SearchKey:   "metadata.name={{pod}}.spec.containers.name={{app}}.securityContext.privileged",
+				Remediation: "{\"before\":\"true\",\"after\":\"false\"}",
+			},
+			wantFile: filepath.Join(chart, "values.yaml"),
+			wantLine: 2,
+			wantKey:  "securityContext.privileged",
+		},
+		{
+			name:        "value written in the template",
+			filePath:    template,
+			remediation: Remediation{Line: 2, SearchKey: "kind", Remediation: "{\"before\":\"Pod\",\"after\":\"Deployment\"}"},
+			wantFile:    template,
+			wantLine:    2,
+		},
+		{
+			name:     "value taken from the last merged tfvars",
+			filePath: mainFile,
+			remediation: Remediation{
+				Line:        2,
+				SearchKey:   "aws_s3_bucket[b].acl",
+				Remediation: "{\"before\":\"public-read\",\"after\":\"private\"}",
+			},
+			wantFile: filepath.Join(module, "terraform.tfvars"),
+			wantLine: 2,
+			wantKey:  "acl",
+		},
+		{
+			name:     "variable without the value",
+			filePath: mainFile,
+			remediation: Remediation{
+				Line:        2,
+				SearchKey:   "aws_s3_bucket[b].acl",
+				Remediation: "{\"before\":\"authenticated-read\",\"after\":\"private\"}",
+			},
+			wantFile: mainFile,
+			wantLine: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.remediation
+			require.Equal(t, tt.wantFile, mapValueSource(tt.filePath, &r))
+			require.Equal(t, tt.wantLine, r.Line)
+			require.Equal(t, tt.wantKey, r.ValueKey)
+			if tt.wantKey != "" {
+				require.Equal(t, tt.filePath, r.ResultFile)
+			}
+		})
+	}
+}
+
+func Test_NewWorkspace(t *testing.T) {
+	t.Run("helm chart is copied with the remediated values", func(t *testing.T) {
+		chart := writeChart(t)
+		r := &Remediation{ResultFile: filepath.Join(chart, "templates", "pod.yaml")}
+
+		w, err := newWorkspace(filepath.Join(chart, "values.yaml"), []byte("securityContext:\n  privileged: false\n"), r)
+		require.NoError(t, err)
+		defer w.remove()
+
+		content, err := os.ReadFile(w.file)
+		require.NoError(t, err)
+		require.Equal(t, "securityContext:\n  privileged: false\n", string(content))
+		require.Equal(t, filepath.Join(w.chart, "templates", "pod.yaml"), w.resultFile)
+		require.FileExists(t, filepath.Join(w.chart, "Chart.yaml"))
+	})
+
+	t.Run("terraform variables are copied", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, content := range map[string]string{
+			"main.tf":          "resource \"aws_s3_bucket\" \"b\" {}\n",
+			"variables.tf":     "variable \"name\" {}\n",
+			"terraform.tfvars": "name = \"bucket\"\n",
+			"notes.txt":        "notes\n",
+		} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), os.ModePerm))
+		}
+
+		w, err := newWorkspace(filepath.Join(dir, "main.tf"), []byte("resource \"aws_s3_bucket\" \"c\" {}\n"), &Remediation{})
+		require.NoError(t, err)
+		defer w.remove()
+
+		require.FileExists(t, filepath.Join(w.dir, "variables.tf"))
+		require.FileExists(t, filepath.Join(w.dir, "terraform.tfvars"))
+		require.NoFileExists(t, filepath.Join(w.dir, "notes.txt"))
+		require.Equal(t, w.file, w.resultFile)
+	})
+}
+
+func Test_IsSupportedFile(t *testing.T) {
+	tests := []struct {
+		filePath string
+		want     bool
+	}{
+		{filePath: "main.tf", want: true},
+		{filePath: "template.json", want: true},
+		{filePath: "Dockerfile", want: true},
+		{filePath: "service.proto", want: true},
+		{filePath: "ansible.cfg", want: true},
+		{filePath: "README.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filePath, func(t *testing.T) {
+			require.Equal(t, tt.want, isSupportedFile((&Summary{}).getParsers(), tt.filePath))
+		})
+	}
+}
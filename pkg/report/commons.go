@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/Checkmarx/kics/pkg/model"
-	reportModel "github.com/Checkmarx/kics/pkg/report/model"
 	"github.com/gocarina/gocsv"
 	"github.com/rs/zerolog/log"
 )
@@ -138,7 +137,8 @@ func exportXMLReport(path, filename string, body interface{}) error {
 	return encoder.Encode(body)
 }
 
-func exportCSVReport(path, filename string, body []reportModel.CSVReport) error {
+// exportCSVReport writes a CSV file with a row per element of body, which must be a pointer to a slice of structs
+func exportCSVReport(path, filename string, body interface{}) error {
 	fullPath := filepath.Join(path, filename)
 	f, err := os.OpenFile(filepath.Clean(fullPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
 	if err != nil {
@@ -147,5 +147,17 @@ func exportCSVReport(path, filename string, body []reportModel.CSVReport) error
 
 	defer closeFile(fullPath, filename, f)
 
-	return gocsv.MarshalFile(&body, f)
+	return gocsv.MarshalFile(body, f)
+}
+
+func exportTextReport(fullPath, filename, content string) error {
+	f, err := os.OpenFile(filepath.Clean(fullPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	defer closeFile(fullPath, filename, f)
+
+	_, err = f.WriteString(content)
+	return err
 }
@@ -22,5 +22,5 @@ func PrintCSVReport(path, filename string, body interface{}) error {
 		report = reportModel.BuildCSVReport(&summary)
 	}
 
-	return exportCSVReport(path, filename, report)
+	return exportCSVReport(path, filename, &report)
 }
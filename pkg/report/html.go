@@ -78,16 +78,34 @@ func getVersion() string {
 
 // PrintHTMLReport creates a report file on HTML format
 func PrintHTMLReport(path, filename string, body interface{}) error {
+	return PrintHTMLReportWithOptions(path, filename, body, &Options{})
+}
+
+// PrintHTMLReportWithOptions creates a report file on HTML format, with a trends section when the options have
+// the summaries of previous scans
+func PrintHTMLReportWithOptions(path, filename string, body interface{}, options *Options) error {
 	if !strings.HasSuffix(filename, ".html") {
 		filename += ".html"
 	}
 
+	var trends *htmlTrends
+	if options != nil && len(options.HTMLHistory) > 0 && body != "" {
+		summary, err := templateSummary(body)
+		if err != nil {
+			return err
+		}
+		trends = newHTMLTrends(options.HTMLHistory, &summary)
+	}
+
 	templateFuncs["includeSVG"] = includeSVG
 	templateFuncs["includeCSS"] = includeCSS
 	templateFuncs["includeJS"] = includeJS
 	templateFuncs["getPaths"] = getPaths
 	templateFuncs["getPlatforms"] = getPlatforms
 	templateFuncs["getVersion"] = getVersion
+	templateFuncs["getTrends"] = func() *htmlTrends {
+		return trends
+	}
 
 	fullPath := filepath.Join(path, filename)
 	t := template.Must(template.New("report.tmpl").Funcs(templateFuncs).Parse(htmlTemplate))
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/test"
@@ -46,3 +47,49 @@ func TestPrintHTMLReport(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintHTMLReportWithOptions(t *testing.T) {
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	summary := model.Summary{
+		Times: model.Times{Start: start.Add(48 * time.Hour)},
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "ALB protocol is HTTP",
+				QueryID:   "de7f5e83-da88-4046-871f-ea18504b1d43",
+				Severity:  model.SeverityHigh,
+				Files: []model.VulnerableFile{
+					{FileName: "positive.tf", Line: 25, SimilarityID: "open", VulnLines: &[]model.CodeLine{}},
+				},
+			},
+		},
+	}
+	previous := &model.Summary{
+		Times: model.Times{Start: start},
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "S3 Bucket <Logging> Disabled",
+				QueryID:   "f861041c-8c9f-4156-acfc-5e6e524f5884",
+				Severity:  model.SeverityMedium,
+				Files:     []model.VulnerableFile{{FileName: "positive.tf", Line: 1, SimilarityID: "fixed"}},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, PrintHTMLReportWithOptions(dir, "results", summary, &Options{HTMLHistory: []*model.Summary{previous}}))
+	content, err := os.ReadFile(filepath.Join(dir, "results.html"))
+	require.NoError(t, err)
+	_, err = html.Parse(strings.NewReader(string(content)))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "Trends:")
+	require.Contains(t, string(content), "2.0 days")
+	require.Contains(t, string(content), "S3 Bucket &lt;Logging&gt; Disabled")
+	require.Contains(t, string(content), `aria-label="Results per severity"`)
+	require.Contains(t, string(content), `aria-label="Introduced and fixed results per scan"`)
+	require.Contains(t, string(content), `aria-label="Slowest queries to fix"`)
+
+	require.NoError(t, PrintHTMLReport(dir, "results", summary))
+	content, err = os.ReadFile(filepath.Join(dir, "results.html"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "Trends:")
+}
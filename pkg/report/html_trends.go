@@ -0,0 +1,240 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/results"
+)
+
+// size of the trend charts, scaled to the width of the report
+const (
+	chartWidth       = 760
+	chartHeight      = 240
+	chartLeft        = 48
+	chartRight       = 16
+	chartTop         = 16
+	chartBottom      = 40
+	chartTicks       = 4
+	chartMaxLabels   = 8
+	barHeight        = 24
+	barLabelWidth    = 320
+	barLabelMaxRunes = 48
+)
+
+var trendSeverities = []model.Severity{model.SeverityHigh, model.SeverityMedium, model.SeverityLow, model.SeverityInfo}
+
+var trendColors = map[model.Severity]string{
+	model.SeverityHigh:   "#fc6e3a",
+	model.SeverityMedium: "#fdb48f",
+	model.SeverityLow:    "#503e9e",
+	model.SeverityInfo:   "#a89fd0",
+}
+
+const (
+	introducedColor = "#fc6e3a"
+	fixedColor      = "#503e9e"
+)
+
+// htmlTrends is the trends section of the html report, with its charts rendered as inline SVG
+type htmlTrends struct {
+	Scans               int
+	Fixed               int
+	MeanTimeToRemediate string
+	SeveritySVG         template.HTML
+	ChangesSVG          template.HTML
+	SlowestSVG          template.HTML
+}
+
+// newHTMLTrends renders the trends of the previous scans and the current one, nil when there is no previous scan
+func newHTMLTrends(history []*model.Summary, summary *model.Summary) *htmlTrends {
+	scans := make([]*model.Summary, 0, len(history)+1)
+	for _, previous := range history {
+		// the reports of the output directory may include the one of the current scan
+		if summary.Start.IsZero() || previous.Start.Before(summary.Start) {
+			scans = append(scans, previous)
+		}
+	}
+	if len(scans) == 0 {
+		return nil
+	}
+	scans = append(scans, summary)
+
+	trends := results.NewTrends(scans)
+	return &htmlTrends{
+		Scans:               len(trends.Scans),
+		Fixed:               trends.Fixed,
+		MeanTimeToRemediate: formatRemediationTime(trends.MeanTimeToRemediate),
+		SeveritySVG:         severityTrendSVG(trends.Scans),
+		ChangesSVG:          changesTrendSVG(trends.Scans),
+		SlowestSVG:          slowestQueriesSVG(trends.SlowestQueries),
+	}
+}
+
+// formatRemediationTime formats a remediation time in days, in hours below a day and in minutes below an hour
+func formatRemediationTime(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Hour:
+		return fmt.Sprintf("%.0f minutes", d.Minutes())
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%.1f hours", d.Hours())
+	default:
+		return fmt.Sprintf("%.1f days", d.Hours()/24)
+	}
+}
+
+// chartScale returns the maximum of the y axis, a multiple of the number of ticks above the maximum value
+func chartScale(maxValue int) int {
+	step := int(math.Ceil(float64(maxValue) / chartTicks))
+	if step == 0 {
+		step = 1
+	}
+	return step * chartTicks
+}
+
+func chartY(value, scale int) float64 {
+	return chartTop + float64(chartHeight-chartTop-chartBottom)*(1-float64(value)/float64(scale))
+}
+
+// chartAxes writes the horizontal grid of the y axis and the dates of the scans on the x axis
+func chartAxes(sb *strings.Builder, scans []results.TrendScan, scale int, x func(int) float64) {
+	for tick := 0; tick <= chartTicks; tick++ {
+		value := scale / chartTicks * tick
+		y := chartY(value, scale)
+		fmt.Fprintf(sb, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e8e8e8"/>`, chartLeft, y, chartWidth-chartRight, y)
+		fmt.Fprintf(sb, `<text x="%d" y="%.1f" text-anchor="end" font-size="11">%d</text>`, chartLeft-6, y+4, value)
+	}
+	every := int(math.Ceil(float64(len(scans)) / chartMaxLabels))
+	for i := range scans {
+		if i%every != 0 && i != len(scans)-1 {
+			continue
+		}
+		fmt.Fprintf(sb, `<text x="%.1f" y="%d" text-anchor="middle" font-size="11">%s</text>`,
+			x(i), chartHeight-chartBottom+18, scans[i].Start.Format("Jan 02"))
+	}
+}
+
+func chartLegend(sb *strings.Builder, labels, colors []string) {
+	x := chartWidth - chartRight
+	for i := len(labels) - 1; i >= 0; i-- {
+		x -= 8 + 7*len(labels[i]) + 16
+		fmt.Fprintf(sb, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, x, chartHeight-14, colors[i])
+		fmt.Fprintf(sb, `<text x="%d" y="%d" font-size="11">%s</text>`, x+14, chartHeight-5, labels[i])
+	}
+}
+
+func openSVG(sb *strings.Builder, height int, label string) {
+	fmt.Fprintf(sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		chartWidth, height, html.EscapeString(label))
+}
+
+// severityTrendSVG renders a line per severity with the results of each scan
+func severityTrendSVG(scans []results.TrendScan) template.HTML {
+	maxValue := 0
+	for i := range scans {
+		for _, severity := range trendSeverities {
+			maxValue = int(math.Max(float64(maxValue), float64(scans[i].SeverityCounters[severity])))
+		}
+	}
+	scale := chartScale(maxValue)
+	x := func(i int) float64 {
+		if len(scans) == 1 {
+			return chartLeft
+		}
+		return chartLeft + float64(chartWidth-chartLeft-chartRight)*float64(i)/float64(len(scans)-1)
+	}
+
+	var sb strings.Builder
+	openSVG(&sb, chartHeight, "Results per severity")
+	chartAxes(&sb, scans, scale, x)
+	labels := make([]string, 0, len(trendSeverities))
+	colors := make([]string, 0, len(trendSeverities))
+	for _, severity := range trendSeverities {
+		points := make([]string, 0, len(scans))
+		for i := range scans {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), chartY(scans[i].SeverityCounters[severity], scale)))
+		}
+		fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`,
+			strings.Join(points, " "), trendColors[severity])
+		for i := range scans {
+			fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: %d %s</title></circle>`,
+				x(i), chartY(scans[i].SeverityCounters[severity], scale), trendColors[severity],
+				scans[i].Start.Format("Jan 02 2006 15:04"), scans[i].SeverityCounters[severity], severity)
+		}
+		labels = append(labels, string(severity))
+		colors = append(colors, trendColors[severity])
+	}
+	chartLegend(&sb, labels, colors)
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String()) //nolint
+}
+
+// changesTrendSVG renders the results introduced and fixed by each scan since the previous one
+func changesTrendSVG(scans []results.TrendScan) template.HTML {
+	maxValue := 0
+	for i := range scans {
+		maxValue = int(math.Max(float64(maxValue), math.Max(float64(scans[i].Introduced), float64(scans[i].Fixed))))
+	}
+	scale := chartScale(maxValue)
+	slot := float64(chartWidth-chartLeft-chartRight) / float64(len(scans))
+	x := func(i int) float64 {
+		return chartLeft + slot*(float64(i)+0.5)
+	}
+	bar := math.Min(slot*0.35, 24)
+
+	var sb strings.Builder
+	openSVG(&sb, chartHeight, "Introduced and fixed results per scan")
+	chartAxes(&sb, scans, scale, x)
+	base := chartY(0, scale)
+	// the first scan has no previous scan to compare with
+	for i := 1; i < len(scans); i++ {
+		for j, value := range []int{scans[i].Introduced, scans[i].Fixed} {
+			color, label := introducedColor, "introduced"
+			if j == 1 {
+				color, label = fixedColor, "fixed"
+			}
+			y := chartY(value, scale)
+			fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %d %s</title></rect>`,
+				x(i)-bar+float64(j)*bar, y, bar, base-y, color, scans[i].Start.Format("Jan 02 2006 15:04"), value, label)
+		}
+	}
+	chartLegend(&sb, []string{"Introduced", "Fixed"}, []string{introducedColor, fixedColor})
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String()) //nolint
+}
+
+// slowestQueriesSVG renders a bar per query with the mean time taken to fix its results, empty when no result
+// was fixed
+func slowestQueriesSVG(queries []results.QueryRemediation) template.HTML {
+	if len(queries) == 0 {
+		return ""
+	}
+	maxTime := queries[0].MeanTime
+	width := float64(chartWidth - barLabelWidth - 96)
+
+	var sb strings.Builder
+	openSVG(&sb, barHeight*len(queries), "Slowest queries to fix")
+	for i := range queries {
+		name := []rune(queries[i].QueryName)
+		if len(name) > barLabelMaxRunes {
+			name = append(name[:barLabelMaxRunes-1], '…')
+		}
+		y := i * barHeight
+		length := math.Max(width*float64(queries[i].MeanTime)/float64(maxTime), 1)
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="end" font-size="12">%s<title>%s (%s)</title></text>`,
+			barLabelWidth-8, y+16, html.EscapeString(string(name)), html.EscapeString(queries[i].QueryName), queries[i].Severity)
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"/>`,
+			barLabelWidth, y+4, length, barHeight-8, trendColors[queries[i].Severity])
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d" font-size="12">%s (%d fixed)</text>`,
+			float64(barLabelWidth)+length+6, y+16, formatRemediationTime(queries[i].MeanTime), queries[i].Fixed)
+	}
+	sb.WriteString(`</svg>`)
+	return template.HTML(sb.String()) //nolint
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"strings"
+
+	reportModel "github.com/Checkmarx/kics/pkg/report/model"
+	"github.com/rs/zerolog/log"
+)
+
+// PrintInventoryCycloneDxReport prints the bill of materials inventory as a CycloneDX 1.5 JSON document
+func PrintInventoryCycloneDxReport(path, filename string, body interface{}) error {
+	if !strings.HasPrefix(filename, "inventory-cyclonedx-") {
+		filename = "inventory-cyclonedx-" + filename
+	}
+
+	inventory, err := getInventory(body)
+	if err != nil {
+		return err
+	}
+
+	return ExportJSONReport(path, filename, reportModel.BuildInventoryCycloneDxReport(inventory))
+}
+
+// PrintInventoryCSVReport prints the bill of materials inventory as a CSV file with a row per resource
+func PrintInventoryCSVReport(path, filename string, body interface{}) error {
+	if !strings.HasPrefix(filename, "inventory-") {
+		filename = "inventory-" + filename
+	}
+	if !strings.HasSuffix(filename, ".csv") {
+		filename += ".csv"
+	}
+
+	inventory, err := getInventory(body)
+	if err != nil {
+		return err
+	}
+
+	return exportCSVReport(path, filename, &inventory)
+}
+
+func getInventory(body interface{}) ([]reportModel.InventoryResource, error) {
+	if body == "" {
+		return []reportModel.InventoryResource{}, nil
+	}
+
+	summary, err := getSummary(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(summary.Bom) == 0 {
+		log.Warn().Msg("The inventory report is empty, use the --bom flag to include the bill of materials queries")
+	}
+
+	return reportModel.BuildInventory(&summary), nil
+}
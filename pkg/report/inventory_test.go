@@ -0,0 +1,44 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintInventoryReports(t *testing.T) {
+	tests := []struct {
+		name     string
+		print    func(path, filename string, body interface{}) error
+		expected string
+	}{
+		{
+			name:     "print inventory cyclonedx report",
+			print:    PrintInventoryCycloneDxReport,
+			expected: "inventory-cyclonedx-output.json",
+		},
+		{
+			name:     "print inventory csv report",
+			print:    PrintInventoryCSVReport,
+			expected: "inventory-output.csv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(os.TempDir(), "testdir")
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(path)
+
+			summary := test.SummaryMock
+			err := tt.print(path, "output", &summary)
+			require.NoError(t, err)
+			require.FileExists(t, filepath.Join(path, tt.expected))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// DefaultMarkdownMaxLength is the character budget of the markdown report, the size limit of GitHub comments
+const DefaultMarkdownMaxLength = 65536
+
+const markdownExtension = ".md"
+
+var markdownCodeLanguages = map[string]string{
+	".tf":         "hcl",
+	".hcl":        "hcl",
+	".yaml":       "yaml",
+	".yml":        "yaml",
+	".json":       "json",
+	".bicep":      "bicep",
+	".proto":      "protobuf",
+	".dockerfile": "dockerfile",
+}
+
+// Options are the options of the report formats
+type Options struct {
+	// MarkdownMaxLength is the character budget of the markdown report, 0 for no limit
+	MarkdownMaxLength int
+	// SarifBaseline is the summary of a previous scan the results of the sarif report are compared with
+	SarifBaseline *model.Summary
+	// HTMLHistory are the summaries of previous scans, from the oldest, the html report shows the trends of
+	HTMLHistory []*model.Summary
+}
+
+// PrintMarkdownReport prints the markdown report with the default character budget
+func PrintMarkdownReport(path, filename string, body interface{}) error {
+	return PrintMarkdownReportWithOptions(path, filename, body, &Options{MarkdownMaxLength: DefaultMarkdownMaxLength})
+}
+
+// PrintMarkdownReportWithOptions prints the markdown report in the given path and filename with the given body,
+// to be posted as a pull request comment, keeping the highest severity results within the character budget
+func PrintMarkdownReportWithOptions(path, filename string, body interface{}, options *Options) error {
+	if !strings.HasSuffix(filename, markdownExtension) {
+		filename += markdownExtension
+	}
+
+	summary := model.Summary{}
+	if body != "" {
+		var err error
+		if summary, err = templateSummary(body); err != nil {
+			return err
+		}
+	}
+
+	content := markdownReport(&summary, options.MarkdownMaxLength)
+	return exportTextReport(filepath.Join(path, filename), filename, content)
+}
+
+// markdownReport renders the most findings, from the highest severity, fitting the character budget
+func markdownReport(summary *model.Summary, maxLength int) string {
+	findings := templateFindings(summary.Queries)
+	content := renderMarkdown(summary, findings, len(findings))
+	if maxLength <= 0 || utf8.RuneCountInString(content) <= maxLength {
+		return content
+	}
+
+	// the length grows with the findings, search the most findings within the budget
+	count := sort.Search(len(findings), func(i int) bool {
+		return utf8.RuneCountInString(renderMarkdown(summary, findings, i+1)) > maxLength
+	})
+	content = renderMarkdown(summary, findings, count)
+	if utf8.RuneCountInString(content) > maxLength {
+		content = string([]rune(content)[:maxLength])
+	}
+	return content
+}
+
+func renderMarkdown(summary *model.Summary, findings []TemplateFinding, count int) string {
+	var sb strings.Builder
+	sb.WriteString("## KICS Scan Results\n\n")
+	writeMarkdownSummary(&sb, summary)
+
+	if len(findings) == 0 {
+		sb.WriteString("\nNo results were found.\n")
+		return sb.String()
+	}
+
+	for _, group := range markdownGroups(findings[:count]) {
+		fmt.Fprintf(&sb, "\n<details>\n<summary><code>%s</code>: %d %s (%s)</summary>\n\n",
+			html.EscapeString(relativeToScannedPaths(summary.ScannedPaths, group.Key)),
+			len(group.Findings), plural(len(group.Findings), "finding"), group.Severity)
+		for i := range group.Findings {
+			writeMarkdownFinding(&sb, &group.Findings[i])
+		}
+		sb.WriteString("</details>\n")
+	}
+
+	if hidden := len(findings) - count; hidden > 0 {
+		fmt.Fprintf(&sb, "\n> :warning: %d more %s not shown, see the full report for all the results.\n",
+			hidden, plural(hidden, "finding"))
+	}
+	return sb.String()
+}
+
+func writeMarkdownSummary(sb *strings.Builder, summary *model.Summary) {
+	sb.WriteString("| Severity | Results |\n| --- | ---: |\n")
+	for _, severity := range model.AllSeverities {
+		count := summary.SeverityCounters[severity]
+		if severity == model.SeverityTrace && count == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "| %s | %d |\n", severity, count)
+	}
+	fmt.Fprintf(sb, "| **TOTAL** | **%d** |\n", summary.TotalCounter)
+}
+
+func writeMarkdownFinding(sb *strings.Builder, finding *TemplateFinding) {
+	name := finding.Query.QueryName
+	if finding.Query.QueryURI != "" {
+		name = fmt.Sprintf("[%s](%s)", name, finding.Query.QueryURI)
+	}
+	fmt.Fprintf(sb, "- **%s** %s, line %d: %s\n", finding.Query.Severity, name, finding.File.Line,
+		strings.TrimSpace(finding.File.KeyActualValue))
+
+	code := snippet(*finding.File)
+	if code == "" {
+		return
+	}
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(sb, "\n  %s%s\n", fence, markdownCodeLanguage(finding.File.FileName))
+	for _, line := range strings.Split(code, "\n") {
+		fmt.Fprintf(sb, "  %s\n", line)
+	}
+	fmt.Fprintf(sb, "  %s\n\n", fence)
+}
+
+// markdownGroups groups the findings by file, the files with the highest severity first
+func markdownGroups(findings []TemplateFinding) []TemplateGroup {
+	groups := groupFindings(findings, func(finding *TemplateFinding) []string {
+		return []string{finding.File.FileName}
+	})
+	sort.SliceStable(groups, func(i, j int) bool {
+		if rankI, rankJ := severityRank(groups[i].Severity), severityRank(groups[j].Severity); rankI != rankJ {
+			return rankI < rankJ
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+func markdownCodeLanguage(fileName string) string {
+	if strings.EqualFold(filepath.Base(fileName), "dockerfile") {
+		return "dockerfile"
+	}
+	return markdownCodeLanguages[strings.ToLower(filepath.Ext(fileName))]
+}
+
+func plural(count int, word string) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func markdownSummary(files int) *model.Summary {
+	low := model.QueryResult{
+		QueryName: "Resource Not Using Tags",
+		QueryURI:  "https://docs.kics.io/tags",
+		Severity:  model.SeverityLow,
+	}
+	for i := 0; i < files; i++ {
+		low.Files = append(low.Files, model.VulnerableFile{
+			FileName:       fmt.Sprintf("low%d.tf", i),
+			Line:           1,
+			KeyActualValue: "tags is undefined",
+		})
+	}
+
+	return &model.Summary{
+		SeveritySummary: model.SeveritySummary{
+			SeverityCounters: map[model.Severity]int{model.SeverityHigh: 1, model.SeverityLow: files},
+			TotalCounter:     files + 1,
+		},
+		Queries: model.QueryResultSlice{
+			low,
+			{
+				QueryName: "S3 Bucket ACL Allows Read To All Users",
+				QueryURI:  "https://docs.kics.io/s3",
+				Severity:  model.SeverityHigh,
+				Files: []model.VulnerableFile{
+					{
+						FileName:       "main.tf",
+						Line:           3,
+						KeyActualValue: "acl is public-read",
+						VulnLines: &[]model.CodeLine{
+							{Position: 2, Line: `resource "aws_s3_bucket" "b" {`},
+							{Position: 3, Line: `  acl = "public-read"`},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPrintMarkdownReport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, PrintMarkdownReport(dir, "results", *markdownSummary(2)))
+
+	content, err := os.ReadFile(filepath.Join(dir, "results.md"))
+	require.NoError(t, err)
+	report := string(content)
+
+	require.Contains(t, report, "| HIGH | 1 |")
+	require.Contains(t, report, "| **TOTAL** | **3** |")
+	require.Contains(t, report, "<summary><code>main.tf</code>: 1 finding (HIGH)</summary>")
+	require.Contains(t, report, "- **HIGH** [S3 Bucket ACL Allows Read To All Users](https://docs.kics.io/s3), line 3: acl is public-read")
+	require.Contains(t, report, "  ```hcl\n  2: resource \"aws_s3_bucket\" \"b\" {\n  3:   acl = \"public-read\"\n  ```")
+	require.Less(t, strings.Index(report, "main.tf"), strings.Index(report, "low0.tf"))
+	require.NotContains(t, report, "more findings")
+}
+
+func TestMarkdownReportTruncation(t *testing.T) {
+	summary := markdownSummary(50)
+	full := markdownReport(summary, 0)
+
+	maxLength := utf8.RuneCountInString(full) / 2
+	truncated := markdownReport(summary, maxLength)
+
+	require.LessOrEqual(t, utf8.RuneCountInString(truncated), maxLength)
+	require.Contains(t, truncated, "S3 Bucket ACL Allows Read To All Users")
+	require.Contains(t, truncated, "more findings not shown")
+	require.Contains(t, truncated, "| **TOTAL** | **51** |")
+	require.Equal(t, strings.Count(truncated, "<details>"), strings.Count(truncated, "</details>"))
+
+	require.Equal(t, full, markdownReport(summary, utf8.RuneCountInString(full)))
+}
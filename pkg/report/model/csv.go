@@ -1,30 +1,38 @@
 package model
 
-import "github.com/Checkmarx/kics/pkg/model"
+import (
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
 
 // CSVReport struct contains all the info to create the csv report
 type CSVReport struct {
-	QueryName                   string `csv:"query_name"`
-	QueryID                     string `csv:"query_id"`
-	QueryURI                    string `csv:"query_uri"`
-	Severity                    string `csv:"severity"`
-	Platform                    string `csv:"platform"`
-	CloudProvider               string `csv:"cloud_provider"`
-	Category                    string `csv:"category"`
-	DescriptionID               string `csv:"description_id"`
-	Description                 string `csv:"description"`
-	CISDescriptionIDFormatted   string `csv:"cis_description_id"`
-	CISDescriptionTitle         string `csv:"cis_description_title"`
-	CISDescriptionTextFormatted string `csv:"cis_description_text"`
-	FileName                    string `csv:"file_name"`
-	SimilarityID                string `csv:"similarity_id"`
-	Line                        int    `csv:"line"`
-	IssueType                   string `csv:"issue_type"`
-	SearchKey                   string `csv:"search_key"`
-	SearchLine                  int    `csv:"search_line"`
-	SearchValue                 string `csv:"search_value"`
-	ExpectedValue               string `csv:"expected_value"`
-	ActualValue                 string `csv:"actual_value"`
+	QueryName                   string  `csv:"query_name"`
+	QueryID                     string  `csv:"query_id"`
+	QueryURI                    string  `csv:"query_uri"`
+	Severity                    string  `csv:"severity"`
+	Platform                    string  `csv:"platform"`
+	CloudProvider               string  `csv:"cloud_provider"`
+	Category                    string  `csv:"category"`
+	DescriptionID               string  `csv:"description_id"`
+	Description                 string  `csv:"description"`
+	CISDescriptionIDFormatted   string  `csv:"cis_description_id"`
+	CISDescriptionTitle         string  `csv:"cis_description_title"`
+	CISDescriptionTextFormatted string  `csv:"cis_description_text"`
+	FileName                    string  `csv:"file_name"`
+	SimilarityID                string  `csv:"similarity_id"`
+	Line                        int     `csv:"line"`
+	IssueType                   string  `csv:"issue_type"`
+	SearchKey                   string  `csv:"search_key"`
+	SearchLine                  int     `csv:"search_line"`
+	SearchValue                 string  `csv:"search_value"`
+	ExpectedValue               string  `csv:"expected_value"`
+	ActualValue                 string  `csv:"actual_value"`
+	RiskScore                   float64 `csv:"risk_score"`
+	Owners                      string  `csv:"owners"`
+	Author                      string  `csv:"author"`
+	CommitDate                  string  `csv:"commit_date"`
 }
 
 // BuildCSVReport builds the CSV report
@@ -55,6 +63,10 @@ func BuildCSVReport(summary *model.Summary) []CSVReport {
 				SearchValue:                 summary.Queries[i].Files[j].SearchValue,
 				ExpectedValue:               summary.Queries[i].Files[j].KeyExpectedValue,
 				ActualValue:                 summary.Queries[i].Files[j].KeyActualValue,
+				RiskScore:                   summary.Queries[i].Files[j].RiskScore,
+				Owners:                      strings.Join(summary.Queries[i].Files[j].Owners, " "),
+				Author:                      summary.Queries[i].Files[j].Author,
+				CommitDate:                  summary.Queries[i].Files[j].CommitDate,
 			})
 		}
 	}
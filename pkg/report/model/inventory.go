@@ -0,0 +1,273 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	inventoryUnknown    = "unknown"
+	inventoryPropPrefix = "kics:"
+)
+
+// dataCategories are the BoM resource categories exported as CycloneDX data components,
+// every other category is exported as a CycloneDX service
+var dataCategories = map[string]bool{
+	"Storage":                  true,
+	"In Memory Data Structure": true,
+}
+
+// InventoryResource is a resource of the bill of materials normalized across platforms and providers
+type InventoryResource struct {
+	Type          string `json:"type" csv:"resource_type"`
+	Name          string `json:"name" csv:"resource_name"`
+	Provider      string `json:"provider" csv:"provider"`
+	Category      string `json:"category" csv:"category"`
+	Engine        string `json:"engine,omitempty" csv:"engine"`
+	Encryption    string `json:"encryption" csv:"encryption"`
+	Accessibility string `json:"accessibility" csv:"accessibility"`
+	Public        bool   `json:"public" csv:"public"`
+	Platform      string `json:"platform" csv:"platform"`
+	QueryID       string `json:"query_id" csv:"query_id"`
+	FileName      string `json:"file_name" csv:"file_name"`
+	Line          int    `json:"line" csv:"line"`
+	SimilarityID  string `json:"similarity_id" csv:"similarity_id"`
+}
+
+// bomValue is the output of a BoM query, as documented in docs/bom.md
+type bomValue struct {
+	ResourceType          string `json:"resource_type"`
+	ResourceName          string `json:"resource_name"`
+	ResourceAccessibility string `json:"resource_accessibility"`
+	ResourceEncryption    string `json:"resource_encryption"`
+	ResourceVendor        string `json:"resource_vendor"`
+	ResourceCategory      string `json:"resource_category"`
+	ResourceEngine        string `json:"resource_engine"`
+}
+
+// BuildInventory normalizes the bill of materials of the summary into a resource inventory,
+// sorted by provider, type and name
+func BuildInventory(summary *model.Summary) []InventoryResource {
+	inventory := make([]InventoryResource, 0)
+
+	for i := range summary.Bom {
+		query := &summary.Bom[i]
+		for j := range query.Files {
+			file := &query.Files[j]
+			if file.Value == nil {
+				continue
+			}
+			var value bomValue
+			if err := json.Unmarshal([]byte(*file.Value), &value); err != nil {
+				log.Debug().Err(err).Msgf("Failed to parse bill of materials value of query %s", query.QueryID)
+				continue
+			}
+
+			inventory = append(inventory, InventoryResource{
+				Type:          value.ResourceType,
+				Name:          orUnknown(value.ResourceName),
+				Provider:      orUnknown(value.ResourceVendor),
+				Category:      orUnknown(value.ResourceCategory),
+				Engine:        value.ResourceEngine,
+				Encryption:    orUnknown(value.ResourceEncryption),
+				Accessibility: orUnknown(value.ResourceAccessibility),
+				Public:        isPubliclyExposed(value.ResourceAccessibility),
+				Platform:      query.Platform,
+				QueryID:       query.QueryID,
+				FileName:      file.FileName,
+				Line:          file.Line,
+				SimilarityID:  file.SimilarityID,
+			})
+		}
+	}
+
+	sort.SliceStable(inventory, func(a, b int) bool {
+		if inventory[a].Provider != inventory[b].Provider {
+			return inventory[a].Provider < inventory[b].Provider
+		}
+		if inventory[a].Type != inventory[b].Type {
+			return inventory[a].Type < inventory[b].Type
+		}
+		return inventory[a].Name < inventory[b].Name
+	})
+
+	return inventory
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return inventoryUnknown
+	}
+	return value
+}
+
+// isPubliclyExposed tells if the accessibility reported by a BoM query means the resource is reachable publicly
+func isPubliclyExposed(accessibility string) bool {
+	return strings.EqualFold(accessibility, "public") || strings.Contains(accessibility, "unrestricted")
+}
+
+// InventoryCycloneDxReport is a CycloneDX 1.5 JSON document describing the resource inventory
+type InventoryCycloneDxReport struct {
+	BomFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     InventoryMetadata    `json:"metadata"`
+	Components   []InventoryComponent `json:"components"`
+	Services     []InventoryService   `json:"services"`
+}
+
+// InventoryMetadata is the metadata of the CycloneDX inventory document
+type InventoryMetadata struct {
+	Timestamp string         `json:"timestamp"`
+	Tools     InventoryTools `json:"tools"`
+}
+
+// InventoryTools lists the tools used to create the CycloneDX inventory document
+type InventoryTools struct {
+	Components []InventoryTool `json:"components"`
+}
+
+// InventoryTool describes the tool used to create the CycloneDX inventory document
+type InventoryTool struct {
+	Type    string `json:"type"`
+	Group   string `json:"group"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InventoryProperty is a CycloneDX name/value property
+type InventoryProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// InventoryComponentData describes the data held by a data component
+type InventoryComponentData struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// InventoryComponent is a CycloneDX component of type data
+type InventoryComponent struct {
+	Type       string                   `json:"type"`
+	BomRef     string                   `json:"bom-ref"`
+	Group      string                   `json:"group,omitempty"`
+	Name       string                   `json:"name"`
+	Data       []InventoryComponentData `json:"data"`
+	Properties []InventoryProperty      `json:"properties"`
+}
+
+// InventoryProvider is the organization providing a service
+type InventoryProvider struct {
+	Name string `json:"name"`
+}
+
+// InventoryService is a CycloneDX service
+type InventoryService struct {
+	BomRef        string              `json:"bom-ref"`
+	Provider      *InventoryProvider  `json:"provider,omitempty"`
+	Group         string              `json:"group,omitempty"`
+	Name          string              `json:"name"`
+	TrustBoundary bool                `json:"x-trust-boundary"`
+	Properties    []InventoryProperty `json:"properties"`
+}
+
+// BuildInventoryCycloneDxReport builds the CycloneDX 1.5 document of the resource inventory, data stores are
+// exported as data components and the remaining resources as services
+func BuildInventoryCycloneDxReport(inventory []InventoryResource) *InventoryCycloneDxReport {
+	bom := &InventoryCycloneDxReport{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.New().String(),
+		Version:      1,
+		Metadata: InventoryMetadata{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Tools: InventoryTools{
+				Components: []InventoryTool{
+					{
+						Type:    "application",
+						Group:   "Checkmarx",
+						Name:    "KICS",
+						Version: constants.Version,
+					},
+				},
+			},
+		},
+		Components: make([]InventoryComponent, 0),
+		Services:   make([]InventoryService, 0),
+	}
+
+	for i := range inventory {
+		resource := &inventory[i]
+		if dataCategories[resource.Category] {
+			bom.Components = append(bom.Components, InventoryComponent{
+				Type:   "data",
+				BomRef: inventoryBomRef(resource),
+				Group:  resource.Type,
+				Name:   resource.Name,
+				Data: []InventoryComponentData{
+					{
+						Type: "dataset",
+						Name: resource.Name,
+					},
+				},
+				Properties: inventoryProperties(resource),
+			})
+			continue
+		}
+
+		service := InventoryService{
+			BomRef:        inventoryBomRef(resource),
+			Group:         resource.Type,
+			Name:          resource.Name,
+			TrustBoundary: resource.Public,
+			Properties:    inventoryProperties(resource),
+		}
+		if resource.Provider != inventoryUnknown {
+			service.Provider = &InventoryProvider{Name: resource.Provider}
+		}
+		bom.Services = append(bom.Services, service)
+	}
+
+	return bom
+}
+
+// inventoryBomRef identifies a resource in the CycloneDX document, falling back to its location
+// when the finding has no similarity ID
+func inventoryBomRef(resource *InventoryResource) string {
+	if resource.SimilarityID != "" {
+		return resource.SimilarityID
+	}
+	return fmt.Sprintf("%s:%d:%s.%s", resource.FileName, resource.Line, resource.Type, resource.Name)
+}
+
+func inventoryProperties(resource *InventoryResource) []InventoryProperty {
+	properties := []InventoryProperty{
+		{Name: inventoryPropPrefix + "resource_type", Value: resource.Type},
+		{Name: inventoryPropPrefix + "provider", Value: resource.Provider},
+		{Name: inventoryPropPrefix + "category", Value: resource.Category},
+		{Name: inventoryPropPrefix + "encryption", Value: resource.Encryption},
+		{Name: inventoryPropPrefix + "accessibility", Value: resource.Accessibility},
+		{Name: inventoryPropPrefix + "public", Value: strconv.FormatBool(resource.Public)},
+	}
+	if resource.Engine != "" {
+		properties = append(properties, InventoryProperty{Name: inventoryPropPrefix + "engine", Value: resource.Engine})
+	}
+
+	return append(properties,
+		InventoryProperty{Name: inventoryPropPrefix + "platform", Value: resource.Platform},
+		InventoryProperty{Name: inventoryPropPrefix + "query_id", Value: resource.QueryID},
+		InventoryProperty{Name: inventoryPropPrefix + "file_name", Value: resource.FileName},
+		InventoryProperty{Name: inventoryPropPrefix + "line", Value: strconv.Itoa(resource.Line)},
+	)
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func bomValuePtr(value string) *string {
+	return &value
+}
+
+var inventorySummaryMock = model.Summary{
+	Bom: model.QueryResultSlice{
+		{
+			QueryName: "BOM - Kubernetes Service",
+			QueryID:   "40cd4f98-2940-4d1c-b137-554ad4ab6a92",
+			Platform:  "Kubernetes",
+			Files: []model.VulnerableFile{
+				{
+					FileName:     "service.yaml",
+					Line:         4,
+					SimilarityID: "svc",
+					Value:        bomValuePtr(`{"resource_type":"Service","resource_name":"web","resource_accessibility":"public","resource_encryption":"unknown","resource_vendor":"Kubernetes","resource_category":"Networking"}`),
+				},
+			},
+		},
+		{
+			QueryName: "BOM - Azure Storage Account",
+			QueryID:   "33f5ffc7-0768-4bf3-8449-0b8b3a82d689",
+			Platform:  "Terraform",
+			Files: []model.VulnerableFile{
+				{
+					FileName:     "main.tf",
+					Line:         1,
+					SimilarityID: "storage",
+					Value:        bomValuePtr(`{"resource_type":"azurerm_storage_account","resource_name":"logs","resource_accessibility":"private","resource_encryption":"encrypted","resource_vendor":"Azure","resource_category":"Storage"}`),
+				},
+				{
+					FileName: "broken.tf",
+					Line:     1,
+					Value:    bomValuePtr("not json"),
+				},
+			},
+		},
+	},
+}
+
+func TestBuildInventory(t *testing.T) {
+	got := BuildInventory(&inventorySummaryMock)
+
+	require.Equal(t, []InventoryResource{
+		{
+			Type:          "azurerm_storage_account",
+			Name:          "logs",
+			Provider:      "Azure",
+			Category:      "Storage",
+			Encryption:    "encrypted",
+			Accessibility: "private",
+			Platform:      "Terraform",
+			QueryID:       "33f5ffc7-0768-4bf3-8449-0b8b3a82d689",
+			FileName:      "main.tf",
+			Line:          1,
+			SimilarityID:  "storage",
+		},
+		{
+			Type:          "Service",
+			Name:          "web",
+			Provider:      "Kubernetes",
+			Category:      "Networking",
+			Encryption:    "unknown",
+			Accessibility: "public",
+			Public:        true,
+			Platform:      "Kubernetes",
+			QueryID:       "40cd4f98-2940-4d1c-b137-554ad4ab6a92",
+			FileName:      "service.yaml",
+			Line:          4,
+			SimilarityID:  "svc",
+		},
+	}, got)
+}
+
+func TestBuildInventoryCycloneDxReport(t *testing.T) {
+	got := BuildInventoryCycloneDxReport(BuildInventory(&inventorySummaryMock))
+
+	require.Equal(t, "CycloneDX", got.BomFormat)
+	require.Equal(t, "1.5", got.SpecVersion)
+	require.Len(t, got.Components, 1)
+	require.Equal(t, "data", got.Components[0].Type)
+	require.Equal(t, "storage", got.Components[0].BomRef)
+	require.Equal(t, "logs", got.Components[0].Name)
+	require.Contains(t, got.Components[0].Properties, InventoryProperty{Name: "kics:encryption", Value: "encrypted"})
+
+	require.Len(t, got.Services, 1)
+	require.Equal(t, "web", got.Services[0].Name)
+	require.Equal(t, &InventoryProvider{Name: "Kubernetes"}, got.Services[0].Provider)
+	require.True(t, got.Services[0].TrustBoundary)
+	require.Contains(t, got.Services[0].Properties, InventoryProperty{Name: "kics:file_name", Value: "service.yaml"})
+}
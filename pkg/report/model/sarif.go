@@ -1,8 +1,13 @@
 package model
 
 import (
+	"encoding/json"
+	"strings"
+	"unicode/utf16"
+
 	"github.com/Checkmarx/kics/internal/constants"
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/results"
 	"github.com/rs/zerolog/log"
 )
 
@@ -23,6 +28,25 @@ var targetTemplate = sarifDescriptorReference{
 	},
 }
 
+var cweTemplate = sarifDescriptorReference{
+	ToolComponent: sarifComponentReference{
+		ComponentReferenceGUID:  "ff2a3ec0-4bf5-4a4c-9a4a-8ef4a3e0a0d2",
+		ComponentReferenceName:  "CWE",
+		ComponentReferenceIndex: 1,
+	},
+}
+
+// similarityFingerprint is the partial fingerprint of the results, so their alerts follow them when lines shift
+const similarityFingerprint = "similarityID/v1"
+
+// baseline states of the results when the report is compared with a baseline
+const (
+	baselineStateNew       = "new"
+	baselineStateUnchanged = "unchanged"
+	baselineStateUpdated   = "updated"
+	baselineStateAbsent    = "absent"
+)
+
 type sarifProperties map[string]interface{}
 
 type ruleMetadata struct {
@@ -31,6 +55,7 @@ type ruleMetadata struct {
 	queryDescription string
 	queryURI         string
 	queryCategory    string
+	queryCWE         string
 	severity         model.Severity
 }
 
@@ -88,7 +113,10 @@ type sarifTool struct {
 }
 
 type sarifRegion struct {
-	StartLine int `json:"startLine"`
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
 }
 
 type sarifArtifactLocation struct {
@@ -104,12 +132,76 @@ type sarifLocation struct {
 	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
 }
 
+type sarifArtifactPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifRelatedLocation struct {
+	ID               int                           `json:"id"`
+	PhysicalLocation sarifArtifactPhysicalLocation `json:"physicalLocation"`
+	Message          sarifMessage                  `json:"message"`
+}
+
+type sarifFlowPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifFlowLocation struct {
+	PhysicalLocation sarifFlowPhysicalLocation `json:"physicalLocation"`
+	Message          sarifMessage              `json:"message"`
+}
+
+type sarifThreadFlowLocation struct {
+	Location sarifFlowLocation `json:"location"`
+}
+
+type sarifThreadFlow struct {
+	Locations []sarifThreadFlowLocation `json:"locations"`
+}
+
+type sarifCodeFlow struct {
+	ThreadFlows []sarifThreadFlow `json:"threadFlows"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion           `json:"deletedRegion"`
+	InsertedContent *sarifArtifactContent `json:"insertedContent,omitempty"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifSuppression struct {
+	Kind          string                 `json:"kind"`
+	Status        string                 `json:"status"`
+	Justification string                 `json:"justification,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
+}
+
 type sarifResult struct {
-	ResultRuleID    string          `json:"ruleId"`
-	ResultRuleIndex int             `json:"ruleIndex"`
-	ResultKind      string          `json:"kind"`
-	ResultMessage   sarifMessage    `json:"message"`
-	ResultLocations []sarifLocation `json:"locations"`
+	ResultRuleID              string                 `json:"ruleId"`
+	ResultRuleIndex           int                    `json:"ruleIndex"`
+	ResultKind                string                 `json:"kind"`
+	ResultMessage             sarifMessage           `json:"message"`
+	ResultLocations           []sarifLocation        `json:"locations"`
+	ResultRelatedLocations    []sarifRelatedLocation `json:"relatedLocations,omitempty"`
+	ResultCodeFlows           []sarifCodeFlow        `json:"codeFlows,omitempty"`
+	ResultPartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	ResultFixes               []sarifFix             `json:"fixes,omitempty"`
+	ResultSuppressions        []sarifSuppression     `json:"suppressions,omitempty"`
+	ResultBaselineState       string                 `json:"baselineState,omitempty"`
 }
 
 type sarifTaxanomyDefinition struct {
@@ -117,11 +209,14 @@ type sarifTaxanomyDefinition struct {
 	DefinitionName             string       `json:"name"`
 	DefinitionShortDescription sarifMessage `json:"shortDescription"`
 	DefinitionFullDescription  sarifMessage `json:"fullDescription"`
+	DefinitionHelpURI          string       `json:"helpUri,omitempty"`
 }
 
 type sarifTaxonomy struct {
 	TaxonomyGUID             string                    `json:"guid"`
 	TaxonomyName             string                    `json:"name"`
+	TaxonomyOrganization     string                    `json:"organization,omitempty"`
+	TaxonomyInformationURI   string                    `json:"informationUri,omitempty"`
 	TaxonomyFullDescription  sarifMessage              `json:"fullDescription"`
 	TaxonomyShortDescription sarifMessage              `json:"shortDescription"`
 	TaxonomyDefinitions      []sarifTaxanomyDefinition `json:"taxa"`
@@ -137,12 +232,15 @@ type SarifRun struct {
 // SarifReport represents a usable sarif report reference
 type SarifReport interface {
 	BuildSarifIssue(issue *model.QueryResult)
+	BuildSarifBaseline(diffs ...*results.Diff)
 }
 
 type sarifReport struct {
 	Schema       string     `json:"$schema"`
 	SarifVersion string     `json:"version"`
 	Runs         []SarifRun `json:"runs"`
+	// resultFiles keeps the vulnerable file of each result, to match the results with a baseline
+	resultFiles []*model.VulnerableFile
 }
 
 func initSarifTool() sarifTool {
@@ -232,6 +330,48 @@ func (sr *sarifReport) findSarifRuleIndex(ruleID string) int {
 	return -1
 }
 
+// buildSarifCWE returns the reference to the CWE taxon of the weakness, adding the CWE taxonomy and the taxon
+// when the first rule refers to them
+func (sr *sarifReport) buildSarifCWE(cwe string) sarifDescriptorReference {
+	if len(sr.Runs[0].Taxonomies) == 1 {
+		sr.Runs[0].Taxonomies = append(sr.Runs[0].Taxonomies, sarifTaxonomy{
+			TaxonomyGUID:           cweTemplate.ToolComponent.ComponentReferenceGUID,
+			TaxonomyName:           cweTemplate.ToolComponent.ComponentReferenceName,
+			TaxonomyOrganization:   "MITRE",
+			TaxonomyInformationURI: "https://cwe.mitre.org/",
+			TaxonomyShortDescription: sarifMessage{
+				Text: "The MITRE Common Weakness Enumeration",
+			},
+			TaxonomyFullDescription: sarifMessage{
+				Text: "This taxonomy contains the weaknesses the issues are related to",
+			},
+			TaxonomyDefinitions: make([]sarifTaxanomyDefinition, 0),
+		})
+	}
+	taxonomy := &sr.Runs[0].Taxonomies[cweTemplate.ToolComponent.ComponentReferenceIndex]
+
+	target := cweTemplate
+	target.ReferenceID = cwe
+	target.ReferenceIndex = -1
+	for idx := range taxonomy.TaxonomyDefinitions {
+		if taxonomy.TaxonomyDefinitions[idx].DefinitionID == cwe {
+			target.ReferenceIndex = idx
+		}
+	}
+	if target.ReferenceIndex < 0 {
+		name := "CWE-" + cwe
+		taxonomy.TaxonomyDefinitions = append(taxonomy.TaxonomyDefinitions, sarifTaxanomyDefinition{
+			DefinitionID:               cwe,
+			DefinitionName:             name,
+			DefinitionShortDescription: sarifMessage{Text: name},
+			DefinitionFullDescription:  sarifMessage{Text: name},
+			DefinitionHelpURI:          "https://cwe.mitre.org/data/definitions/" + cwe + ".html",
+		})
+		target.ReferenceIndex = len(taxonomy.TaxonomyDefinitions) - 1
+	}
+	return target
+}
+
 func (sr *sarifReport) buildSarifRule(queryMetadata *ruleMetadata, cisMetadata ruleCISMetadata) int {
 	index := sr.findSarifRuleIndex(queryMetadata.queryID)
 	if index < 0 {
@@ -256,6 +396,14 @@ func (sr *sarifReport) buildSarifRule(queryMetadata *ruleMetadata, cisMetadata r
 				"cisTitle": cisMetadata.title,
 			}
 		}
+		if cwe := strings.TrimPrefix(strings.ToUpper(queryMetadata.queryCWE), "CWE-"); cwe != "" {
+			rule.RuleRelationships = append(rule.RuleRelationships, sarifDescriptorRelationship{Target: sr.buildSarifCWE(cwe)})
+			if rule.RuleProperties == nil {
+				rule.RuleProperties = sarifProperties{}
+			}
+			// code scanning shows the weaknesses of the alerts from the rule tags
+			rule.RuleProperties["tags"] = []string{"external/cwe/cwe-" + cwe}
+		}
 
 		sr.Runs[0].Tool.Driver.Rules = append(sr.Runs[0].Tool.Driver.Rules, rule)
 		index = len(sr.Runs[0].Tool.Driver.Rules) - 1
@@ -263,48 +411,254 @@ func (sr *sarifReport) buildSarifRule(queryMetadata *ruleMetadata, cisMetadata r
 	return index
 }
 
+func (sr *sarifReport) buildSarifIssueRule(issue *model.QueryResult) int {
+	metadata := ruleMetadata{
+		queryID:          issue.QueryID,
+		queryName:        issue.QueryName,
+		queryDescription: issue.Description,
+		queryURI:         issue.QueryURI,
+		queryCategory:    issue.Category,
+		queryCWE:         issue.CWE,
+		severity:         issue.Severity,
+	}
+	cisDescriptions := ruleCISMetadata{
+		id:              issue.CISDescriptionIDFormatted,
+		title:           issue.CISDescriptionTitle,
+		descriptionText: issue.CISDescriptionTextFormatted,
+	}
+	return sr.buildSarifRule(&metadata, cisDescriptions)
+}
+
 // BuildSarifIssue creates a new entries in Results (one for each file) and new entry in Rules and Taxonomy if necessary
 func (sr *sarifReport) BuildSarifIssue(issue *model.QueryResult) {
 	if len(issue.Files) > 0 {
-		metadata := ruleMetadata{
-			queryID:          issue.QueryID,
-			queryName:        issue.QueryName,
-			queryDescription: issue.Description,
-			queryURI:         issue.QueryURI,
-			queryCategory:    issue.Category,
-			severity:         issue.Severity,
+		ruleIndex := sr.buildSarifIssueRule(issue)
+		for idx := range issue.Files {
+			sr.buildSarifResult(issue, ruleIndex, &issue.Files[idx])
 		}
-		cisDescriptions := ruleCISMetadata{
-			id:              issue.CISDescriptionIDFormatted,
-			title:           issue.CISDescriptionTitle,
-			descriptionText: issue.CISDescriptionTextFormatted,
+	}
+}
+
+// BuildSarifBaseline sets the baseline state of the results from their comparison with the results of a baseline
+// scan, and adds the results of the baseline that are no longer found as absent
+func (sr *sarifReport) BuildSarifBaseline(diffs ...*results.Diff) {
+	states := make(map[*model.VulnerableFile]string)
+	for _, diff := range diffs {
+		for idx := range diff.Added {
+			states[diff.Added[idx].File] = baselineStateNew
+		}
+		for idx := range diff.Changed {
+			states[diff.Changed[idx].New.File] = baselineStateUpdated
 		}
-		ruleIndex := sr.buildSarifRule(&metadata, cisDescriptions)
+	}
+	for idx := range sr.Runs[0].Results {
+		state, ok := states[sr.resultFiles[idx]]
+		if !ok {
+			state = baselineStateUnchanged
+		}
+		sr.Runs[0].Results[idx].ResultBaselineState = state
+	}
 
-		kind := "fail"
-		if severityLevelEquivalence[issue.Severity] == "none" {
-			kind = "informational"
+	for _, diff := range diffs {
+		for idx := range diff.Removed {
+			removed := diff.Removed[idx]
+			result := sr.buildSarifResult(removed.Query, sr.buildSarifIssueRule(removed.Query), removed.File)
+			result.ResultBaselineState = baselineStateAbsent
+			// the fix of a result no longer found does not apply to the current files
+			result.ResultFixes = nil
 		}
-		for idx := range issue.Files {
-			line := issue.Files[idx].Line
-			if line < 1 {
-				line = 1
-			}
-			result := sarifResult{
-				ResultRuleID:    issue.QueryID,
-				ResultRuleIndex: ruleIndex,
-				ResultKind:      kind,
-				ResultMessage:   sarifMessage{Text: issue.Files[idx].KeyActualValue},
-				ResultLocations: []sarifLocation{
-					{
-						PhysicalLocation: sarifPhysicalLocation{
-							ArtifactLocation: sarifArtifactLocation{ArtifactURI: issue.Files[idx].FileName},
-							Region:           sarifRegion{StartLine: line},
+	}
+}
+
+// buildSarifResult adds the result of the vulnerable file and returns it
+func (sr *sarifReport) buildSarifResult(issue *model.QueryResult, ruleIndex int, file *model.VulnerableFile) *sarifResult {
+	kind := "fail"
+	if severityLevelEquivalence[issue.Severity] == "none" {
+		kind = "informational"
+	}
+	line := file.Line
+	if line < 1 {
+		line = 1
+	}
+	result := sarifResult{
+		ResultRuleID:    issue.QueryID,
+		ResultRuleIndex: ruleIndex,
+		ResultKind:      kind,
+		ResultMessage:   sarifMessage{Text: file.KeyActualValue},
+		ResultLocations: []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{ArtifactURI: file.FileName},
+					Region:           sarifRegion{StartLine: line},
+				},
+			},
+		},
+		ResultRelatedLocations: buildSarifRelatedLocations(file),
+		ResultCodeFlows:        buildSarifCodeFlows(file, line),
+		ResultFixes:            buildSarifFixes(file),
+		ResultSuppressions:     buildSarifSuppressions(file.Suppression),
+	}
+	if file.SimilarityID != "" {
+		result.ResultPartialFingerprints = map[string]string{similarityFingerprint: file.SimilarityID}
+	}
+	sr.Runs[0].Results = append(sr.Runs[0].Results, result)
+	sr.resultFiles = append(sr.resultFiles, file)
+	return &sr.Runs[0].Results[len(sr.Runs[0].Results)-1]
+}
+
+// buildSarifRelatedLocations creates the location of the file the result file was resolved from, the file
+// referencing it or the values of its Helm chart
+func buildSarifRelatedLocations(file *model.VulnerableFile) []sarifRelatedLocation {
+	if file.ResolvedFrom == "" {
+		return nil
+	}
+	return []sarifRelatedLocation{
+		{
+			ID: 1,
+			PhysicalLocation: sarifArtifactPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{ArtifactURI: file.ResolvedFrom},
+			},
+			Message: sarifMessage{Text: "Resolved from " + file.ResolvedFrom},
+		},
+	}
+}
+
+// buildSarifCodeFlows creates the flow of a result resolved through other files, from the file it was resolved
+// from, the Helm chart values, the file with the reference or the including Ansible playbook, to the result
+func buildSarifCodeFlows(file *model.VulnerableFile, line int) []sarifCodeFlow {
+	if file.ResolvedFrom == "" {
+		return nil
+	}
+	return []sarifCodeFlow{
+		{
+			ThreadFlows: []sarifThreadFlow{
+				{
+					Locations: []sarifThreadFlowLocation{
+						{
+							Location: sarifFlowLocation{
+								PhysicalLocation: sarifFlowPhysicalLocation{
+									ArtifactLocation: sarifArtifactLocation{ArtifactURI: file.ResolvedFrom},
+								},
+								Message: sarifMessage{Text: "Resolved from " + file.ResolvedFrom},
+							},
+						},
+						{
+							Location: sarifFlowLocation{
+								PhysicalLocation: sarifFlowPhysicalLocation{
+									ArtifactLocation: sarifArtifactLocation{ArtifactURI: file.FileName},
+									Region:           &sarifRegion{StartLine: line},
+								},
+								Message: sarifMessage{Text: file.KeyActualValue},
+							},
 						},
 					},
 				},
-			}
-			sr.Runs[0].Results = append(sr.Runs[0].Results, result)
+			},
+		},
+	}
+}
+
+// buildSarifFixes creates the fix of a result from its remediation, replacements need the line of the result
+// to locate the replaced value and additions use the indentation of the next line, as the auto remediation
+func buildSarifFixes(file *model.VulnerableFile) []sarifFix {
+	if file.Remediation == "" || file.Line < 1 {
+		return nil
+	}
+
+	var replacement sarifReplacement
+	switch file.RemediationType {
+	case "replacement":
+		var info struct {
+			Before string `json:"before"`
+			After  string `json:"after"`
+		}
+		content, ok := vulnerableLine(file, file.Line)
+		if json.Unmarshal([]byte(file.Remediation), &info) != nil || info.Before == "" || !ok {
+			return nil
+		}
+		column := strings.Index(content, info.Before)
+		if column < 0 {
+			return nil
+		}
+		startColumn := utf16Length(content[:column]) + 1
+		replacement = sarifReplacement{
+			DeletedRegion: sarifRegion{
+				StartLine:   file.Line,
+				StartColumn: startColumn,
+				EndColumn:   startColumn + utf16Length(info.Before),
+			},
+			InsertedContent: &sarifArtifactContent{Text: info.After},
+		}
+	case "addition":
+		// without the next line the indentation of the addition is unknown
+		next, ok := vulnerableLine(file, file.Line+1)
+		if !ok {
+			return nil
+		}
+		indentation := next[:len(next)-len(strings.TrimLeft(next, " \t"))]
+		replacement = sarifReplacement{
+			DeletedRegion:   sarifRegion{StartLine: file.Line + 1, StartColumn: 1, EndColumn: 1},
+			InsertedContent: &sarifArtifactContent{Text: indentation + file.Remediation + "\n"},
+		}
+	case "removal":
+		replacement = sarifReplacement{
+			DeletedRegion: sarifRegion{StartLine: file.Line, StartColumn: 1, EndLine: file.Line + 1, EndColumn: 1},
+		}
+	default:
+		return nil
+	}
+
+	return []sarifFix{
+		{
+			Description: sarifMessage{Text: file.KeyExpectedValue},
+			ArtifactChanges: []sarifArtifactChange{
+				{
+					ArtifactLocation: sarifArtifactLocation{ArtifactURI: file.FileName},
+					Replacements:     []sarifReplacement{replacement},
+				},
+			},
+		},
+	}
+}
+
+// vulnerableLine returns the content of a line among the lines kept with the result
+func vulnerableLine(file *model.VulnerableFile, line int) (string, bool) {
+	if file.VulnLines == nil {
+		return "", false
+	}
+	for _, codeLine := range *file.VulnLines {
+		if codeLine.Position == line {
+			return codeLine.Line, true
 		}
 	}
+	return "", false
+}
+
+// utf16Length returns the length of the text in UTF-16 code units, the default column kind of SARIF
+func utf16Length(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// buildSarifSuppressions creates the in source suppression of a result suppressed through a comment
+func buildSarifSuppressions(suppression *model.Suppression) []sarifSuppression {
+	if suppression == nil {
+		return nil
+	}
+	properties := map[string]interface{}{
+		"line": suppression.Line,
+	}
+	if suppression.Owner != "" {
+		properties["owner"] = suppression.Owner
+	}
+	if suppression.Expires != "" {
+		properties["expires"] = suppression.Expires
+	}
+	return []sarifSuppression{
+		{
+			Kind:          "inSource",
+			Status:        "accepted",
+			Justification: suppression.Reason,
+			Properties:    properties,
+		},
+	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Checkmarx/kics/internal/constants"
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/results"
 	"github.com/stretchr/testify/require"
 )
 
@@ -254,3 +255,134 @@ func TestBuildSarifIssue(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSarifIssueSuppressed(t *testing.T) {
+	result := NewSarifReport().(*sarifReport)
+	result.BuildSarifIssue(&model.QueryResult{
+		QueryName: "test",
+		QueryID:   "1",
+		Severity:  model.SeverityHigh,
+		Files: []model.VulnerableFile{
+			{FileName: "main.tf", Line: 4},
+			{
+				FileName: "main.tf",
+				Line:     8,
+				Suppression: &model.Suppression{
+					Command: model.IgnoreLine,
+					Line:    7,
+					Reason:  "public website",
+					Owner:   "@web-team",
+					Expires: "2027-01-01",
+				},
+			},
+		},
+	})
+	require.Len(t, result.Runs[0].Results, 2)
+	require.Empty(t, result.Runs[0].Results[0].ResultSuppressions)
+	require.Equal(t, []sarifSuppression{
+		{
+			Kind:          "inSource",
+			Status:        "accepted",
+			Justification: "public website",
+			Properties:    map[string]interface{}{"line": 7, "owner": "@web-team", "expires": "2027-01-01"},
+		},
+	}, result.Runs[0].Results[1].ResultSuppressions)
+}
+
+func TestBuildSarifIssueFidelity(t *testing.T) {
+	result := NewSarifReport().(*sarifReport)
+	result.BuildSarifIssue(&model.QueryResult{
+		QueryName: "Passwords And Secrets",
+		QueryID:   "1",
+		Severity:  model.SeverityHigh,
+		Category:  "Secret Management",
+		CWE:       "798",
+		Files: []model.VulnerableFile{
+			{
+				FileName:         "chart/templates/deployment.yaml",
+				SimilarityID:     "abc",
+				Line:             2,
+				VulnLines:        &[]model.CodeLine{{Position: 1, Line: "spec:"}, {Position: 2, Line: "  privileged: true"}},
+				KeyExpectedValue: "privileged should be false",
+				Remediation:      `{"before":"true","after":"false"}`,
+				RemediationType:  "replacement",
+				ResolvedFrom:     "chart/values.yaml",
+			},
+			{FileName: "main.tf", Line: 4, Remediation: "enabled = true", RemediationType: "removal"},
+		},
+	})
+
+	require.Len(t, result.Runs[0].Taxonomies, 2)
+	require.Equal(t, "798", result.Runs[0].Taxonomies[1].TaxonomyDefinitions[0].DefinitionID)
+	rule := result.Runs[0].Tool.Driver.Rules[0]
+	require.Len(t, rule.RuleRelationships, 2)
+	require.Equal(t, "798", rule.RuleRelationships[1].Target.ReferenceID)
+	require.Equal(t, []string{"external/cwe/cwe-798"}, rule.RuleProperties["tags"])
+
+	replaced := result.Runs[0].Results[0]
+	require.Equal(t, map[string]string{similarityFingerprint: "abc"}, replaced.ResultPartialFingerprints)
+	require.Equal(t, "chart/values.yaml", replaced.ResultRelatedLocations[0].PhysicalLocation.ArtifactLocation.ArtifactURI)
+	flow := replaced.ResultCodeFlows[0].ThreadFlows[0].Locations
+	require.Len(t, flow, 2)
+	require.Equal(t, "chart/values.yaml", flow[0].Location.PhysicalLocation.ArtifactLocation.ArtifactURI)
+	require.Equal(t, sarifFlowPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{ArtifactURI: "chart/templates/deployment.yaml"},
+		Region:           &sarifRegion{StartLine: 2},
+	}, flow[1].Location.PhysicalLocation)
+	require.Equal(t, sarifReplacement{
+		DeletedRegion:   sarifRegion{StartLine: 2, StartColumn: 15, EndColumn: 19},
+		InsertedContent: &sarifArtifactContent{Text: "false"},
+	}, replaced.ResultFixes[0].ArtifactChanges[0].Replacements[0])
+
+	removed := result.Runs[0].Results[1]
+	require.Empty(t, removed.ResultPartialFingerprints)
+	require.Empty(t, removed.ResultCodeFlows)
+	require.Equal(t, sarifRegion{StartLine: 4, StartColumn: 1, EndLine: 5, EndColumn: 1},
+		removed.ResultFixes[0].ArtifactChanges[0].Replacements[0].DeletedRegion)
+}
+
+func TestBuildSarifAdditionFix(t *testing.T) {
+	file := &model.VulnerableFile{
+		FileName:        "main.tf",
+		Line:            1,
+		VulnLines:       &[]model.CodeLine{{Position: 1, Line: "resource \"aws_s3_bucket\" \"b\" {"}, {Position: 2, Line: "  acl = \"private\""}},
+		Remediation:     "versioning {}",
+		RemediationType: "addition",
+	}
+	require.Equal(t, []sarifReplacement{
+		{
+			DeletedRegion:   sarifRegion{StartLine: 2, StartColumn: 1, EndColumn: 1},
+			InsertedContent: &sarifArtifactContent{Text: "  versioning {}\n"},
+		},
+	}, buildSarifFixes(file)[0].ArtifactChanges[0].Replacements)
+
+	// the next line is not among the lines of the result, so the indentation of the addition is unknown
+	file.Line = 2
+	require.Nil(t, buildSarifFixes(file))
+}
+
+func TestBuildSarifBaseline(t *testing.T) {
+	query := func(files ...model.VulnerableFile) *model.Summary {
+		return &model.Summary{Queries: model.QueryResultSlice{
+			{QueryName: "test", QueryID: "1", Severity: model.SeverityHigh, Files: files},
+		}}
+	}
+	baseline := query(
+		model.VulnerableFile{FileName: "main.tf", Line: 1, SimilarityID: "kept", SearchKey: "kept"},
+		model.VulnerableFile{FileName: "main.tf", Line: 9, SimilarityID: "fixed", SearchKey: "fixed"},
+	)
+	current := query(
+		model.VulnerableFile{FileName: "main.tf", Line: 3, SimilarityID: "kept", SearchKey: "kept"},
+		model.VulnerableFile{FileName: "main.tf", Line: 5, SimilarityID: "new", SearchKey: "new"},
+	)
+
+	result := NewSarifReport().(*sarifReport)
+	result.BuildSarifIssue(&current.Queries[0])
+	result.BuildSarifBaseline(results.Compare(baseline, current))
+
+	require.Len(t, result.Runs[0].Results, 3)
+	require.Equal(t, baselineStateUnchanged, result.Runs[0].Results[0].ResultBaselineState)
+	require.Equal(t, baselineStateNew, result.Runs[0].Results[1].ResultBaselineState)
+	require.Equal(t, baselineStateAbsent, result.Runs[0].Results[2].ResultBaselineState)
+	require.Equal(t, 9, result.Runs[0].Results[2].ResultLocations[0].PhysicalLocation.Region.StartLine)
+}
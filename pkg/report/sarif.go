@@ -3,16 +3,25 @@ package report
 import (
 	"strings"
 
+	"github.com/Checkmarx/kics/pkg/model"
 	reportModel "github.com/Checkmarx/kics/pkg/report/model"
+	"github.com/Checkmarx/kics/pkg/results"
 )
 
 // PrintSarifReport creates a report file on sarif format
 func PrintSarifReport(path, filename string, body interface{}) error {
+	return PrintSarifReportWithOptions(path, filename, body, &Options{})
+}
+
+// PrintSarifReportWithOptions creates a report file on sarif format, with the baseline state of the results
+// when the options have a baseline
+func PrintSarifReportWithOptions(path, filename string, body interface{}, options *Options) error {
 	if !strings.HasSuffix(filename, ".sarif") {
 		filename += ".sarif"
 	}
 	if body != "" {
-		summary, err := getSummary(body)
+		// the summary is kept as is when possible, the fixes of the replacements need the lines of the results
+		summary, err := templateSummary(body)
 		if err != nil {
 			return err
 		}
@@ -21,6 +30,16 @@ func PrintSarifReport(path, filename string, body interface{}) error {
 		for idx := range summary.Queries {
 			sarifReport.BuildSarifIssue(&summary.Queries[idx])
 		}
+		for idx := range summary.Suppressed {
+			sarifReport.BuildSarifIssue(&summary.Suppressed[idx])
+		}
+		if options != nil && options.SarifBaseline != nil {
+			baseline := options.SarifBaseline
+			sarifReport.BuildSarifBaseline(
+				results.Compare(baseline, &summary),
+				results.Compare(&model.Summary{Queries: baseline.Suppressed}, &model.Summary{Queries: summary.Suppressed}),
+			)
+		}
 		body = sarifReport
 	}
 
@@ -0,0 +1,297 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// TemplateFormatPrefix prefixes the report formats rendered from a user template, e.g. template:slack.json.tmpl
+const TemplateFormatPrefix = "template:"
+
+const (
+	templateExtension = ".tmpl"
+	unownedGroup      = "unowned"
+)
+
+// TemplateFinding is a vulnerable file with the query that found it
+type TemplateFinding struct {
+	Query *model.QueryResult
+	File  *model.VulnerableFile
+}
+
+// TemplateGroup is a set of findings sharing a file, a query or an owner, sorted from the highest severity
+type TemplateGroup struct {
+	Key      string
+	Severity model.Severity
+	Findings []TemplateFinding
+}
+
+// IsTemplateFormat returns true if the report format is rendered from a user template
+func IsTemplateFormat(format string) bool {
+	return strings.HasPrefix(strings.ToLower(format), TemplateFormatPrefix)
+}
+
+// ParseTemplateFormat parses the template of a template report format, with the template report functions
+func ParseTemplateFormat(format string) (*template.Template, error) {
+	templatePath := format[len(TemplateFormatPrefix):]
+	content, err := os.ReadFile(filepath.Clean(templatePath))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read report template %s", templatePath)
+	}
+
+	t, err := template.New(filepath.Base(templatePath)).Funcs(templateReportFuncs(&model.Summary{})).Parse(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse report template %s", templatePath)
+	}
+	return t, nil
+}
+
+// TemplateReportFilename returns the file name of a template report, the output name followed by the name
+// of the template and its extension, e.g. results-slack.json for the template slack.json.tmpl
+func TemplateReportFilename(filename, format string) string {
+	name := strings.TrimSuffix(filepath.Base(format[len(TemplateFormatPrefix):]), templateExtension)
+	ext := filepath.Ext(name)
+	if ext == "" {
+		ext = ".txt"
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + "-" + strings.TrimSuffix(name, ext) + ext
+}
+
+// PrintTemplateReport renders the template of the format against the summary of the scan
+func PrintTemplateReport(path, filename, format string, body interface{}) error {
+	t, err := ParseTemplateFormat(format)
+	if err != nil {
+		return err
+	}
+
+	summary, err := templateSummary(body)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err := t.Funcs(templateReportFuncs(&summary)).Execute(&buffer, &summary); err != nil {
+		return errors.Wrapf(err, "failed to render report template %s", t.Name())
+	}
+
+	filename = TemplateReportFilename(filename, format)
+	return exportTextReport(filepath.Join(path, filename), filename, buffer.String())
+}
+
+// templateSummary returns the summary of the body, keeping the lines of the vulnerable files the JSON
+// representation of the summary leaves out
+func templateSummary(body interface{}) (model.Summary, error) {
+	switch summary := body.(type) {
+	case model.Summary:
+		return summary, nil
+	case *model.Summary:
+		return *summary, nil
+	default:
+		return getSummary(body)
+	}
+}
+
+// templateReportFuncs returns the functions available to report templates, see docs/results.md
+func templateReportFuncs(summary *model.Summary) template.FuncMap {
+	return template.FuncMap{
+		"severities":   filterSeverities,
+		"minSeverity":  filterMinSeverity,
+		"findings":     templateFindings,
+		"groupByFile":  groupByFile,
+		"groupByQuery": groupByQuery,
+		"groupByOwner": groupByOwner,
+		"relPath": func(filePath string) string {
+			return relativeToScannedPaths(summary.ScannedPaths, filePath)
+		},
+		"snippet": snippet,
+		"lower":   strings.ToLower,
+		"upper":   strings.ToUpper,
+		"join":    strings.Join,
+		"replace": strings.ReplaceAll,
+		"trim":    strings.TrimSpace,
+		"json":    toJSON,
+		"csv":     toCSV,
+		"add": func(a, b int) int {
+			return a + b
+		},
+	}
+}
+
+func severityRank(severity model.Severity) int {
+	for i := range model.AllSeverities {
+		if strings.EqualFold(string(model.AllSeverities[i]), string(severity)) {
+			return i
+		}
+	}
+	return len(model.AllSeverities)
+}
+
+// filterSeverities returns the queries with one of the severities
+func filterSeverities(queries model.QueryResultSlice, severities ...string) model.QueryResultSlice {
+	filtered := make(model.QueryResultSlice, 0, len(queries))
+	for i := range queries {
+		for _, severity := range severities {
+			if strings.EqualFold(string(queries[i].Severity), severity) {
+				filtered = append(filtered, queries[i])
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterMinSeverity returns the queries with the severity or a higher one
+func filterMinSeverity(queries model.QueryResultSlice, severity string) model.QueryResultSlice {
+	minRank := severityRank(model.Severity(severity))
+	filtered := make(model.QueryResultSlice, 0, len(queries))
+	for i := range queries {
+		if severityRank(queries[i].Severity) <= minRank {
+			filtered = append(filtered, queries[i])
+		}
+	}
+	return filtered
+}
+
+// templateFindings returns the findings of the queries sorted by severity, file and line
+func templateFindings(queries model.QueryResultSlice) []TemplateFinding {
+	findings := make([]TemplateFinding, 0)
+	for i := range queries {
+		for j := range queries[i].Files {
+			findings = append(findings, TemplateFinding{Query: &queries[i], File: &queries[i].Files[j]})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if rankI, rankJ := severityRank(findings[i].Query.Severity), severityRank(findings[j].Query.Severity); rankI != rankJ {
+			return rankI < rankJ
+		}
+		if findings[i].File.FileName != findings[j].File.FileName {
+			return findings[i].File.FileName < findings[j].File.FileName
+		}
+		return findings[i].File.Line < findings[j].File.Line
+	})
+	return findings
+}
+
+// groupByFile returns the findings of the queries grouped by file, sorted by file name
+func groupByFile(queries model.QueryResultSlice) []TemplateGroup {
+	groups := groupFindings(templateFindings(queries), func(finding *TemplateFinding) []string {
+		return []string{finding.File.FileName}
+	})
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// groupByQuery returns the findings of the queries grouped by query name, sorted by severity and name
+func groupByQuery(queries model.QueryResultSlice) []TemplateGroup {
+	groups := groupFindings(templateFindings(queries), func(finding *TemplateFinding) []string {
+		return []string{finding.Query.QueryName}
+	})
+	sort.SliceStable(groups, func(i, j int) bool {
+		if rankI, rankJ := severityRank(groups[i].Severity), severityRank(groups[j].Severity); rankI != rankJ {
+			return rankI < rankJ
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// groupByOwner returns the findings of the queries grouped by CODEOWNERS owner, sorted by owner, a finding
+// with several owners is in the group of each of them and the findings without owner are in the unowned group
+func groupByOwner(queries model.QueryResultSlice) []TemplateGroup {
+	groups := groupFindings(templateFindings(queries), func(finding *TemplateFinding) []string {
+		if len(finding.File.Owners) == 0 {
+			return []string{unownedGroup}
+		}
+		return finding.File.Owners
+	})
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Key == unownedGroup) != (groups[j].Key == unownedGroup) {
+			return groups[j].Key == unownedGroup
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// groupFindings groups the findings by their keys, a finding is added to the group of each of its keys
+func groupFindings(findings []TemplateFinding, keys func(*TemplateFinding) []string) []TemplateGroup {
+	groups := make([]TemplateGroup, 0)
+	indexes := make(map[string]int)
+	for i := range findings {
+		for _, k := range keys(&findings[i]) {
+			idx, ok := indexes[k]
+			if !ok {
+				idx = len(groups)
+				indexes[k] = idx
+				groups = append(groups, TemplateGroup{Key: k, Severity: findings[i].Query.Severity})
+			}
+			// findings are sorted by severity, the first one of a group has its highest severity
+			groups[idx].Findings = append(groups[idx].Findings, findings[i])
+		}
+	}
+	return groups
+}
+
+// relativeToScannedPaths returns the path relative to the scanned path containing it
+func relativeToScannedPaths(scannedPaths []string, filePath string) string {
+	for _, scannedPath := range scannedPaths {
+		base := scannedPath
+		if info, err := os.Stat(scannedPath); err == nil && !info.IsDir() {
+			base = filepath.Dir(scannedPath)
+		}
+		rel, err := filepath.Rel(base, filePath)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filePath)
+}
+
+// snippet returns the lines of the vulnerable file around the result, each prefixed by its number
+func snippet(file model.VulnerableFile) string {
+	if file.VulnLines == nil {
+		return ""
+	}
+	lines := make([]string, 0, len(*file.VulnLines))
+	for _, line := range *file.VulnLines {
+		lines = append(lines, fmt.Sprintf("%d: %s", line.Position, line.Line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toJSON returns the value as JSON, a quoted string for strings
+func toJSON(value interface{}) (string, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// toCSV returns the values as a CSV record, without the line break
+func toCSV(values ...interface{}) (string, error) {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		record = append(record, toString(value))
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(record); err != nil {
+		return "", err
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n"), writer.Error()
+}
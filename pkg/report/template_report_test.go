@@ -0,0 +1,113 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTemplateReport(t *testing.T) {
+	summary := model.Summary{
+		SeveritySummary: model.SeveritySummary{ScanID: "console", TotalCounter: 3},
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "ALB protocol is HTTP",
+				Severity:  model.SeverityHigh,
+				Files: []model.VulnerableFile{
+					{FileName: "positive.tf", Line: 25, Owners: []string{"@org/security", "@org/infra"}},
+					{FileName: "positive.tf", Line: 19},
+				},
+			},
+			{
+				QueryName: "AmazonMQ Broker Encryption Disabled",
+				Severity:  model.SeverityMedium,
+				Files: []model.VulnerableFile{
+					{
+						FileName:  "positive.tf",
+						Line:      1,
+						VulnLines: &[]model.CodeLine{{Position: 1, Line: `resource "aws_mq_broker" "positive1" {`}},
+					},
+				},
+			},
+			{
+				QueryName: "Resource Not Using Tags",
+				Severity:  model.SeverityInfo,
+				Files:     []model.VulnerableFile{{FileName: "negative.tf", Line: 1}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		file     string
+		want     string
+	}{
+		{
+			name:     "should render queries filtered by severity",
+			template: `{{range severities .Queries "medium"}}{{.QueryName}}{{end}}`,
+			file:     "medium.txt.tmpl",
+			want:     "AmazonMQ Broker Encryption Disabled",
+		},
+		{
+			name:     "should render findings grouped by file",
+			template: `{{range groupByFile .Queries}}{{.Key}} {{.Severity}} {{len .Findings}}{{end}}`,
+			file:     "files.tmpl",
+			want:     "negative.tf INFO 1positive.tf HIGH 3",
+		},
+		{
+			name:     "should render findings grouped by query with snippets",
+			template: `{{range groupByQuery (minSeverity .Queries "MEDIUM")}}{{.Key}};{{range .Findings}}{{snippet .File}};{{end}}{{end}}`,
+			file:     "queries.md.tmpl",
+			want:     "ALB protocol is HTTP;;;AmazonMQ Broker Encryption Disabled;1: resource \"aws_mq_broker\" \"positive1\" {;",
+		},
+		{
+			name:     "should render findings grouped by owner",
+			template: `{{range groupByOwner .Queries}}{{.Key}} {{len .Findings}};{{end}}`,
+			file:     "owners.tmpl",
+			want:     "@org/infra 1;@org/security 1;unowned 3;",
+		},
+		{
+			name:     "should render json and csv values",
+			template: `{{json .ScanID}},{{csv "a,b" 1}}`,
+			file:     "values.json.tmpl",
+			want:     `"console","a,b",1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			templatePath := filepath.Join(dir, tt.file)
+			require.NoError(t, os.WriteFile(templatePath, []byte(tt.template), os.ModePerm))
+
+			format := TemplateFormatPrefix + templatePath
+			require.NoError(t, PrintTemplateReport(dir, "results", format, summary))
+
+			content, err := os.ReadFile(filepath.Join(dir, TemplateReportFilename("results", format)))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(content))
+		})
+	}
+}
+
+func TestTemplateReportFilename(t *testing.T) {
+	require.Equal(t, "results-slack.json", TemplateReportFilename("results", "template:reports/slack.json.tmpl"))
+	require.Equal(t, "results-report.txt", TemplateReportFilename("results", "template:report.tmpl"))
+	require.Equal(t, "output-jira.csv", TemplateReportFilename("output.json", "template:jira.csv"))
+}
+
+func TestParseTemplateFormat(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "invalid.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("{{range .Queries}}"), os.ModePerm))
+
+	_, err := ParseTemplateFormat(TemplateFormatPrefix + templatePath)
+	require.Error(t, err)
+
+	_, err = ParseTemplateFormat(TemplateFormatPrefix + filepath.Join(dir, "missing.tmpl"))
+	require.Error(t, err)
+}
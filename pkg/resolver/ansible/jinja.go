@@ -0,0 +1,277 @@
+package ansible
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxRenderDepth = 10
+)
+
+var (
+	jinjaExpressionRgx = regexp.MustCompile(`{{\s*(.*?)\s*}}`)
+	jinjaReferenceRgx  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*((\.[A-Za-z_][A-Za-z0-9_]*)|(\[\s*['"][^'"]+['"]\s*\])|(\[\d+\]))*$`)
+	jinjaPathRgx       = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*|\[\s*['"]([^'"]+)['"]\s*\]|\[(\d+)\]`)
+	jinjaFilterRgx     = regexp.MustCompile(`^([A-Za-z_]+)\s*(?:\((.*)\))?$`)
+)
+
+// scope keeps the variables visible at a given point of the project, ordered from the lowest to the highest precedence
+type scope []map[string]interface{}
+
+// with returns a new scope with the given variables on top of the current ones
+func (s scope) with(vars map[string]interface{}) scope {
+	if len(vars) == 0 {
+		return s
+	}
+	newScope := make(scope, 0, len(s)+1)
+	newScope = append(newScope, s...)
+	return append(newScope, vars)
+}
+
+// lookup returns the value of the variable with the highest precedence
+func (s scope) lookup(name string) (interface{}, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if value, ok := s[i][name]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// render replaces every Jinja2 expression of the line that can be evaluated with its value,
+// expressions that can not be evaluated are kept untouched
+func (s scope) render(line string) string {
+	return s.renderDepth(line, 0)
+}
+
+func (s scope) renderDepth(line string, depth int) string {
+	if depth > maxRenderDepth || !strings.Contains(line, "{{") {
+		return line
+	}
+	return jinjaExpressionRgx.ReplaceAllStringFunc(line, func(expression string) string {
+		inner := jinjaExpressionRgx.FindStringSubmatch(expression)[1]
+		value, ok := s.evaluate(inner, depth)
+		if !ok {
+			return expression
+		}
+		rendered, ok := stringify(value)
+		if !ok {
+			return expression
+		}
+		return rendered
+	})
+}
+
+// evaluate computes a simple Jinja2 expression: a variable reference followed by optional filters
+func (s scope) evaluate(expression string, depth int) (interface{}, bool) {
+	parts := splitFilters(expression)
+	value, defined := s.resolveReference(strings.TrimSpace(parts[0]), depth)
+	for _, filter := range parts[1:] {
+		var ok bool
+		value, defined, ok = s.applyFilter(strings.TrimSpace(filter), value, defined, depth)
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, defined
+}
+
+// resolveReference returns the value of a variable reference such as 'var', 'var.attr' or "var['key']"
+func (s scope) resolveReference(reference string, depth int) (interface{}, bool) {
+	if literal, ok := parseLiteral(reference); ok {
+		return literal, true
+	}
+	if !jinjaReferenceRgx.MatchString(reference) {
+		return nil, false
+	}
+	path := jinjaPathRgx.FindAllStringSubmatch(reference, -1)
+	value, ok := s.lookup(path[0][0])
+	if !ok {
+		return nil, false
+	}
+	value = s.expand(value, depth)
+	for _, step := range path[1:] {
+		value, ok = index(value, step)
+		if !ok {
+			return nil, false
+		}
+		value = s.expand(value, depth)
+	}
+	return value, true
+}
+
+// expand renders variables whose values are themselves templates
+func (s scope) expand(value interface{}, depth int) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	trimmed := strings.TrimSpace(str)
+	if match := jinjaExpressionRgx.FindStringSubmatch(trimmed); match != nil && match[0] == trimmed {
+		if evaluated, ok := s.evaluate(match[1], depth+1); ok {
+			return evaluated
+		}
+	}
+	return s.renderDepth(str, depth+1)
+}
+
+// applyFilter applies the supported Jinja2 filters, the last return value is false when the filter is not supported
+func (s scope) applyFilter(filter string, value interface{}, defined bool, depth int) (result interface{}, isDefined, ok bool) {
+	match := jinjaFilterRgx.FindStringSubmatch(filter)
+	if match == nil {
+		return nil, false, false
+	}
+	name, args := match[1], strings.TrimSpace(match[2])
+	switch name {
+	case "default", "d":
+		if defined {
+			return value, true, true
+		}
+		defaultArgs := strings.SplitN(args, ",", 2)
+		defaultValue, ok := s.resolveReference(strings.TrimSpace(defaultArgs[0]), depth)
+		return defaultValue, ok, ok
+	}
+	if !defined {
+		return nil, false, true
+	}
+	switch name {
+	case "string":
+		str, ok := stringify(value)
+		return str, ok, ok
+	case "lower", "upper", "trim":
+		str, ok := stringify(value)
+		if !ok {
+			return nil, false, false
+		}
+		return map[string]func(string) string{
+			"lower": strings.ToLower,
+			"upper": strings.ToUpper,
+			"trim":  strings.TrimSpace,
+		}[name](str), true, true
+	case "bool":
+		str, ok := stringify(value)
+		if !ok {
+			return nil, false, false
+		}
+		switch strings.ToLower(str) {
+		case "true", "yes", "on", "1", "y":
+			return true, true, true
+		default:
+			return false, true, true
+		}
+	case "int":
+		str, ok := stringify(value)
+		if !ok {
+			return nil, false, false
+		}
+		number, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return 0, true, true
+		}
+		return int(number), true, true
+	}
+	return nil, false, false
+}
+
+// splitFilters splits an expression by the filter pipes that are not inside quotes or parentheses
+func splitFilters(expression string) []string {
+	parts := make([]string, 0)
+	var quote rune
+	level := 0
+	last := 0
+	for i, char := range expression {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == '(':
+			level++
+		case char == ')':
+			level--
+		case char == '|' && level == 0:
+			parts = append(parts, expression[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, expression[last:])
+}
+
+// parseLiteral parses quoted strings, numbers and booleans
+func parseLiteral(expression string) (interface{}, bool) {
+	if len(expression) >= 2 {
+		first, last := expression[0], expression[len(expression)-1]
+		if (first == '\'' || first == '"') && first == last {
+			return expression[1 : len(expression)-1], true
+		}
+	}
+	switch expression {
+	case "true", "True":
+		return true, true
+	case "false", "False":
+		return false, true
+	}
+	if number, err := strconv.ParseFloat(expression, 64); err == nil {
+		return number, true
+	}
+	return nil, false
+}
+
+// index returns the value of an attribute, key or position of a value
+func index(value interface{}, step []string) (interface{}, bool) {
+	if str, ok := value.(string); ok {
+		// complex values from the inventory are kept as JSON strings
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+			return nil, false
+		}
+		value = decoded
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		key := step[0]
+		if step[1] != "" {
+			key = step[1]
+		}
+		result, ok := typed[key]
+		return result, ok
+	case []interface{}:
+		if step[2] == "" {
+			return nil, false
+		}
+		position, err := strconv.Atoi(step[2])
+		if err != nil || position >= len(typed) {
+			return nil, false
+		}
+		return typed[position], true
+	}
+	return nil, false
+}
+
+// stringify converts a scalar value to the text that will replace the expression, values that could
+// change the structure of the YAML line (collections, quotes or new lines) are not converted
+func stringify(value interface{}) (string, bool) {
+	var str string
+	switch typed := value.(type) {
+	case string:
+		str = typed
+	case bool:
+		str = strconv.FormatBool(typed)
+	case int, int64, uint64:
+		str = fmt.Sprint(typed)
+	case float64:
+		str = strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return "", false
+	}
+	if strings.ContainsAny(str, "\n\r\"'") {
+		return "", false
+	}
+	return str, true
+}
@@ -0,0 +1,51 @@
+package ansible
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope_Render(t *testing.T) {
+	s := scope{
+		{
+			"name":    "low",
+			"enabled": true,
+			"port":    8080,
+			"server":  map[string]interface{}{"host": "example.com"},
+			"ports":   []interface{}{80, 443},
+			"json":    `{"key": "value"}`,
+		},
+		{
+			"name":   "high",
+			"nested": "{{ name }}-suffix",
+		},
+	}
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "no_expression", line: "state: present", want: "state: present"},
+		{name: "precedence", line: `name: "{{ name }}"`, want: `name: "high"`},
+		{name: "boolean", line: `enabled: "{{enabled}}"`, want: `enabled: "true"`},
+		{name: "number", line: `url: "http://host:{{ port }}/"`, want: `url: "http://host:8080/"`},
+		{name: "attribute", line: `host: "{{ server.host }}"`, want: `host: "example.com"`},
+		{name: "key", line: `host: "{{ server['host'] }}"`, want: `host: "example.com"`},
+		{name: "index", line: `port: "{{ ports[1] }}"`, want: `port: "443"`},
+		{name: "json_string", line: `key: "{{ json.key }}"`, want: `key: "value"`},
+		{name: "nested_template", line: `value: "{{ nested }}"`, want: `value: "high-suffix"`},
+		{name: "default_filter", line: `value: "{{ missing | default('x') }}"`, want: `value: "x"`},
+		{name: "default_defined", line: `value: "{{ name | d('x') | upper }}"`, want: `value: "HIGH"`},
+		{name: "bool_filter", line: `value: "{{ 'yes' | bool }}"`, want: `value: "true"`},
+		{name: "undefined", line: `value: "{{ missing }}"`, want: `value: "{{ missing }}"`},
+		{name: "unsupported_filter", line: `value: "{{ name | to_json }}"`, want: `value: "{{ name | to_json }}"`},
+		{name: "collection", line: `value: "{{ server }}"`, want: `value: "{{ server }}"`},
+		{name: "expression", line: `value: "{{ port + 1 }}"`, want: `value: "{{ port + 1 }}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, s.render(tt.line))
+		})
+	}
+}
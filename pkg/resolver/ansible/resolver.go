@@ -0,0 +1,655 @@
+// Package ansible implements the resolver of Ansible projects, it inlines the roles and included tasks in the
+// playbooks and evaluates simple Jinja2 variable references using the inventory, group_vars, host_vars and role variables
+package ansible
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	masterUtils "github.com/Checkmarx/kics/pkg/utils"
+	"github.com/relex/aini"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	builtinPrefix  = "ansible.builtin."
+	configFileName = "ansible.cfg"
+	defaultTasks   = "main"
+)
+
+var (
+	playbookDirs  = []string{"", "playbooks"}
+	projectDirs   = []string{"roles", "group_vars", "host_vars"}
+	taskListKeys  = []string{"pre_tasks", "tasks", "post_tasks", "handlers"}
+	blockKeys     = []string{"block", "rescue", "always"}
+	roleEntryKeys = map[string]bool{"role": true, "name": true, "vars": true, "tags": true, "when": true}
+)
+
+// Resolver is an instance of the ansible resolver
+type Resolver struct {
+}
+
+// project keeps the information of the ansible project being resolved
+type project struct {
+	root      string
+	rolesPath []string
+	inventory *aini.InventoryData
+	files     map[string]*taskFile
+	included  map[string]bool
+}
+
+// taskFile keeps the original lines and the parsed content of a playbook or tasks file
+type taskFile struct {
+	original []string
+	node     *yaml.Node
+}
+
+// sourceLine is a line of a resolved playbook with the file and line it was inlined from
+type sourceLine struct {
+	rendered string
+	original string
+	source   model.SourceLine
+}
+
+// expansion keeps the rendered lines of a file and the lines of the included files inlined after its lines,
+// a file included several times gets an expansion for each include with the variables of the include site
+type expansion struct {
+	path     string
+	file     *taskFile
+	rendered []string
+	inserts  map[int][]sourceLine
+}
+
+// playState keeps the roles already resolved in a play and the handlers of its roles
+type playState struct {
+	roles    map[string]bool
+	handlers []sourceLine
+}
+
+// includeContext keeps the directories used to look for included files and the files being inlined
+type includeContext struct {
+	playDir string
+	dirs    []string
+	chain   []string
+	play    *playState
+}
+
+// Resolve will expand the playbooks of the ansible project, inlining the tasks files and roles they include,
+// and return the playbooks with the variable references rendered
+func (r *Resolver) Resolve(filePath string) (model.ResolvedFiles, error) {
+	// handle panic during resolve process
+	defer func() {
+		if r := recover(); r != nil {
+			errMessage := "Recovered from panic during resolve of file " + filePath
+			masterUtils.HandlePanic(r, errMessage)
+		}
+	}()
+	playbooks := findPlaybooks(filePath)
+	if len(playbooks) == 0 {
+		// without playbooks the directory is not an ansible project and its files are scanned as they are
+		log.Debug().Msgf("No playbooks found in %s", filePath)
+		return model.ResolvedFiles{}, nil
+	}
+	p := newProject(filePath)
+
+	rfiles := model.ResolvedFiles{}
+	resolved := make(map[string]bool)
+	for len(playbooks) > 0 {
+		path := filepath.Clean(playbooks[0])
+		playbooks = playbooks[1:]
+		if resolved[path] {
+			continue
+		}
+		resolved[path] = true
+		lines, imported, ok := p.resolvePlaybook(path)
+		if !ok {
+			continue
+		}
+		playbooks = append(playbooks, imported...)
+		rfiles.File = append(rfiles.File, newResolvedFile(path, lines))
+		rfiles.Excluded = append(rfiles.Excluded, path)
+	}
+	included := make([]string, 0, len(p.included))
+	for path := range p.included {
+		if !resolved[path] {
+			included = append(included, path)
+		}
+	}
+	sort.Strings(included)
+	rfiles.Excluded = append(rfiles.Excluded, included...)
+	return rfiles, nil
+}
+
+// SupportedTypes returns the supported fileKinds for this resolver
+func (r *Resolver) SupportedTypes() []model.FileKind {
+	return []model.FileKind{model.KindANSIBLE}
+}
+
+func newProject(root string) *project {
+	cfg := loadConfig(root)
+	return &project{
+		root:      root,
+		rolesPath: cfg.rolesPath,
+		inventory: loadInventory(root, cfg),
+		files:     make(map[string]*taskFile),
+		included:  make(map[string]bool),
+	}
+}
+
+func newResolvedFile(path string, lines []sourceLine) model.ResolvedHelm {
+	rendered := make([]string, len(lines))
+	original := make([]string, len(lines))
+	sources := make([]model.SourceLine, len(lines))
+	for i := range lines {
+		rendered[i] = lines[i].rendered
+		original[i] = lines[i].original
+		sources[i] = lines[i].source
+	}
+	return model.ResolvedHelm{
+		FileName:     path,
+		Content:      []byte(strings.Join(rendered, "\n")),
+		OriginalData: []byte(strings.Join(original, "\n")),
+		SourceLines:  sources,
+	}
+}
+
+// IsProject checks if the directory is the root of an ansible project, it has an ansible.cfg file or one of the
+// roles, group_vars and host_vars directories along with a playbook, as these directories are common in other projects
+func IsProject(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, configFileName)); err == nil {
+		return true
+	}
+	for _, marker := range projectDirs {
+		if info, err := os.Stat(filepath.Join(dir, marker)); err == nil && info.IsDir() {
+			return len(findPlaybooks(dir)) > 0
+		}
+	}
+	return false
+}
+
+// findPlaybooks returns the playbooks at the project root and at its playbooks directory
+func findPlaybooks(root string) []string {
+	playbooks := make([]string, 0)
+	for _, dir := range playbookDirs {
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(root, dir, entry.Name())
+			if !entry.IsDir() && isYAML(path) && isPlaybook(path) {
+				playbooks = append(playbooks, path)
+			}
+		}
+	}
+	sort.Strings(playbooks)
+	return playbooks
+}
+
+// isPlaybook checks if the file is a list of plays
+func isPlaybook(path string) bool {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	var plays []map[string]interface{}
+	if err := yaml.Unmarshal(content, &plays); err != nil {
+		return false
+	}
+	for _, play := range plays {
+		if _, ok := play["hosts"]; ok {
+			return true
+		}
+		if importPlaybook(play) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func importPlaybook(play map[string]interface{}) interface{} {
+	if imported, ok := play["import_playbook"]; ok {
+		return imported
+	}
+	return play[builtinPrefix+"import_playbook"]
+}
+
+// load reads and parses a file, files are read only once and expanded for each playbook or task including them
+func (p *project) load(path string) (*taskFile, bool) {
+	path = filepath.Clean(path)
+	if file, ok := p.files[path]; ok {
+		return file, file != nil
+	}
+	p.files[path] = nil
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Debug().Msgf("failed to read ansible file %s: %s", path, err)
+		return nil, false
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		log.Debug().Msgf("failed to parse ansible file %s: %s", path, err)
+		return nil, false
+	}
+	file := &taskFile{
+		original: strings.Split(strings.ReplaceAll(string(content), "\r", ""), "\n"),
+		node:     &node,
+	}
+	p.files[path] = file
+	return file, true
+}
+
+func (f *taskFile) sequence() *yaml.Node {
+	if len(f.node.Content) == 0 || f.node.Content[0].Kind != yaml.SequenceNode || len(f.node.Content[0].Content) == 0 {
+		return nil
+	}
+	return f.node.Content[0]
+}
+
+func newExpansion(path string, file *taskFile) *expansion {
+	return &expansion{
+		path:     path,
+		file:     file,
+		rendered: append([]string{}, file.original...),
+		inserts:  make(map[int][]sourceLine),
+	}
+}
+
+// render renders the lines in the range [start, end] of the file, lines are always rendered from the
+// original content so inner ranges rendered later take precedence
+func (e *expansion) render(start, end int, s scope) {
+	for line := start - 1; line < end && line < len(e.file.original); line++ {
+		if line >= 0 {
+			e.rendered[line] = s.render(e.file.original[line])
+		}
+	}
+}
+
+// insert inlines the lines after the line of the file, indenting them
+func (e *expansion) insert(after, indent int, lines []sourceLine) {
+	prefix := strings.Repeat(" ", indent)
+	for _, line := range lines {
+		if strings.TrimSpace(line.original) != "" {
+			line.rendered = prefix + line.rendered
+			line.original = prefix + line.original
+			line.source.Indent += indent
+		}
+		e.inserts[after] = append(e.inserts[after], line)
+	}
+}
+
+// lines returns the lines in the range [start, end] of the file with the lines inlined in between
+func (e *expansion) lines(start, end int) []sourceLine {
+	lines := append([]sourceLine{}, e.inserts[start-1]...)
+	for line := start; line <= end && line <= len(e.file.original); line++ {
+		lines = append(lines, sourceLine{
+			rendered: e.rendered[line-1],
+			original: e.file.original[line-1],
+			source:   model.SourceLine{FilePath: e.path, Line: line},
+		})
+		lines = append(lines, e.inserts[line]...)
+	}
+	return lines
+}
+
+// syntheticLine is a line added by the resolver, it is mapped to the line of the file that caused it
+func (e *expansion) syntheticLine(text string, line int) sourceLine {
+	return sourceLine{rendered: text, original: text, source: model.SourceLine{FilePath: e.path, Line: line}}
+}
+
+// resolvePlaybook renders every play of the playbook with its variables and inlines its roles and included
+// tasks, it returns the playbooks imported by the plays
+func (p *project) resolvePlaybook(path string) (lines []sourceLine, imported []string, ok bool) {
+	file, ok := p.load(path)
+	if !ok {
+		return nil, nil, false
+	}
+	plays := file.sequence()
+	if plays == nil {
+		return nil, nil, false
+	}
+	e := newExpansion(path, file)
+	playDir := filepath.Dir(path)
+	forEachItem(file.original, plays, len(file.original), func(play *yaml.Node, start, end int) {
+		playScope := scope{inventoryVars(p.inventory, scalar(mapValue(play, "hosts")))}
+		if varsFiles := mapValue(play, "vars_files"); varsFiles != nil {
+			for _, varsFile := range varsFiles.Content {
+				name := playScope.render(varsFile.Value)
+				playScope = playScope.with(loadVarsFile(filepath.Join(playDir, name)))
+			}
+		}
+		playScope = playScope.with(nodeVars(mapValue(play, "vars")))
+		e.render(start, end, playScope)
+
+		for _, key := range []string{"import_playbook", builtinPrefix + "import_playbook"} {
+			if name := scalar(mapValue(play, key)); name != "" {
+				imported = append(imported, filepath.Join(playDir, playScope.render(name)))
+			}
+		}
+
+		ctx := includeContext{
+			playDir: playDir,
+			dirs:    []string{playDir},
+			chain:   []string{path},
+			play:    &playState{roles: make(map[string]bool)},
+		}
+		roleTasks := make([]sourceLine, 0)
+		if roles := mapValue(play, "roles"); roles != nil && roles.Kind == yaml.SequenceNode {
+			for _, role := range roles.Content {
+				name, params := roleEntry(role)
+				tasks := p.expandRole(ctx, name, defaultTasks, playScope, params, true)
+				if len(tasks) == 0 {
+					continue
+				}
+				// the tasks of each role are inlined as a block named after the role
+				roleTasks = append(roleTasks, e.syntheticLine(fmt.Sprintf("- name: %q", name), role.Line),
+					e.syntheticLine("  block:", role.Line))
+				for _, task := range tasks {
+					if strings.TrimSpace(task.original) != "" {
+						task.rendered = "    " + task.rendered
+						task.original = "    " + task.original
+						task.source.Indent += 4
+					}
+					roleTasks = append(roleTasks, task)
+				}
+			}
+		}
+		for _, key := range taskListKeys {
+			if tasks := mapValue(play, key); tasks != nil && tasks.Kind == yaml.SequenceNode {
+				p.expandTasks(e, tasks, end, playScope, ctx)
+			}
+		}
+		// roles run before the tasks of the play and their handlers are added to the handlers of the play
+		e.insertTaskList(play, "tasks", end, true, roleTasks)
+		e.insertTaskList(play, "handlers", end, false, ctx.play.handlers)
+	})
+	return e.lines(1, len(file.original)), imported, true
+}
+
+// insertTaskList inlines tasks at the start or at the end of a task list of the play, adding the task list
+// after the play when it has none
+func (e *expansion) insertTaskList(play *yaml.Node, key string, playEnd int, atStart bool, tasks []sourceLine) {
+	if len(tasks) == 0 {
+		return
+	}
+	list := mapValue(play, key)
+	if list == nil {
+		keyIndent := play.Column - 1
+		e.inserts[playEnd] = append(e.inserts[playEnd], e.syntheticLine(strings.Repeat(" ", keyIndent)+key+":", play.Line))
+		e.insert(playEnd, keyIndent+2, tasks)
+		return
+	}
+	if list.Kind != yaml.SequenceNode || len(list.Content) == 0 || list.Style == yaml.FlowStyle {
+		log.Debug().Msgf("failed to inline ansible roles in %s of the play at %s:%d", key, e.path, play.Line)
+		return
+	}
+	first := list.Content[0]
+	indent := indentation(e.file.original[first.Line-1])
+	if atStart {
+		e.insert(first.Line-1, indent, tasks)
+		return
+	}
+	last := list.Content[len(list.Content)-1]
+	e.insert(itemEnd(e.file.original, last, playEnd), indent, tasks)
+}
+
+// expandTaskFile renders a tasks file with the variables of the include site and inlines the files it includes,
+// it returns the lines of its task list
+func (p *project) expandTaskFile(path string, s scope, ctx includeContext) []sourceLine {
+	path = filepath.Clean(path)
+	for _, including := range ctx.chain {
+		if including == path {
+			log.Debug().Msgf("ansible file %s includes itself", path)
+			return nil
+		}
+	}
+	file, ok := p.load(path)
+	if !ok {
+		return nil
+	}
+	tasks := file.sequence()
+	if tasks == nil {
+		return nil
+	}
+	p.included[path] = true
+	ctx.dirs = append([]string{filepath.Dir(path)}, ctx.dirs...)
+	ctx.chain = append([]string{path}, ctx.chain...)
+
+	e := newExpansion(path, file)
+	start := tasks.Content[0].Line
+	end := itemEnd(file.original, tasks.Content[len(tasks.Content)-1], len(file.original))
+	e.render(start, end, s)
+	p.expandTasks(e, tasks, end, s, ctx)
+	return e.lines(start, end)
+}
+
+// expandTasks renders each task of the list with its own variables and inlines the included tasks and roles
+// as a block of the including task
+func (p *project) expandTasks(e *expansion, tasks *yaml.Node, end int, s scope, ctx includeContext) {
+	forEachItem(e.file.original, tasks, end, func(task *yaml.Node, start, taskEnd int) {
+		taskScope := s.with(nodeVars(mapValue(task, "vars")))
+		e.render(start, taskEnd, taskScope)
+		if included := p.expandIncludes(task, taskScope, ctx); len(included) > 0 {
+			indent := task.Column - 1
+			e.inserts[taskEnd] = append(e.inserts[taskEnd], e.syntheticLine(strings.Repeat(" ", indent)+"block:", task.Line))
+			e.insert(taskEnd, indent+2, included)
+		}
+		for _, key := range blockKeys {
+			if block := mapValue(task, key); block != nil && block.Kind == yaml.SequenceNode {
+				p.expandTasks(e, block, taskEnd, taskScope, ctx)
+			}
+		}
+	})
+}
+
+// expandIncludes returns the tasks of the tasks files and roles included or imported by a task
+func (p *project) expandIncludes(task *yaml.Node, s scope, ctx includeContext) []sourceLine {
+	lines := make([]sourceLine, 0)
+	for i := 0; i+1 < len(task.Content); i += 2 {
+		value := task.Content[i+1]
+		switch strings.TrimPrefix(task.Content[i].Value, builtinPrefix) {
+		case "include_tasks", "import_tasks", "include":
+			name := scalar(value)
+			if value.Kind == yaml.MappingNode {
+				name = scalar(mapValue(value, "file"))
+			}
+			if path, ok := findInclude(s.render(name), ctx.dirs); ok {
+				lines = append(lines, p.expandTaskFile(path, s, ctx)...)
+			}
+		case "include_role", "import_role":
+			tasksFrom := scalar(mapValue(value, "tasks_from"))
+			if tasksFrom == "" {
+				tasksFrom = defaultTasks
+			}
+			lines = append(lines, p.expandRole(ctx, scalar(mapValue(value, "name")), s.render(tasksFrom), s, nil, false)...)
+		}
+	}
+	return lines
+}
+
+// expandRole returns the tasks of the dependencies of a role followed by its own tasks, adding its handlers to the
+// play, role defaults have the lowest precedence while role vars and role parameters override the variables of the
+// play. Roles of the play and role dependencies run once per play, included roles run at every include
+func (p *project) expandRole(ctx includeContext, name, tasksFrom string, s scope, params map[string]interface{},
+	once bool) []sourceLine {
+	roleDir, ok := p.findRole(ctx.playDir, s.render(name))
+	if !ok {
+		log.Debug().Msgf("failed to find ansible role %s", name)
+		return nil
+	}
+	if once && ctx.play.roles[roleDir+":"+tasksFrom] {
+		return nil
+	}
+	ctx.play.roles[roleDir+":"+tasksFrom] = true
+
+	roleScope := scope{loadRoleVars(roleDir, "defaults")}
+	roleScope = append(roleScope, s...)
+	roleScope = roleScope.with(loadRoleVars(roleDir, "vars")).with(params)
+
+	lines := make([]sourceLine, 0)
+	if meta, ok := findYAML(filepath.Join(roleDir, "meta"), defaultTasks); ok {
+		for _, dependency := range roleDependencies(meta) {
+			depName, depParams := roleEntry(dependency)
+			lines = append(lines, p.expandRole(ctx, depName, defaultTasks, s, depParams, true)...)
+		}
+	}
+
+	roleCtx := ctx
+	roleCtx.dirs = []string{filepath.Join(roleDir, "tasks"), roleDir}
+	if path, ok := findYAML(filepath.Join(roleDir, "tasks"), tasksFrom); ok {
+		lines = append(lines, p.expandTaskFile(path, roleScope, roleCtx)...)
+	}
+	if path, ok := findYAML(filepath.Join(roleDir, "handlers"), defaultTasks); ok && !p.handlersAdded(ctx, path) {
+		ctx.play.handlers = append(ctx.play.handlers, p.expandTaskFile(path, roleScope, roleCtx)...)
+	}
+	return lines
+}
+
+// handlersAdded returns true when the handlers file was already added to the handlers of the play
+func (p *project) handlersAdded(ctx includeContext, path string) bool {
+	key := "handlers:" + filepath.Clean(path)
+	added := ctx.play.roles[key]
+	ctx.play.roles[key] = true
+	return added
+}
+
+// findRole looks for the role in the same order Ansible does: the roles directory next to the playbook,
+// the roles_path of ansible.cfg, the project roles directory and the playbook directory
+func (p *project) findRole(playDir, name string) (string, bool) {
+	if name == "" || strings.Contains(name, "{{") {
+		return "", false
+	}
+	candidates := []string{filepath.Join(playDir, "roles", name)}
+	for _, rolesPath := range p.rolesPath {
+		candidates = append(candidates, filepath.Join(rolesPath, name))
+	}
+	candidates = append(candidates, filepath.Join(p.root, "roles", name), filepath.Join(playDir, name))
+	if filepath.IsAbs(name) {
+		candidates = []string{name}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return filepath.Clean(candidate), true
+		}
+	}
+	return "", false
+}
+
+// findInclude looks for an included tasks file in the directories of the include context
+func findInclude(name string, dirs []string) (string, bool) {
+	if name == "" || strings.Contains(name, "{{") {
+		return "", false
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name)
+		if filepath.IsAbs(name) {
+			path = name
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// roleDependencies returns the dependencies declared in the meta/main.yml of a role
+func roleDependencies(path string) []*yaml.Node {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+	dependencies := mapValue(node.Content[0], "dependencies")
+	if dependencies == nil || dependencies.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return dependencies.Content
+}
+
+// roleEntry returns the name and the parameters of a role entry, it can be a role name or a mapping
+func roleEntry(entry *yaml.Node) (name string, params map[string]interface{}) {
+	params = make(map[string]interface{})
+	if entry.Kind != yaml.MappingNode {
+		return scalar(entry), params
+	}
+	name = scalar(mapValue(entry, "role"))
+	if name == "" {
+		name = scalar(mapValue(entry, "name"))
+	}
+	for i := 0; i+1 < len(entry.Content); i += 2 {
+		key := entry.Content[i].Value
+		if roleEntryKeys[key] || strings.HasPrefix(key, "become") {
+			continue
+		}
+		var value interface{}
+		if err := entry.Content[i+1].Decode(&value); err == nil {
+			params[key] = value
+		}
+	}
+	for key, value := range nodeVars(mapValue(entry, "vars")) {
+		params[key] = value
+	}
+	return name, params
+}
+
+// forEachItem calls fn for every mapping of a sequence with the range of lines it spans
+func forEachItem(lines []string, sequence *yaml.Node, end int, fn func(item *yaml.Node, start, end int)) {
+	for i, item := range sequence.Content {
+		limit := end
+		if i+1 < len(sequence.Content) {
+			limit = sequence.Content[i+1].Line - 1
+		}
+		if item.Kind == yaml.MappingNode {
+			fn(item, item.Line, itemEnd(lines, item, limit))
+		}
+	}
+}
+
+// itemEnd returns the last line of a sequence item before the limit, the lines indented as its keys or deeper,
+// ignoring the blank and comment lines that follow it
+func itemEnd(lines []string, item *yaml.Node, limit int) int {
+	end := item.Line
+	for line := item.Line + 1; line <= limit && line <= len(lines); line++ {
+		text := strings.TrimSpace(lines[line-1])
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if indentation(lines[line-1]) < item.Column-1 {
+			break
+		}
+		end = line
+	}
+	return end
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// mapValue returns the value node of a key of a mapping node
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
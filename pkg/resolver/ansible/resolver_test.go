@@ -0,0 +1,100 @@
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnsible_SupportedTypes(t *testing.T) {
+	res := &Resolver{}
+	want := []model.FileKind{model.KindANSIBLE}
+	t.Run("get_supported_type", func(t *testing.T) {
+		got := res.SupportedTypes()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("SupportedTypes() = %v, want = %v", got, want)
+		}
+	})
+}
+
+func TestAnsible_Resolve(t *testing.T) {
+	res := &Resolver{}
+	root := filepath.FromSlash("../../../test/fixtures/test_ansible")
+
+	got, err := res.Resolve(root)
+	require.NoError(t, err)
+
+	require.Len(t, got.File, 1)
+	require.Equal(t, filepath.Join(root, "site.yml"), got.File[0].FileName)
+	require.ElementsMatch(t, []string{
+		filepath.Join(root, "site.yml"),
+		filepath.Join(root, "roles", "web", "tasks", "main.yml"),
+		filepath.Join(root, "tasks", "extra.yml"),
+	}, got.Excluded)
+
+	require.Equal(t, `---
+- name: configure webservers
+  hosts: webservers
+  vars:
+    package_state: present
+  roles:
+    - web
+  tasks:
+    - name: "web"
+      block:
+        - name: download web content
+          ansible.builtin.get_url:
+            url: "https://example.com:8080/index.html"
+            dest: "/var/www/nginx/index.html"
+            validate_certs: "false"
+    - name: include extra tasks
+      include_tasks: tasks/extra.yml
+      block:
+        - name: install nginx
+          ansible.builtin.yum:
+            name: nginx
+            state: "present"
+        - name: install curl
+          ansible.builtin.yum:
+            name: curl
+            state: "latest"
+            update_cache: "{{ unknown_var }}"
+`, string(got.File[0].Content))
+
+	lines := got.File[0].SourceLines
+	require.Len(t, lines, 28)
+	require.Equal(t, model.SourceLine{FilePath: filepath.Join(root, "site.yml"), Line: 7, Indent: 4}, lines[8])
+	require.Equal(t, model.SourceLine{FilePath: filepath.Join(root, "roles", "web", "tasks", "main.yml"), Line: 6, Indent: 8},
+		lines[14])
+	require.Equal(t, model.SourceLine{FilePath: filepath.Join(root, "site.yml"), Line: 10}, lines[16])
+	require.Equal(t, model.SourceLine{FilePath: filepath.Join(root, "tasks", "extra.yml"), Line: 9, Indent: 8}, lines[25])
+}
+
+func TestAnsible_Resolve_NoPlaybooks(t *testing.T) {
+	res := &Resolver{}
+	got, err := res.Resolve(filepath.FromSlash("../../../test/fixtures/test_ansible/roles"))
+	require.NoError(t, err)
+	require.Empty(t, got.File)
+	require.Empty(t, got.Excluded)
+}
+
+func TestAnsible_IsProject(t *testing.T) {
+	rbac := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rbac, "roles"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(rbac, "roles", "admin.yaml"),
+		[]byte("apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: admin\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(rbac, "binding.yaml"),
+		[]byte("apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRoleBinding\n"), 0o600))
+
+	configOnly := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configOnly, configFileName), []byte("[defaults]\n"), 0o600))
+
+	require.True(t, IsProject(filepath.FromSlash("../../../test/fixtures/test_ansible")))
+	require.True(t, IsProject(configOnly))
+	require.False(t, IsProject(rbac))
+	require.False(t, IsProject(filepath.FromSlash("../../../test/fixtures/test_ansible/roles")))
+}
@@ -0,0 +1,267 @@
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bigkevmcd/go-configparser"
+	"github.com/relex/aini"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	inventoryFiles = []string{"inventory", "inventory.ini", "hosts", "hosts.ini",
+		filepath.Join("inventory", "hosts"), filepath.Join("inventory", "hosts.ini")}
+	yamlExtensions = []string{".yml", ".yaml"}
+)
+
+// config keeps the ansible.cfg settings used by the resolver
+type config struct {
+	rolesPath []string
+	inventory []string
+}
+
+// loadConfig reads the roles search paths and the inventory sources from the project ansible.cfg
+func loadConfig(root string) config {
+	cfg := config{}
+	path := filepath.Join(root, configFileName)
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return cfg
+	}
+	parsed, err := configparser.ParseReaderWithOptions(strings.NewReader(string(content)),
+		configparser.Delimiters("="), configparser.InlineCommentPrefixes([]string{";"}))
+	if err != nil {
+		log.Debug().Msgf("failed to parse ansible config %s: %s", path, err)
+		return cfg
+	}
+	cfg.rolesPath = splitPaths(root, getOption(parsed, "roles_path"))
+	cfg.inventory = splitPaths(root, strings.ReplaceAll(getOption(parsed, "inventory"), ",", ":"))
+	return cfg
+}
+
+func getOption(parsed *configparser.ConfigParser, option string) string {
+	value, err := parsed.Get("defaults", option)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitPaths splits a colon separated list of paths, making relative paths relative to the project root
+func splitPaths(root, value string) []string {
+	paths := make([]string, 0)
+	for _, path := range strings.Split(value, ":") {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.HasPrefix(path, "~") {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// loadInventory parses the INI inventory of the project and adds the group_vars and host_vars variables,
+// groups and hosts that only exist as group_vars or host_vars entries are added to the inventory
+func loadInventory(root string, cfg config) *aini.InventoryData {
+	sources := cfg.inventory
+	if len(sources) == 0 {
+		for _, name := range inventoryFiles {
+			sources = append(sources, filepath.Join(root, name))
+		}
+	}
+
+	var content strings.Builder
+	varsDirs := []string{root}
+	for _, source := range sources {
+		info, err := os.Stat(source)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Clean(source))
+		if err != nil {
+			continue
+		}
+		content.Write(data)
+		content.WriteString("\n")
+		if dir := filepath.Dir(source); dir != root {
+			varsDirs = append(varsDirs, dir)
+		}
+	}
+
+	inventory, err := aini.ParseString(content.String())
+	if err != nil {
+		log.Debug().Msgf("failed to parse ansible inventory of %s: %s", root, err)
+		inventory, _ = aini.ParseString("")
+	}
+
+	// make sure group_vars and host_vars entries without an inventory definition are still loaded
+	missing := make([]string, 0)
+	for _, dir := range varsDirs {
+		for _, name := range listVarsEntries(filepath.Join(dir, "group_vars")) {
+			if _, ok := inventory.Groups[name]; !ok {
+				missing = append(missing, "["+name+"]")
+			}
+		}
+		for _, name := range listVarsEntries(filepath.Join(dir, "host_vars")) {
+			if _, ok := inventory.Hosts[name]; !ok {
+				missing = append(missing, "[ungrouped]", name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		content.WriteString(strings.Join(missing, "\n"))
+		if completed, err := aini.ParseString(content.String()); err == nil {
+			inventory = completed
+		}
+	}
+
+	for _, dir := range varsDirs {
+		if err := inventory.AddVars(dir); err != nil {
+			log.Debug().Msgf("failed to load ansible variables of %s: %s", dir, err)
+		}
+	}
+	return inventory
+}
+
+// listVarsEntries returns the group or host names defined in a group_vars/host_vars directory
+func listVarsEntries(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return []string{}
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() {
+			if !isYAML(name) {
+				continue
+			}
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// inventoryVars returns the variables of the hosts targeted by a play hosts pattern
+func inventoryVars(inventory *aini.InventoryData, pattern string) map[string]interface{} {
+	vars := make(map[string]interface{})
+	if all, ok := inventory.Groups["all"]; ok {
+		addStringVars(vars, all.Vars)
+	}
+	for _, target := range strings.FieldsFunc(pattern, func(r rune) bool { return r == ':' || r == ',' }) {
+		target = strings.TrimSpace(target)
+		if target == "" || strings.HasPrefix(target, "!") || strings.HasPrefix(target, "&") {
+			continue
+		}
+		if group, ok := inventory.Groups[target]; ok {
+			addStringVars(vars, group.Vars)
+			continue
+		}
+		hosts, err := inventory.MatchHosts(target)
+		if err != nil || len(hosts) == 0 {
+			continue
+		}
+		// hosts may have different values, the first host in lexical order is used
+		addStringVars(vars, aini.HostMapListValues(hosts)[0].Vars)
+	}
+	return vars
+}
+
+func addStringVars(vars map[string]interface{}, values map[string]string) {
+	for key, value := range values {
+		vars[key] = value
+	}
+}
+
+// loadVarsFile reads a YAML variables file, directories are read in lexical order as Ansible does
+func loadVarsFile(path string) map[string]interface{} {
+	vars := make(map[string]interface{})
+	info, err := os.Stat(path)
+	if err != nil {
+		return vars
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return vars
+		}
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for key, value := range loadVarsFile(filepath.Join(path, name)) {
+				vars[key] = value
+			}
+		}
+		return vars
+	}
+	if !isYAML(path) {
+		return vars
+	}
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return vars
+	}
+	if err := yaml.Unmarshal(content, &vars); err != nil {
+		log.Debug().Msgf("failed to load ansible variables file %s: %s", path, err)
+		return make(map[string]interface{})
+	}
+	return vars
+}
+
+// loadRoleVars reads the variables of a role directory such as defaults/ or vars/
+func loadRoleVars(roleDir, kind string) map[string]interface{} {
+	if path, ok := findYAML(filepath.Join(roleDir, kind), "main"); ok {
+		return loadVarsFile(path)
+	}
+	return loadVarsFile(filepath.Join(roleDir, kind, "main"))
+}
+
+// nodeVars decodes a 'vars' mapping node
+func nodeVars(node *yaml.Node) map[string]interface{} {
+	vars := make(map[string]interface{})
+	if node == nil || node.Kind != yaml.MappingNode {
+		return vars
+	}
+	if err := node.Decode(&vars); err != nil {
+		return make(map[string]interface{})
+	}
+	return vars
+}
+
+// findYAML looks for a file with one of the YAML extensions, the name may already have an extension
+func findYAML(dir, name string) (string, bool) {
+	candidates := []string{filepath.Join(dir, name)}
+	if !isYAML(name) {
+		candidates = make([]string, 0, len(yamlExtensions))
+		for _, ext := range yamlExtensions {
+			candidates = append(candidates, filepath.Join(dir, name+ext))
+		}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func isYAML(path string) bool {
+	ext := filepath.Ext(path)
+	for _, yamlExt := range yamlExtensions {
+		if ext == yamlExt {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/resolver/ansible"
 	"github.com/rs/zerolog/log"
 )
 
@@ -70,9 +71,17 @@ func (r *Resolver) Resolve(filePath string, kind model.FileKind) (model.Resolved
 
 // GetType will analyze the filepath to determine which resolver to use
 func (r *Resolver) GetType(filePath string) model.FileKind {
+	return GetKind(filePath)
+}
+
+// GetKind will analyze the directory to determine if it is a Helm chart or an Ansible project
+func GetKind(filePath string) model.FileKind {
 	_, err := os.Stat(filepath.Join(filePath, "Chart.yaml"))
 	if err == nil {
 		return model.KindHELM
 	}
+	if ansible.IsProject(filePath) {
+		return model.KindANSIBLE
+	}
 	return model.KindCOMMON
 }
@@ -34,6 +34,13 @@ func TestGetType(t *testing.T) {
 			},
 			want: model.KindHELM,
 		},
+		{
+			name: "get_ansible_type",
+			args: args{
+				filepath: filepath.FromSlash("../../test/fixtures/test_ansible"),
+			},
+			want: model.KindANSIBLE,
+		},
 		{
 			name: "get_no_type",
 			args: args{
@@ -0,0 +1,128 @@
+package results
+
+import (
+	"strconv"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// Change is a finding of both scans whose severity changed
+type Change struct {
+	Old Finding
+	New Finding
+}
+
+// Diff holds the findings added, removed and with a changed severity between two scans
+type Diff struct {
+	Old       *model.Summary
+	New       *model.Summary
+	Added     []Finding
+	Removed   []Finding
+	Changed   []Change
+	Unchanged int
+}
+
+// matchKeys returns the keys matching a finding of the other scan, from the most to the least precise: the
+// similarity ID, then the query and the search key, the same finding on moved lines, then the query and the line,
+// the same finding with an updated search key
+var matchKeys = []func(*Finding) string{
+	func(f *Finding) string {
+		return f.File.SimilarityID
+	},
+	func(f *Finding) string {
+		return f.Query.QueryID + "|" + f.File.FileName + "|" + string(f.File.IssueType) + "|" +
+			f.File.SearchKey + "|" + f.File.SearchValue
+	},
+	func(f *Finding) string {
+		return f.Query.QueryID + "|" + f.File.FileName + "|" + strconv.Itoa(f.File.Line)
+	},
+}
+
+// Compare matches the findings of the old and the new scan
+func Compare(oldSummary, newSummary *model.Summary) *Diff {
+	oldFindings := Findings(oldSummary)
+	newFindings := Findings(newSummary)
+	oldMatches := make([]int, len(oldFindings))
+	newMatches := make([]int, len(newFindings))
+	for i := range oldMatches {
+		oldMatches[i] = -1
+	}
+	for i := range newMatches {
+		newMatches[i] = -1
+	}
+
+	for _, key := range matchKeys {
+		candidates := make(map[string][]int)
+		for i := range oldFindings {
+			if k := key(&oldFindings[i]); oldMatches[i] < 0 && k != "" {
+				candidates[k] = append(candidates[k], i)
+			}
+		}
+		for i := range newFindings {
+			k := key(&newFindings[i])
+			if newMatches[i] >= 0 || k == "" {
+				continue
+			}
+			if match := closest(oldFindings, candidates[k], oldMatches, newFindings[i].File.Line); match >= 0 {
+				oldMatches[match] = i
+				newMatches[i] = match
+			}
+		}
+	}
+
+	diff := &Diff{Old: oldSummary, New: newSummary}
+	for i := range newFindings {
+		switch match := newMatches[i]; {
+		case match < 0:
+			diff.Added = append(diff.Added, newFindings[i])
+		case oldFindings[match].Query.Severity != newFindings[i].Query.Severity:
+			diff.Changed = append(diff.Changed, Change{Old: oldFindings[match], New: newFindings[i]})
+		default:
+			diff.Unchanged++
+		}
+	}
+	for i := range oldFindings {
+		if oldMatches[i] < 0 {
+			diff.Removed = append(diff.Removed, oldFindings[i])
+		}
+	}
+	return diff
+}
+
+// closest returns the unmatched candidate nearest to the line, -1 if there is none
+func closest(findings []Finding, candidates, matches []int, line int) int {
+	match := -1
+	distance := 0
+	for _, candidate := range candidates {
+		if matches[candidate] >= 0 {
+			continue
+		}
+		d := findings[candidate].File.Line - line
+		if d < 0 {
+			d = -d
+		}
+		if match < 0 || d < distance {
+			match, distance = candidate, d
+		}
+	}
+	return match
+}
+
+// AddedSummary returns a summary of the findings only in the new scan
+func (d *Diff) AddedSummary() model.Summary {
+	return NewSummary(d.New, d.Added)
+}
+
+// RemovedSummary returns a summary of the findings only in the old scan
+func (d *Diff) RemovedSummary() model.Summary {
+	return NewSummary(d.Old, d.Removed)
+}
+
+// ChangedSummary returns a summary of the findings whose severity changed, with their new severity
+func (d *Diff) ChangedSummary() model.Summary {
+	findings := make([]Finding, 0, len(d.Changed))
+	for i := range d.Changed {
+		findings = append(findings, d.Changed[i].New)
+	}
+	return NewSummary(d.New, findings)
+}
@@ -0,0 +1,77 @@
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func diffQuery(id string, severity model.Severity, files ...model.VulnerableFile) model.QueryResult {
+	return model.QueryResult{QueryID: id, QueryName: "query " + id, Severity: severity, Files: files}
+}
+
+func TestCompare(t *testing.T) {
+	oldSummary := &model.Summary{
+		Queries: model.QueryResultSlice{
+			diffQuery("q1", model.SeverityHigh,
+				model.VulnerableFile{FileName: "main.tf", Line: 10, SimilarityID: "same"},
+				model.VulnerableFile{FileName: "main.tf", Line: 20, SimilarityID: "moved", SearchKey: "resource.b"},
+				model.VulnerableFile{FileName: "main.tf", Line: 30, SimilarityID: "fixed", SearchKey: "resource.c"},
+			),
+			diffQuery("q2", model.SeverityMedium,
+				model.VulnerableFile{FileName: "pod.yaml", Line: 5, SimilarityID: "escalated", SearchKey: "spec"},
+			),
+		},
+	}
+	newSummary := &model.Summary{
+		Queries: model.QueryResultSlice{
+			diffQuery("q1", model.SeverityHigh,
+				model.VulnerableFile{FileName: "main.tf", Line: 12, SimilarityID: "same"},
+				model.VulnerableFile{FileName: "main.tf", Line: 25, SimilarityID: "moved-lines", SearchKey: "resource.b"},
+				model.VulnerableFile{FileName: "other.tf", Line: 1, SimilarityID: "introduced", SearchKey: "resource.d"},
+			),
+			diffQuery("q2", model.SeverityHigh,
+				model.VulnerableFile{FileName: "pod.yaml", Line: 5, SimilarityID: "escalated-by-update", SearchKey: "spec.containers"},
+			),
+		},
+	}
+
+	diff := Compare(oldSummary, newSummary)
+	require.Equal(t, 2, diff.Unchanged)
+
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, "introduced", diff.Added[0].File.SimilarityID)
+
+	require.Len(t, diff.Removed, 1)
+	require.Equal(t, "fixed", diff.Removed[0].File.SimilarityID)
+
+	require.Len(t, diff.Changed, 1)
+	require.Equal(t, model.Severity(model.SeverityMedium), diff.Changed[0].Old.Query.Severity)
+	require.Equal(t, model.Severity(model.SeverityHigh), diff.Changed[0].New.Query.Severity)
+
+	added := diff.AddedSummary()
+	require.Equal(t, 1, added.TotalCounter)
+	require.Equal(t, 1, added.SeverityCounters[model.SeverityHigh])
+	require.Equal(t, "other.tf", added.Queries[0].Files[0].FileName)
+
+	changed := diff.ChangedSummary()
+	require.Equal(t, 1, changed.SeverityCounters[model.SeverityHigh])
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"queries": [{"query_id": "q1", "severity": "HIGH",
+		"files": [{"file_name": "main.tf", "line": 1, "similarity_id": "s1"}]}], "total_counter": 1}`), os.ModePerm))
+
+	summary, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.TotalCounter)
+	require.Equal(t, "s1", summary.Queries[0].Files[0].SimilarityID)
+
+	require.NoError(t, os.WriteFile(path, []byte("not json"), os.ModePerm))
+	_, err = Load(path)
+	require.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package results
+
+import (
+	"strconv"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// Merge combines the summaries of several scans, the results of each query are merged and deduplicated by
+// similarity ID, and the counters, the severity summary and the times are recomputed
+func Merge(summaries []*model.Summary) model.Summary {
+	merged := model.Summary{
+		Queries:      make(model.QueryResultSlice, 0),
+		ScannedPaths: make([]string, 0),
+	}
+	queries := newQueryMerger()
+	bom := newQueryMerger()
+	suppressed := newQueryMerger()
+	scannedPaths := make(map[string]bool)
+
+	for _, summary := range summaries {
+		if merged.Version == "" {
+			merged.Version = summary.Version
+			merged.ScanID = summary.ScanID
+		}
+		mergeCounters(&merged.Counters, &summary.Counters)
+		mergeTimes(&merged.Times, &summary.Times)
+		for _, path := range summary.ScannedPaths {
+			if !scannedPaths[path] {
+				scannedPaths[path] = true
+				merged.ScannedPaths = append(merged.ScannedPaths, path)
+			}
+		}
+		queries.add(summary.Queries)
+		bom.add(summary.Bom)
+		suppressed.add(summary.Suppressed)
+		merged.Waivers = append(merged.Waivers, summary.Waivers...)
+	}
+
+	merged.Queries = queries.queries
+	if len(bom.queries) > 0 {
+		merged.Bom = bom.queries
+	}
+	if len(suppressed.queries) > 0 {
+		merged.Suppressed = suppressed.queries
+	}
+	Recount(&merged)
+	return merged
+}
+
+func mergeCounters(merged, counters *model.Counters) {
+	merged.ScannedFiles += counters.ScannedFiles
+	merged.ScannedFilesLines += counters.ScannedFilesLines
+	merged.ParsedFiles += counters.ParsedFiles
+	merged.ParsedFilesLines += counters.ParsedFilesLines
+	merged.IgnoredFilesLines += counters.IgnoredFilesLines
+	merged.FailedToScanFiles += counters.FailedToScanFiles
+	merged.FailedToExecuteQueries += counters.FailedToExecuteQueries
+	merged.FailedSimilarityID += counters.FailedSimilarityID
+	// the shards run the same queries
+	if counters.TotalQueries > merged.TotalQueries {
+		merged.TotalQueries = counters.TotalQueries
+	}
+}
+
+// mergeTimes keeps the earliest start and the latest end
+func mergeTimes(merged, times *model.Times) {
+	if !times.Start.IsZero() && (merged.Start.IsZero() || times.Start.Before(merged.Start)) {
+		merged.Start = times.Start
+	}
+	if times.End.After(merged.End) {
+		merged.End = times.End
+	}
+}
+
+// queryMerger merges the results of the queries, each result once
+type queryMerger struct {
+	queries model.QueryResultSlice
+	indexes map[string]int
+	seen    map[string]bool
+}
+
+func newQueryMerger() *queryMerger {
+	return &queryMerger{
+		queries: make(model.QueryResultSlice, 0),
+		indexes: make(map[string]int),
+		seen:    make(map[string]bool),
+	}
+}
+
+func (m *queryMerger) add(queries model.QueryResultSlice) {
+	for i := range queries {
+		idx, ok := m.indexes[queries[i].QueryID]
+		if !ok {
+			idx = len(m.queries)
+			m.indexes[queries[i].QueryID] = idx
+			query := queries[i]
+			query.Files = make([]model.VulnerableFile, 0, len(queries[i].Files))
+			m.queries = append(m.queries, query)
+		}
+		for j := range queries[i].Files {
+			key := resultKey(queries[i].QueryID, &queries[i].Files[j])
+			if m.seen[key] {
+				continue
+			}
+			m.seen[key] = true
+			m.queries[idx].Files = append(m.queries[idx].Files, queries[i].Files[j])
+		}
+	}
+}
+
+// resultKey identifies a result by its similarity ID, or by its location when the similarity ID failed to compute
+func resultKey(queryID string, file *model.VulnerableFile) string {
+	if file.SimilarityID != "" {
+		return queryID + "|" + file.SimilarityID
+	}
+	return queryID + "|" + file.FileName + "|" + strconv.Itoa(file.Line) + "|" + file.SearchKey
+}
@@ -0,0 +1,60 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	shardA := &model.Summary{
+		Version:      "1.7.0",
+		Counters:     model.Counters{ScannedFiles: 2, ParsedFiles: 2, TotalQueries: 10},
+		Times:        model.Times{Start: start.Add(time.Minute), End: start.Add(3 * time.Minute)},
+		ScannedPaths: []string{"infra"},
+		Queries: model.QueryResultSlice{
+			diffQuery("q1", model.SeverityMedium,
+				model.VulnerableFile{FileName: "infra/main.tf", Line: 1, SimilarityID: "a1"},
+				model.VulnerableFile{FileName: "shared/common.tf", Line: 4, SimilarityID: "shared"},
+			),
+		},
+		Bom: model.QueryResultSlice{
+			diffQuery("bom", model.SeverityTrace, model.VulnerableFile{FileName: "infra/main.tf", SimilarityID: "b1"}),
+		},
+	}
+	shardB := &model.Summary{
+		Counters:     model.Counters{ScannedFiles: 3, ParsedFiles: 3, TotalQueries: 10},
+		Times:        model.Times{Start: start, End: start.Add(2 * time.Minute)},
+		ScannedPaths: []string{"apps"},
+		Queries: model.QueryResultSlice{
+			diffQuery("q1", model.SeverityMedium,
+				model.VulnerableFile{FileName: "shared/common.tf", Line: 4, SimilarityID: "shared"},
+			),
+			diffQuery("q2", model.SeverityHigh,
+				model.VulnerableFile{FileName: "apps/pod.yaml", Line: 3, SimilarityID: "b2"},
+			),
+		},
+	}
+
+	merged := Merge([]*model.Summary{shardA, shardB})
+
+	require.Equal(t, "1.7.0", merged.Version)
+	require.Equal(t, 5, merged.ScannedFiles)
+	require.Equal(t, 10, merged.TotalQueries)
+	require.Equal(t, start, merged.Start)
+	require.Equal(t, start.Add(3*time.Minute), merged.End)
+	require.Equal(t, []string{"infra", "apps"}, merged.ScannedPaths)
+
+	require.Len(t, merged.Queries, 2)
+	require.Equal(t, "q2", merged.Queries[0].QueryID)
+	require.Len(t, merged.Queries[1].Files, 2)
+
+	require.Equal(t, 3, merged.TotalCounter)
+	require.Equal(t, 1, merged.SeverityCounters[model.SeverityHigh])
+	require.Equal(t, 2, merged.SeverityCounters[model.SeverityMedium])
+	require.Equal(t, 1, merged.SeverityCounters[model.SeverityTrace])
+	require.Equal(t, 1, merged.TotalBOMResources)
+}
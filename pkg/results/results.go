@@ -0,0 +1,107 @@
+// Package results loads the JSON reports of scans to compare and combine their results
+package results
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// Finding is a result of a scan, a vulnerable file with the query that found it
+type Finding struct {
+	Query *model.QueryResult
+	File  *model.VulnerableFile
+}
+
+// Load reads the JSON report of a scan
+func Load(path string) (*model.Summary, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var summary model.Summary
+	if err := json.Unmarshal(content, &summary); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the JSON report %s", path)
+	}
+
+	// the JSON report leaves out the lines of the results, the reports showing them expect a slice
+	for _, queries := range []model.QueryResultSlice{summary.Queries, summary.Bom, summary.Suppressed} {
+		for i := range queries {
+			for j := range queries[i].Files {
+				queries[i].Files[j].VulnLines = &[]model.CodeLine{}
+			}
+		}
+	}
+	return &summary, nil
+}
+
+// Findings returns the findings of the queries of the summary
+func Findings(summary *model.Summary) []Finding {
+	findings := make([]Finding, 0)
+	for i := range summary.Queries {
+		for j := range summary.Queries[i].Files {
+			findings = append(findings, Finding{Query: &summary.Queries[i], File: &summary.Queries[i].Files[j]})
+		}
+	}
+	return findings
+}
+
+// NewSummary returns a summary of the findings, grouped by query, with the scan information of the base summary
+func NewSummary(base *model.Summary, findings []Finding) model.Summary {
+	summary := *base
+	summary.Queries = make(model.QueryResultSlice, 0)
+	summary.Bom = nil
+	summary.Suppressed = nil
+	summary.Gate = nil
+
+	queries := make(map[string]int)
+	for _, finding := range findings {
+		idx, ok := queries[finding.Query.QueryID]
+		if !ok {
+			idx = len(summary.Queries)
+			queries[finding.Query.QueryID] = idx
+			query := *finding.Query
+			query.Files = make([]model.VulnerableFile, 0)
+			summary.Queries = append(summary.Queries, query)
+		}
+		summary.Queries[idx].Files = append(summary.Queries[idx].Files, *finding.File)
+	}
+
+	Recount(&summary)
+	return summary
+}
+
+// Recount sorts the queries of the summary by severity and recomputes its severity counters, the bill of
+// materials counted as TRACE as the scan does
+func Recount(summary *model.Summary) {
+	severityOrder := make(map[model.Severity]int, len(model.AllSeverities))
+	for i, severity := range model.AllSeverities {
+		severityOrder[severity] = i
+	}
+	sort.SliceStable(summary.Queries, func(i, j int) bool {
+		if summary.Queries[i].Severity != summary.Queries[j].Severity {
+			return severityOrder[summary.Queries[i].Severity] < severityOrder[summary.Queries[j].Severity]
+		}
+		return summary.Queries[i].QueryName < summary.Queries[j].QueryName
+	})
+
+	summary.SeverityCounters = make(map[model.Severity]int, len(model.AllSeverities))
+	for _, severity := range model.AllSeverities {
+		summary.SeverityCounters[severity] = 0
+	}
+	summary.TotalCounter = 0
+	for i := range summary.Queries {
+		summary.SeverityCounters[summary.Queries[i].Severity] += len(summary.Queries[i].Files)
+		summary.TotalCounter += len(summary.Queries[i].Files)
+	}
+	summary.TotalBOMResources = 0
+	for i := range summary.Bom {
+		summary.SeverityCounters[model.SeverityTrace] += len(summary.Bom[i].Files)
+		summary.TotalBOMResources += len(summary.Bom[i].Files)
+	}
+}
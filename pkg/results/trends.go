@@ -0,0 +1,150 @@
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/pkg/errors"
+)
+
+// maxSlowestQueries is the number of queries kept by the trends, from the slowest to fix
+const maxSlowestQueries = 10
+
+// TrendScan is a scan of the trends, with its results by severity and its results introduced and fixed since the
+// previous scan
+type TrendScan struct {
+	Start            time.Time
+	SeverityCounters map[model.Severity]int
+	Total            int
+	Introduced       int
+	Fixed            int
+}
+
+// QueryRemediation is the time taken to fix the results of a query
+type QueryRemediation struct {
+	QueryID   string
+	QueryName string
+	Severity  model.Severity
+	Fixed     int
+	MeanTime  time.Duration
+}
+
+// Trends are the results of successive scans, from the oldest to the latest
+type Trends struct {
+	Scans []TrendScan
+	// Fixed is the number of results of the previous scans no longer found by the latest
+	Fixed int
+	// MeanTimeToRemediate is the mean time from the first scan finding a result to the first scan no longer
+	// finding it, the results being identified by their similarity ID
+	MeanTimeToRemediate time.Duration
+	SlowestQueries      []QueryRemediation
+}
+
+// LoadHistory reads the JSON reports of a directory, sorted from the oldest to the latest scan, the files which
+// are not JSON reports are skipped
+func LoadHistory(dir string) ([]*model.Summary, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the reports directory %s", dir)
+	}
+
+	history := make([]*model.Summary, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+		summary, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil || summary.Start.IsZero() {
+			// other JSON files, like the reports of other formats, have no scan start time
+			continue
+		}
+		history = append(history, summary)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Start.Before(history[j].Start)
+	})
+	return history, nil
+}
+
+type remediation struct {
+	query     *model.QueryResult
+	firstSeen time.Time
+	lastSeen  int
+}
+
+// NewTrends computes the trends of the scans, sorted from the oldest to the latest
+func NewTrends(scans []*model.Summary) *Trends {
+	trends := &Trends{Scans: make([]TrendScan, 0, len(scans)), SlowestQueries: make([]QueryRemediation, 0)}
+	seen := make(map[string]*remediation)
+	for i, summary := range scans {
+		scan := TrendScan{Start: summary.Start, SeverityCounters: make(map[model.Severity]int)}
+		for j := range summary.Queries {
+			scan.SeverityCounters[summary.Queries[j].Severity] += len(summary.Queries[j].Files)
+			scan.Total += len(summary.Queries[j].Files)
+			for k := range summary.Queries[j].Files {
+				id := summary.Queries[j].Files[k].SimilarityID
+				if id == "" {
+					continue
+				}
+				if _, ok := seen[id]; !ok {
+					seen[id] = &remediation{query: &summary.Queries[j], firstSeen: summary.Start}
+				}
+				seen[id].lastSeen = i
+			}
+		}
+		if i > 0 {
+			diff := Compare(scans[i-1], summary)
+			scan.Introduced = len(diff.Added)
+			scan.Fixed = len(diff.Removed)
+		}
+		trends.Scans = append(trends.Scans, scan)
+	}
+
+	var total time.Duration
+	queries := make(map[string]*QueryRemediation)
+	for _, finding := range seen {
+		if finding.lastSeen == len(scans)-1 {
+			continue
+		}
+		// fixed by the scan following the last one finding it
+		duration := scans[finding.lastSeen+1].Start.Sub(finding.firstSeen)
+		total += duration
+		trends.Fixed++
+
+		query, ok := queries[finding.query.QueryID]
+		if !ok {
+			query = &QueryRemediation{
+				QueryID:   finding.query.QueryID,
+				QueryName: finding.query.QueryName,
+				Severity:  finding.query.Severity,
+			}
+			queries[finding.query.QueryID] = query
+		}
+		query.MeanTime += duration
+		query.Fixed++
+	}
+	if trends.Fixed == 0 {
+		return trends
+	}
+	trends.MeanTimeToRemediate = total / time.Duration(trends.Fixed)
+
+	for _, query := range queries {
+		query.MeanTime /= time.Duration(query.Fixed)
+		trends.SlowestQueries = append(trends.SlowestQueries, *query)
+	}
+	sort.Slice(trends.SlowestQueries, func(i, j int) bool {
+		if trends.SlowestQueries[i].MeanTime != trends.SlowestQueries[j].MeanTime {
+			return trends.SlowestQueries[i].MeanTime > trends.SlowestQueries[j].MeanTime
+		}
+		return trends.SlowestQueries[i].QueryName < trends.SlowestQueries[j].QueryName
+	})
+	if len(trends.SlowestQueries) > maxSlowestQueries {
+		trends.SlowestQueries = trends.SlowestQueries[:maxSlowestQueries]
+	}
+	return trends
+}
@@ -0,0 +1,89 @@
+package results
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTrends(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	scans := []*model.Summary{
+		{
+			Times: model.Times{Start: start},
+			Queries: model.QueryResultSlice{
+				diffQuery("q1", model.SeverityHigh,
+					model.VulnerableFile{FileName: "main.tf", Line: 1, SimilarityID: "a"},
+					model.VulnerableFile{FileName: "main.tf", Line: 5, SimilarityID: "b"},
+				),
+				diffQuery("q2", model.SeverityMedium, model.VulnerableFile{FileName: "main.tf", Line: 9, SimilarityID: "c"}),
+			},
+		},
+		{
+			Times: model.Times{Start: start.Add(2 * day)},
+			Queries: model.QueryResultSlice{
+				diffQuery("q1", model.SeverityHigh, model.VulnerableFile{FileName: "main.tf", Line: 5, SimilarityID: "b"}),
+				diffQuery("q2", model.SeverityMedium,
+					model.VulnerableFile{FileName: "main.tf", Line: 9, SimilarityID: "c"},
+					model.VulnerableFile{FileName: "vars.tf", Line: 2, SimilarityID: "d"},
+				),
+			},
+		},
+		{
+			Times: model.Times{Start: start.Add(5 * day)},
+			Queries: model.QueryResultSlice{
+				diffQuery("q2", model.SeverityMedium, model.VulnerableFile{FileName: "vars.tf", Line: 2, SimilarityID: "d"}),
+			},
+		},
+	}
+
+	trends := NewTrends(scans)
+
+	require.Len(t, trends.Scans, 3)
+	require.Equal(t, 2, trends.Scans[0].SeverityCounters[model.SeverityHigh])
+	require.Equal(t, 3, trends.Scans[0].Total)
+	require.Equal(t, 0, trends.Scans[0].Introduced)
+	require.Equal(t, 1, trends.Scans[1].Introduced)
+	require.Equal(t, 1, trends.Scans[1].Fixed)
+	require.Equal(t, 0, trends.Scans[2].Introduced)
+	require.Equal(t, 2, trends.Scans[2].Fixed)
+
+	// a fixed after 2 days, b and c after 5 days
+	require.Equal(t, 3, trends.Fixed)
+	require.Equal(t, 4*day, trends.MeanTimeToRemediate)
+	require.Equal(t, []QueryRemediation{
+		{QueryID: "q2", QueryName: "query q2", Severity: model.SeverityMedium, Fixed: 1, MeanTime: 5 * day},
+		{QueryID: "q1", QueryName: "query q1", Severity: model.SeverityHigh, Fixed: 2, MeanTime: 84 * time.Hour},
+	}, trends.SlowestQueries)
+}
+
+func TestLoadHistory(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	for name, summary := range map[string]model.Summary{
+		"b.json": {Times: model.Times{Start: start}},
+		"a.json": {Times: model.Times{Start: start.Add(time.Hour)}},
+		"c.json": {},
+	} {
+		content, err := json.Marshal(summary)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, os.ModePerm))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "d.json"), []byte("[]"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "results.html"), []byte("<html></html>"), os.ModePerm))
+
+	history, err := LoadHistory(dir)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, start, history[0].Start)
+	require.Equal(t, start.Add(time.Hour), history[1].Start)
+
+	_, err = LoadHistory(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,296 @@
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// Weight names, as used by --risk-weights and the risk-weights key of the configuration file
+const (
+	WeightPublicExposure         = "public-exposure"
+	WeightProduction             = "production"
+	WeightReferenced             = "referenced"
+	WeightSecretConfidencePrefix = "secret-confidence-"
+)
+
+// ExposurePublic is the exposure, in the query metadata, of the queries about publicly accessible resources
+const ExposurePublic = "public"
+
+// Weights are added up to compute the risk score of a finding: the weight of its severity, plus the weight of
+// each contextual factor found for it
+type Weights struct {
+	Severity         map[model.Severity]float64
+	PublicExposure   float64
+	Production       float64
+	Referenced       float64
+	SecretConfidence map[string]float64
+}
+
+// DefaultWeights returns the weights used when none is configured, a high severity finding scores 7 and
+// every factor can raise the score of a medium severity finding above it
+func DefaultWeights() Weights {
+	return Weights{
+		Severity: map[model.Severity]float64{
+			model.SeverityHigh:   7,
+			model.SeverityMedium: 5,
+			model.SeverityLow:    3,
+			model.SeverityInfo:   1,
+			model.SeverityTrace:  0,
+		},
+		PublicExposure: 2,
+		Production:     1.5,
+		Referenced:     1,
+		SecretConfidence: map[string]float64{
+			model.SecretConfidenceHigh:   1,
+			model.SecretConfidenceMedium: 0.5,
+			model.SecretConfidenceLow:    0,
+		},
+	}
+}
+
+// ParseWeights returns the default weights overridden by name=value pairs, where the name is a severity
+// (high, medium, low, info), public-exposure, production, referenced or secret-confidence-<high|medium|low>
+func ParseWeights(pairs []string) (Weights, error) {
+	weights := DefaultWeights()
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return weights, fmt.Errorf("invalid risk weight '%s', expected <name>=<value>", pair)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			return weights, fmt.Errorf("invalid value of risk weight '%s': %s is not a positive number", name, value)
+		}
+		if err := weights.set(name, weight); err != nil {
+			return weights, err
+		}
+	}
+	return weights, nil
+}
+
+func (w *Weights) set(name string, weight float64) error {
+	switch {
+	case name == WeightPublicExposure:
+		w.PublicExposure = weight
+	case name == WeightProduction:
+		w.Production = weight
+	case name == WeightReferenced:
+		w.Referenced = weight
+	case strings.HasPrefix(name, WeightSecretConfidencePrefix):
+		confidence := strings.TrimPrefix(name, WeightSecretConfidencePrefix)
+		if _, ok := w.SecretConfidence[confidence]; !ok {
+			return fmt.Errorf("unknown risk weight '%s'", name)
+		}
+		w.SecretConfidence[confidence] = weight
+	default:
+		severity := model.Severity(strings.ToUpper(name))
+		if _, ok := w.Severity[severity]; !ok || severity == model.SeverityTrace {
+			return fmt.Errorf("unknown risk weight '%s'", name)
+		}
+		w.Severity[severity] = weight
+	}
+	return nil
+}
+
+var (
+	productionPathRegex  = regexp.MustCompile(`(?i)(^|[/\\._-])(prod|production|prd|live)([/\\._-]|$)`)
+	environmentRegex     = regexp.MustCompile(`(?im)^\s*["']?(?:env|environment|stage|workspace)["']?\s*[=:]\s*["']?([\w.-]+)`)
+	publicExposureValues = []string{"0.0.0.0/0", "::/0"}
+	terraformKeyRegex    = regexp.MustCompile(`^([\w-]+)\[([^\]]+)\]`)
+	cloudFormationRegex  = regexp.MustCompile(`^Resources\.([^.\[]+)`)
+)
+
+// Scorer computes the risk score of the findings of a scan
+type Scorer struct {
+	weights    Weights
+	contents   []string
+	public     map[string]bool
+	production map[string]bool
+	reference  map[string]bool
+}
+
+// NewScorer creates a scorer for the findings of a summary, the scanned files are used to tell if a resource
+// is referenced by other resources
+func NewScorer(weights Weights, summary *model.Summary, files model.FileMetadatas) *Scorer {
+	contents := make([]string, 0, len(files))
+	seen := make(map[string]bool, len(files))
+	for i := range files {
+		if seen[files[i].FilePath] {
+			continue
+		}
+		seen[files[i].FilePath] = true
+		contents = append(contents, files[i].OriginalData)
+	}
+
+	return &Scorer{
+		weights:    weights,
+		contents:   contents,
+		public:     publicResources(summary),
+		production: productionWorkspaces(files),
+		reference:  make(map[string]bool),
+	}
+}
+
+// productionWorkspaces indexes the directories of the scanned files that are production workspaces: the selected
+// Terraform workspace, or the environment a file of the directory sets, e.g. environment = "prod", has a
+// production-like name
+func productionWorkspaces(files model.FileMetadatas) map[string]bool {
+	production := make(map[string]bool)
+	for i := range files {
+		dir := filepath.Dir(files[i].FilePath)
+		if _, ok := production[dir]; !ok {
+			production[dir] = isProductionName(terraformWorkspace(dir))
+		}
+		if production[dir] {
+			continue
+		}
+		for _, groups := range environmentRegex.FindAllStringSubmatch(files[i].OriginalData, -1) {
+			if isProductionName(groups[1]) {
+				production[dir] = true
+				break
+			}
+		}
+	}
+	return production
+}
+
+// terraformWorkspace returns the Terraform workspace selected in the directory, if any
+func terraformWorkspace(dir string) string {
+	content, err := os.ReadFile(filepath.Clean(filepath.Join(dir, ".terraform", "environment")))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func isProductionName(name string) bool {
+	return name != "" && productionPathRegex.MatchString(name)
+}
+
+// publicResources indexes the resources the bill of materials reports as publicly accessible, by file and name
+func publicResources(summary *model.Summary) map[string]bool {
+	public := make(map[string]bool)
+	for i := range summary.Bom {
+		for j := range summary.Bom[i].Files {
+			file := &summary.Bom[i].Files[j]
+			if file.Value == nil {
+				continue
+			}
+			var resource struct {
+				Name          string `json:"resource_name"`
+				Accessibility string `json:"resource_accessibility"`
+			}
+			if err := json.Unmarshal([]byte(*file.Value), &resource); err != nil {
+				continue
+			}
+			if resource.Accessibility == "public" {
+				public[file.FileName+"|"+resource.Name] = true
+			}
+		}
+	}
+	return public
+}
+
+// Score sets the risk score of the findings of the summary and sorts the queries and their files from
+// the highest to the lowest score
+func (s *Scorer) Score(summary *model.Summary) {
+	for i := range summary.Queries {
+		query := &summary.Queries[i]
+		query.RiskScore = 0
+		for j := range query.Files {
+			file := &query.Files[j]
+			file.RiskScore = s.score(query, file)
+			query.RiskScore = math.Max(query.RiskScore, file.RiskScore)
+		}
+		sort.SliceStable(query.Files, func(a, b int) bool {
+			return query.Files[a].RiskScore > query.Files[b].RiskScore
+		})
+	}
+
+	sort.SliceStable(summary.Queries, func(a, b int) bool {
+		return summary.Queries[a].RiskScore > summary.Queries[b].RiskScore
+	})
+}
+
+func (s *Scorer) score(query *model.QueryResult, file *model.VulnerableFile) float64 {
+	score := s.weights.Severity[query.Severity]
+	if s.isPublic(query, file) {
+		score += s.weights.PublicExposure
+	}
+	if s.isProduction(file) {
+		score += s.weights.Production
+	}
+	if file.Confidence != "" {
+		score += s.weights.SecretConfidence[file.Confidence]
+	}
+	if s.isReferenced(query, file) {
+		score += s.weights.Referenced
+	}
+	return math.Round(score*10) / 10
+}
+
+// isProduction tells if the finding is in a production-like path or in a production workspace
+func (s *Scorer) isProduction(file *model.VulnerableFile) bool {
+	return productionPathRegex.MatchString(file.FileName) || s.production[filepath.Dir(file.FileName)]
+}
+
+// isPublic tells if the finding is about a publicly exposed resource, either from the exposure of the query
+// metadata, the value found or the accessibility of the resource in the bill of materials
+func (s *Scorer) isPublic(query *model.QueryResult, file *model.VulnerableFile) bool {
+	if strings.EqualFold(query.Exposure, ExposurePublic) {
+		return true
+	}
+	for _, value := range publicExposureValues {
+		if strings.Contains(file.KeyActualValue, value) || strings.Contains(file.SearchValue, value) {
+			return true
+		}
+	}
+	return file.ResourceName != "" && s.public[file.FileName+"|"+file.ResourceName]
+}
+
+// isReferenced tells if the resource of the finding is referenced by other resources of the scanned files: a
+// Terraform resource is referenced by its address, other resources by their name, which must then be found
+// more than once, the first being its own definition
+func (s *Scorer) isReferenced(query *model.QueryResult, file *model.VulnerableFile) bool {
+	token, occurrences := "", 2
+	if groups := terraformKeyRegex.FindStringSubmatch(file.SearchKey); groups != nil && strings.EqualFold(query.Platform, "terraform") {
+		token, occurrences = groups[1]+"."+groups[2], 1
+	} else if groups := cloudFormationRegex.FindStringSubmatch(file.SearchKey); groups != nil {
+		token = groups[1]
+	} else if file.ResourceName != "" && file.ResourceName != "unknown" {
+		token = file.ResourceName
+	}
+	if token == "" {
+		return false
+	}
+
+	key := token + "|" + strconv.Itoa(occurrences)
+	if referenced, ok := s.reference[key]; ok {
+		return referenced
+	}
+
+	tokenRegex := regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(token) + `($|[^\w-])`)
+	found := 0
+	for _, content := range s.contents {
+		found += len(tokenRegex.FindAllStringIndex(content, occurrences))
+		if found >= occurrences {
+			break
+		}
+	}
+	s.reference[key] = found >= occurrences
+	return s.reference[key]
+}
@@ -0,0 +1,129 @@
+package risk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWeights(t *testing.T) {
+	weights, err := ParseWeights([]string{"high=10", "public-exposure=3.5", "secret-confidence-high=2", ""})
+	require.NoError(t, err)
+	require.Equal(t, 10.0, weights.Severity[model.SeverityHigh])
+	require.Equal(t, 5.0, weights.Severity[model.SeverityMedium])
+	require.Equal(t, 3.5, weights.PublicExposure)
+	require.Equal(t, 2.0, weights.SecretConfidence[model.SecretConfidenceHigh])
+
+	for _, pairs := range [][]string{{"high"}, {"critical=1"}, {"trace=1"}, {"production=-1"}, {"secret-confidence-max=1"}} {
+		_, err := ParseWeights(pairs)
+		require.Error(t, err, pairs)
+	}
+}
+
+func TestScore(t *testing.T) {
+	value := `{"resource_type":"aws_s3_bucket","resource_name":"logs","resource_accessibility":"public"}`
+	summary := &model.Summary{
+		Queries: model.QueryResultSlice{
+			{
+				QueryName: "S3 Bucket Without Versioning",
+				Severity:  model.SeverityHigh,
+				Platform:  "Terraform",
+				Files: []model.VulnerableFile{
+					{FileName: "dev/main.tf", SearchKey: "aws_s3_bucket[unused]"},
+				},
+			},
+			{
+				QueryName: "S3 Bucket Logging Disabled",
+				Severity:  model.SeverityMedium,
+				Platform:  "Terraform",
+				Files: []model.VulnerableFile{
+					{FileName: "dev/main.tf", SearchKey: "aws_s3_bucket[unused]"},
+					{FileName: "envs/prod/main.tf", SearchKey: "aws_s3_bucket[logs].logging", ResourceName: "logs"},
+				},
+			},
+			{
+				QueryName: "Passwords And Secrets - Generic Token",
+				Severity:  model.SeverityHigh,
+				Platform:  "Common",
+				Files: []model.VulnerableFile{
+					{FileName: "config.yaml", Confidence: model.SecretConfidenceMedium},
+				},
+			},
+		},
+		Bom: model.QueryResultSlice{
+			{
+				Files: []model.VulnerableFile{{FileName: "envs/prod/main.tf", Value: &value}},
+			},
+		},
+	}
+	files := model.FileMetadatas{
+		{FilePath: "envs/prod/main.tf", OriginalData: `resource "aws_s3_bucket" "logs" {}`},
+		{FilePath: "envs/prod/cdn.tf", OriginalData: `origin { domain_name = aws_s3_bucket.logs.bucket_domain_name }`},
+		{FilePath: "envs/prod/cdn.tf", OriginalData: `origin { domain_name = aws_s3_bucket.logs.bucket_domain_name }`},
+	}
+
+	NewScorer(DefaultWeights(), summary, files).Score(summary)
+
+	// medium (5) + public exposure (2) + production (1.5) + referenced (1)
+	require.Equal(t, "S3 Bucket Logging Disabled", summary.Queries[0].QueryName)
+	require.Equal(t, 9.5, summary.Queries[0].RiskScore)
+	require.Equal(t, "envs/prod/main.tf", summary.Queries[0].Files[0].FileName)
+	require.Equal(t, 9.5, summary.Queries[0].Files[0].RiskScore)
+	require.Equal(t, 5.0, summary.Queries[0].Files[1].RiskScore)
+
+	// high (7) + medium secret confidence (0.5)
+	require.Equal(t, 7.5, summary.Queries[1].RiskScore)
+	require.Equal(t, 7.0, summary.Queries[2].RiskScore)
+}
+
+func TestIsReferenced(t *testing.T) {
+	scorer := NewScorer(DefaultWeights(), &model.Summary{}, model.FileMetadatas{
+		{FilePath: "template.yaml", OriginalData: "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n  Policy:\n    Properties:\n      Bucket: !Ref Bucket\n"},
+		{FilePath: "deployment.yaml", OriginalData: "metadata:\n  name: web-api\n"},
+	})
+
+	require.True(t, scorer.isReferenced(&model.QueryResult{Platform: "CloudFormation"},
+		&model.VulnerableFile{SearchKey: "Resources.Bucket.Properties"}))
+	require.False(t, scorer.isReferenced(&model.QueryResult{Platform: "CloudFormation"},
+		&model.VulnerableFile{SearchKey: "Resources.Policy.Properties"}))
+	require.False(t, scorer.isReferenced(&model.QueryResult{Platform: "Kubernetes"},
+		&model.VulnerableFile{SearchKey: "metadata.name={{web-api}}", ResourceName: "web"}))
+}
+
+func TestIsPublic(t *testing.T) {
+	scorer := NewScorer(DefaultWeights(), &model.Summary{}, model.FileMetadatas{})
+
+	require.True(t, scorer.isPublic(&model.QueryResult{QueryName: "S3 Bucket With Public Access", Exposure: ExposurePublic},
+		&model.VulnerableFile{}))
+	require.True(t, scorer.isPublic(&model.QueryResult{QueryName: "Security Group With Unrestricted Access"},
+		&model.VulnerableFile{KeyActualValue: "cidr_blocks contains 0.0.0.0/0"}))
+	// the query name alone does not make a finding public
+	require.False(t, scorer.isPublic(&model.QueryResult{QueryName: "S3 Bucket Public ACL Overridden By Public Access Block"},
+		&model.VulnerableFile{}))
+}
+
+func TestIsProduction(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, ".terraform"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".terraform", "environment"), []byte("prod-eu\n"), os.ModePerm))
+	staging := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(staging, ".terraform"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(staging, ".terraform", "environment"), []byte("staging\n"), os.ModePerm))
+
+	scorer := NewScorer(DefaultWeights(), &model.Summary{}, model.FileMetadatas{
+		{FilePath: filepath.Join(workspace, "main.tf"), OriginalData: `resource "aws_s3_bucket" "logs" {}`},
+		{FilePath: filepath.Join(staging, "main.tf"), OriginalData: `resource "aws_s3_bucket" "logs" {}`},
+		{FilePath: filepath.Join("stacks", "web", "main.tf"), OriginalData: `resource "aws_s3_bucket" "web" {}`},
+		{FilePath: filepath.Join("stacks", "web", "terraform.tfvars"), OriginalData: "region      = \"eu-west-1\"\nenvironment = \"production\"\n"},
+		{FilePath: filepath.Join("stacks", "api", "values.yaml"), OriginalData: "env: development\nreplicas: 2\n"},
+	})
+
+	require.True(t, scorer.isProduction(&model.VulnerableFile{FileName: "envs/prod/main.tf"}))
+	require.True(t, scorer.isProduction(&model.VulnerableFile{FileName: filepath.Join(workspace, "main.tf")}))
+	require.False(t, scorer.isProduction(&model.VulnerableFile{FileName: filepath.Join(staging, "main.tf")}))
+	require.True(t, scorer.isProduction(&model.VulnerableFile{FileName: filepath.Join("stacks", "web", "main.tf")}))
+	require.False(t, scorer.isProduction(&model.VulnerableFile{FileName: filepath.Join("stacks", "api", "values.yaml")}))
+}
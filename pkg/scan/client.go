@@ -7,6 +7,7 @@ import (
 	"github.com/Checkmarx/kics/internal/storage"
 	"github.com/Checkmarx/kics/internal/tracker"
 	"github.com/Checkmarx/kics/pkg/descriptions"
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
 	"github.com/Checkmarx/kics/pkg/model"
 	consolePrinter "github.com/Checkmarx/kics/pkg/printer"
 	"github.com/Checkmarx/kics/pkg/progress"
@@ -27,12 +28,19 @@ type Parameters struct {
 	InputData                   string
 	OutputName                  string
 	OutputPath                  string
+	Ownership                   bool
+	Owners                      []string
 	Path                        []string
 	PayloadPath                 string
 	PreviewLines                int
 	QueriesPath                 []string
+	QueryCoverage               string
 	LibrariesPath               string
 	ReportFormats               []string
+	MarkdownMaxLength           int
+	SarifBaseline               string
+	HTMLHistory                 string
+	RiskWeights                 []string
 	Platform                    []string
 	ExcludePlatform             []string
 	FilesAndTypes               []model.FileAndType
@@ -41,6 +49,10 @@ type Parameters struct {
 	LineInfoPayload             bool
 	DisableSecrets              bool
 	SecretsRegexesPath          string
+	SecretsRulesPacks           []string
+	SecretsMinConfidence        string
+	WaiversPath                 string
+	GatePath                    string
 	ChangedDefaultQueryPath     bool
 	ChangedDefaultLibrariesPath bool
 	ScanID                      string
@@ -56,8 +68,11 @@ type Client struct {
 	Tracker           *tracker.CITracker
 	Storage           *storage.MemoryStorage
 	ExcludeResultsMap map[string]bool
+	Waivers           []model.Waiver
+	Gate              *model.GateFile
 	Printer           *consolePrinter.Printer
 	ProBarBuilder     *progress.PbBuilder
+	Coverage          *coverage.Collector
 }
 
 // NewClient initializes the client with all the required parameters
@@ -88,6 +103,22 @@ func NewClient(params *Parameters, proBarBuilder *progress.PbBuilder, customPrin
 func (c *Client) PerformScan(ctx context.Context) error {
 	c.ScanStartTime = time.Now()
 
+	// the waivers and the policy gate only apply to the results of a scan, so the commands that do not scan
+	// ignore their files
+	waivers, err := loadWaivers(c.ScanParams.WaiversPath, c.ScanParams.Path)
+	if err != nil {
+		log.Err(err)
+		return err
+	}
+	c.Waivers = waivers
+
+	gate, err := loadGate(c.ScanParams.GatePath, c.ScanParams.Path)
+	if err != nil {
+		log.Err(err)
+		return err
+	}
+	c.Gate = gate
+
 	scanResults, err := c.executeScan(ctx)
 
 	if err != nil {
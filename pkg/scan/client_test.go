@@ -1,6 +1,7 @@
 package scan
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -29,3 +30,18 @@ func Test_ClientError(t *testing.T) {
 	require.Nil(t, client)
 	require.Error(t, err)
 }
+
+func Test_ClientInvalidScanFiles(t *testing.T) {
+	params := &Parameters{
+		PreviewLines:   3,
+		ExcludeResults: []string{},
+		Path:           []string{filepath.Join(waiversFixtures, "invalid"), filepath.Join(gateFixtures, "invalid")},
+	}
+
+	client, err := NewClient(params, nil, nil)
+
+	require.NotNil(t, client)
+	require.NoError(t, err)
+	require.Nil(t, client.Waivers)
+	require.Nil(t, client.Gate)
+}
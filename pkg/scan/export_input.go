@@ -0,0 +1,117 @@
+package scan
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Checkmarx/kics/internal/constants"
+	"github.com/Checkmarx/kics/pkg/engine/source"
+	"github.com/Checkmarx/kics/pkg/kics"
+	"github.com/Checkmarx/kics/pkg/model"
+)
+
+// PlatformInput is the rego input of the queries of a platform for the files of a kind, the queries
+// of a platform run once for each parser supporting it
+type PlatformInput struct {
+	Platform string
+	Kind     model.FileKind
+	Library  string
+	Input    model.Documents
+}
+
+// ExportInputs prepares the sources as the scan does and returns the input of the queries of each
+// detected platform
+func (c *Client) ExportInputs(ctx context.Context) ([]PlatformInput, error) {
+	extractedPaths, err := c.prepareAndAnalyzePaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer deleteExtractionFolder(extractedPaths.ExtractionMap)
+
+	if len(extractedPaths.Path) == 0 {
+		return []PlatformInput{}, nil
+	}
+
+	querySource := source.NewFilesystemSource(
+		c.ScanParams.QueriesPath,
+		c.ScanParams.Platform,
+		c.ScanParams.CloudProvider,
+		c.ScanParams.LibrariesPath,
+		"")
+
+	services, err := c.createService(nil, nil, nil, extractedPaths.Path, c.Tracker, c.Storage, querySource)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareSources(ctx, c.ScanParams.ScanID, services); err != nil {
+		return nil, err
+	}
+
+	inputs := make([]PlatformInput, 0)
+	for _, service := range services {
+		files := service.Files()
+		if len(files) == 0 {
+			continue
+		}
+
+		// the inspector combines the documents without the lines information
+		input := files.Combine(false)
+		for _, platform := range service.Parser.Platform {
+			if !containsFold(c.ScanParams.Platform, platform) {
+				continue
+			}
+			inputs = append(inputs, PlatformInput{
+				Platform: platform,
+				Kind:     files[0].Kind,
+				Library:  platformLibrary(platform),
+				Input:    input,
+			})
+		}
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].Platform != inputs[j].Platform {
+			return inputs[i].Platform < inputs[j].Platform
+		}
+		return inputs[i].Kind < inputs[j].Kind
+	})
+	return inputs, nil
+}
+
+// prepareSources parses the sources of the services concurrently, as the scanner does before running the queries
+func prepareSources(ctx context.Context, scanID string, services []*kics.Service) error {
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(services))
+	for _, service := range services {
+		wg.Add(1)
+		go service.PrepareSources(ctx, scanID, &wg, errCh)
+	}
+	wg.Wait()
+	close(errCh)
+
+	if err, ok := <-errCh; ok {
+		return err
+	}
+	return nil
+}
+
+// platformLibrary returns the name of the library of the platform
+func platformLibrary(platform string) string {
+	for name, library := range constants.AvailablePlatforms {
+		if strings.EqualFold(name, platform) {
+			return strings.ToLower(library)
+		}
+	}
+	return strings.ToLower(platform)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/gobwas/glob"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	gateFileName    = "kics-gate.yaml"
+	gateFileVersion = 1
+)
+
+// loadGate reads and validates the policy gate file, it returns nil when there is none
+func loadGate(gatePath string, scanPaths []string) (*model.GateFile, error) {
+	path := getScannedFilePath(gatePath, gateFileName, scanPaths)
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var gate model.GateFile
+	if err := yaml.Unmarshal(content, &gate); err != nil {
+		return nil, fmt.Errorf("failed to parse gate file %s: %w", path, err)
+	}
+	if gate.Version != gateFileVersion {
+		return nil, fmt.Errorf("unsupported gate file version %d in %s", gate.Version, path)
+	}
+	if err := validateGateRules(gate.Rules); err != nil {
+		return nil, fmt.Errorf("invalid gate file %s: %w", path, err)
+	}
+
+	log.Info().Msgf("Loaded %d gate rules from %s", len(gate.Rules), path)
+	return &gate, nil
+}
+
+func validateGateRules(rules []model.GateRule) error {
+	for idx := range rules {
+		rule := &rules[idx]
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("gate rule %d has no name", idx+1)
+		}
+		if rule.Action == "" {
+			rule.Action = model.GateActionFail
+		}
+		rule.Action = strings.ToLower(rule.Action)
+		if rule.Action != model.GateActionFail && rule.Action != model.GateActionWarn {
+			return fmt.Errorf("gate rule %q has an invalid action %q, expected fail or warn", rule.Name, rule.Action)
+		}
+		for _, severity := range rule.Severities {
+			if !isValidSeverity(severity) {
+				return fmt.Errorf("gate rule %q has an invalid severity %q", rule.Name, severity)
+			}
+		}
+		for _, path := range rule.Paths {
+			if _, err := glob.Compile(filepath.ToSlash(path), '/'); err != nil {
+				return fmt.Errorf("gate rule %q has an invalid path glob %q: %w", rule.Name, path, err)
+			}
+		}
+		if rule.MaxCount < 0 {
+			return fmt.Errorf("gate rule %q has a negative maxCount", rule.Name)
+		}
+	}
+	return nil
+}
+
+// gateRuleMatcher keeps a gate rule with its compiled path globs
+type gateRuleMatcher struct {
+	rule  *model.GateRule
+	globs []glob.Glob
+}
+
+// evaluateGate counts the results matching each rule of the gate, a rule is violated when they are more than its
+// maximum count
+func evaluateGate(gate *model.GateFile, summary *model.Summary, basePaths []string) *model.GateResult {
+	result := &model.GateResult{Passed: true, Rules: make([]model.GateRuleResult, 0, len(gate.Rules))}
+	for idx := range gate.Rules {
+		matcher := gateRuleMatcher{rule: &gate.Rules[idx]}
+		for _, path := range gate.Rules[idx].Paths {
+			// the globs were already validated when the gate was loaded
+			matcher.globs = append(matcher.globs, glob.MustCompile(filepath.ToSlash(path), '/'))
+		}
+
+		ruleResult := model.GateRuleResult{GateRule: gate.Rules[idx]}
+		for i := range summary.Queries {
+			query := &summary.Queries[i]
+			if !matcher.matchesQuery(query) {
+				continue
+			}
+			for j := range query.Files {
+				// the file names of the summary are relative to the working directory, the globs to the scanned paths
+				fileName := query.Files[j].FileName
+				if original, ok := summary.FilePaths[fileName]; ok {
+					fileName = original
+				}
+				if !matcher.matchesPath(fileName, basePaths) {
+					continue
+				}
+				ruleResult.Count++
+				if ruleResult.HighestSeverity == "" || severityIndex(query.Severity) < severityIndex(ruleResult.HighestSeverity) {
+					ruleResult.HighestSeverity = query.Severity
+				}
+			}
+		}
+
+		ruleResult.Violated = ruleResult.Count > gate.Rules[idx].MaxCount
+		if ruleResult.Violated {
+			if gate.Rules[idx].Action == model.GateActionFail {
+				result.Passed = false
+			}
+			log.Warn().Msgf("Gate rule %s violated: %d results, maximum %d", gate.Rules[idx].Name,
+				ruleResult.Count, gate.Rules[idx].MaxCount)
+		}
+		result.Rules = append(result.Rules, ruleResult)
+	}
+	return result
+}
+
+// matchesQuery returns true when the query meets the severity, category, query ID and platform criteria
+func (m *gateRuleMatcher) matchesQuery(query *model.QueryResult) bool {
+	return matchesAny(m.rule.Severities, string(query.Severity)) &&
+		matchesAny(m.rule.Categories, query.Category) &&
+		matchesAny(m.rule.QueryIDs, query.QueryID) &&
+		matchesAny(m.rule.Platforms, query.Platform)
+}
+
+func (m *gateRuleMatcher) matchesPath(fileName string, basePaths []string) bool {
+	if len(m.globs) == 0 {
+		return true
+	}
+	for _, g := range m.globs {
+		if matchesPath(g, fileName, basePaths) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny returns true when the criteria are empty or one of them is the value, case insensitive
+func matchesAny(criteria []string, value string) bool {
+	if len(criteria) == 0 {
+		return true
+	}
+	for _, criterion := range criteria {
+		if strings.EqualFold(criterion, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func severityIndex(severity model.Severity) int {
+	for idx, s := range model.AllSeverities {
+		if s == severity {
+			return idx
+		}
+	}
+	return len(model.AllSeverities)
+}
@@ -0,0 +1,117 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var gateFixtures = filepath.FromSlash("../../test/fixtures/test_gate")
+
+func Test_LoadGate(t *testing.T) {
+	gate, err := loadGate("", []string{gateFixtures})
+	require.NoError(t, err)
+	require.Len(t, gate.Rules, 3)
+	require.Equal(t, []string{"prod/**"}, gate.Rules[0].Paths)
+	require.Equal(t, 5, gate.Rules[1].MaxCount)
+	require.Equal(t, model.GateActionWarn, gate.Rules[2].Action)
+
+	gate, err = loadGate("", []string{filepath.Join(gateFixtures, "missing")})
+	require.NoError(t, err)
+	require.Nil(t, gate)
+
+	_, err = loadGate("", []string{filepath.Join(gateFixtures, "invalid")})
+	require.ErrorContains(t, err, "invalid action")
+
+	_, err = loadGate(filepath.Join(gateFixtures, "missing.yaml"), []string{})
+	require.Error(t, err)
+}
+
+func Test_ValidateGateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    model.GateRule
+		wantErr string
+	}{
+		{name: "valid", rule: model.GateRule{Name: "any high", Severities: []string{"high"}}},
+		{name: "no_name", rule: model.GateRule{Severities: []string{"high"}}, wantErr: "no name"},
+		{name: "invalid_action", rule: model.GateRule{Name: "r", Action: "block"}, wantErr: "invalid action"},
+		{name: "invalid_severity", rule: model.GateRule{Name: "r", Severities: []string{"urgent"}}, wantErr: "severity"},
+		{name: "invalid_glob", rule: model.GateRule{Name: "r", Paths: []string{"prod/[a"}}, wantErr: "path glob"},
+		{name: "negative_max_count", rule: model.GateRule{Name: "r", MaxCount: -1}, wantErr: "maxCount"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := []model.GateRule{tt.rule}
+			err := validateGateRules(rules)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				require.Equal(t, model.GateActionFail, rules[0].Action)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_EvaluateGate(t *testing.T) {
+	gate, err := loadGate("", []string{gateFixtures})
+	require.NoError(t, err)
+
+	basePath := filepath.FromSlash("/repo")
+	secrets := make([]model.VulnerableFile, 0)
+	for i := 0; i < 6; i++ {
+		secrets = append(secrets, model.VulnerableFile{FileName: filepath.FromSlash("/repo/config.yaml"), Line: i + 1})
+	}
+	summary := &model.Summary{
+		Queries: model.QueryResultSlice{
+			{
+				QueryID:  "q1",
+				Severity: model.SeverityHigh,
+				Platform: "Terraform",
+				Files: []model.VulnerableFile{
+					{FileName: filepath.FromSlash("/repo/dev/main.tf")},
+				},
+			},
+			{
+				QueryID:  "q2",
+				Severity: model.SeverityMedium,
+				Category: "Secret Management",
+				Platform: "Common",
+				Files:    secrets,
+			},
+			{
+				QueryID:  "q3",
+				Severity: model.SeverityInfo,
+				Platform: "Terraform",
+				Files: []model.VulnerableFile{
+					{FileName: filepath.FromSlash("/repo/prod/main.tf")},
+				},
+			},
+		},
+	}
+
+	result := evaluateGate(gate, summary, []string{basePath})
+	require.False(t, result.Passed)
+	require.Equal(t, 0, result.Rules[0].Count)
+	require.False(t, result.Rules[0].Violated)
+	require.Equal(t, 6, result.Rules[1].Count)
+	require.True(t, result.Rules[1].Violated)
+	require.Equal(t, model.Severity(model.SeverityMedium), result.Rules[1].HighestSeverity)
+	require.Equal(t, 1, result.Rules[2].Count)
+	require.False(t, result.Rules[2].Violated)
+
+	summary.Queries[0].Files[0].FileName = filepath.FromSlash("/repo/prod/main.tf")
+	summary.Queries[1].Files = secrets[:5]
+	result = evaluateGate(gate, summary, []string{basePath})
+	require.False(t, result.Passed)
+	require.True(t, result.Rules[0].Violated)
+	require.Equal(t, model.Severity(model.SeverityHigh), result.Rules[0].HighestSeverity)
+	require.False(t, result.Rules[1].Violated)
+
+	summary.Queries[0].Files = nil
+	result = evaluateGate(gate, summary, []string{basePath})
+	require.True(t, result.Passed)
+}
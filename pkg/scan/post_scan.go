@@ -9,11 +9,15 @@ import (
 
 	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
 	"github.com/Checkmarx/kics/pkg/descriptions"
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
 	"github.com/Checkmarx/kics/pkg/engine/provider"
 	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/Checkmarx/kics/pkg/ownership"
 	consolePrinter "github.com/Checkmarx/kics/pkg/printer"
 	"github.com/Checkmarx/kics/pkg/progress"
 	"github.com/Checkmarx/kics/pkg/report"
+	"github.com/Checkmarx/kics/pkg/results"
+	"github.com/Checkmarx/kics/pkg/risk"
 	"github.com/rs/zerolog/log"
 )
 
@@ -70,16 +74,40 @@ func (c *Client) resolveOutputs(
 			return err
 		}
 	}
+	if c.Coverage != nil {
+		if err := coverage.Write(c.ScanParams.QueryCoverage, c.Coverage.Report()); err != nil {
+			return err
+		}
+		log.Info().Msgf("Queries coverage written to '%s'", c.ScanParams.QueryCoverage)
+	}
+
+	options := &report.Options{MarkdownMaxLength: c.ScanParams.MarkdownMaxLength}
+	if c.ScanParams.SarifBaseline != "" {
+		baseline, err := results.Load(c.ScanParams.SarifBaseline)
+		if err != nil {
+			return err
+		}
+		options.SarifBaseline = baseline
+	}
+	if c.ScanParams.HTMLHistory != "" {
+		history, err := results.LoadHistory(c.ScanParams.HTMLHistory)
+		if err != nil {
+			return err
+		}
+		options.HTMLHistory = history
+	}
 
 	return printOutput(
 		c.ScanParams.OutputPath,
 		c.ScanParams.OutputName,
 		summary, c.ScanParams.ReportFormats,
+		options,
 		proBarBuilder,
 	)
 }
 
-func printOutput(outputPath, filename string, body interface{}, formats []string, proBarBuilder progress.PbBuilder) error {
+func printOutput(outputPath, filename string, body interface{}, formats []string, options *report.Options,
+	proBarBuilder progress.PbBuilder) error {
 	log.Debug().Msg("console.printOutput()")
 	if outputPath == "" {
 		return nil
@@ -89,7 +117,7 @@ func printOutput(outputPath, filename string, body interface{}, formats []string
 	}
 
 	log.Debug().Msgf("Output formats provided [%v]", strings.Join(formats, ","))
-	err := consoleHelpers.GenerateReport(outputPath, filename, body, formats, proBarBuilder)
+	err := consoleHelpers.GenerateReport(outputPath, filename, body, formats, options, proBarBuilder)
 
 	return err
 }
@@ -108,7 +136,7 @@ func (c *Client) postScan(scanResults *Results) error {
 
 	// mask results preview if Secrets Scan is disabled
 	if c.ScanParams.DisableSecrets {
-		err := maskPreviewLines(c.ScanParams.SecretsRegexesPath, scanResults)
+		err := maskPreviewLines(c.ScanParams.SecretsRegexesPath, c.ScanParams.SecretsRulesPacks, scanResults)
 		if err != nil {
 			log.Err(err)
 			return err
@@ -119,6 +147,25 @@ func (c *Client) postScan(scanResults *Results) error {
 		ScannedPaths:      c.ScanParams.Path,
 		PathExtractionMap: scanResults.ExtractedPaths.ExtractionMap,
 	})
+	summary.Waivers = scanResults.WaiversUsage
+
+	if c.ScanParams.Ownership || len(c.ScanParams.Owners) > 0 {
+		ownership.NewEnricher().Enrich(&summary)
+		if len(c.ScanParams.Owners) > 0 {
+			ownership.Filter(&summary, c.ScanParams.Owners)
+		}
+	}
+
+	weights, err := risk.ParseWeights(c.ScanParams.RiskWeights)
+	if err != nil {
+		log.Err(err)
+		return err
+	}
+	risk.NewScorer(weights, &summary, scanResults.Files).Score(&summary)
+
+	if c.Gate != nil {
+		summary.Gate = evaluateGate(c.Gate, &summary, c.ScanParams.Path)
+	}
 
 	if err := c.resolveOutputs(
 		&summary,
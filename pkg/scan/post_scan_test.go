@@ -10,6 +10,7 @@ import (
 	"github.com/Checkmarx/kics/pkg/model"
 	"github.com/Checkmarx/kics/pkg/printer"
 	"github.com/Checkmarx/kics/pkg/progress"
+	"github.com/Checkmarx/kics/pkg/report"
 	"github.com/stretchr/testify/require"
 )
 
@@ -279,7 +280,7 @@ func Test_PrintOutput(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := printOutput(tt.outputPath, tt.filename, tt.body, tt.formats, tt.proBarBuilder)
+			err := printOutput(tt.outputPath, tt.filename, tt.body, tt.formats, &report.Options{}, tt.proBarBuilder)
 			os.Remove(filepath.Join("..", "..", tt.filename+".json"))
 			require.NoError(t, err)
 		})
@@ -10,8 +10,8 @@ import (
 	"github.com/Checkmarx/kics/pkg/model"
 )
 
-func maskPreviewLines(secretsPath string, scanResults *Results) error {
-	secretsRegexRulesContent, err := getSecretsRegexRules(secretsPath)
+func maskPreviewLines(secretsPath string, rulesPacksPaths []string, scanResults *Results) error {
+	secretsRegexRulesContent, err := getSecretsRegexRules(secretsPath, rulesPacksPaths)
 	if err != nil {
 		return err
 	}
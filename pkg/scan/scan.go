@@ -3,14 +3,18 @@ package scan
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Checkmarx/kics/assets"
 	consoleHelpers "github.com/Checkmarx/kics/internal/console/helpers"
 	"github.com/Checkmarx/kics/pkg/engine"
+	"github.com/Checkmarx/kics/pkg/engine/coverage"
 	"github.com/Checkmarx/kics/pkg/engine/provider"
 	"github.com/Checkmarx/kics/pkg/engine/secrets"
 	"github.com/Checkmarx/kics/pkg/engine/source"
@@ -27,8 +31,11 @@ import (
 	terraformParser "github.com/Checkmarx/kics/pkg/parser/terraform"
 	yamlParser "github.com/Checkmarx/kics/pkg/parser/yaml"
 	"github.com/Checkmarx/kics/pkg/resolver"
+	ansibleResolver "github.com/Checkmarx/kics/pkg/resolver/ansible"
 	"github.com/Checkmarx/kics/pkg/resolver/helm"
 	"github.com/Checkmarx/kics/pkg/scanner"
+	"github.com/Checkmarx/kics/pkg/utils"
+	"github.com/google/uuid"
 
 	"github.com/rs/zerolog/log"
 )
@@ -39,6 +46,7 @@ type Results struct {
 	ExtractedPaths provider.ExtractedPath
 	Files          model.FileMetadatas
 	FailedQueries  map[string]error
+	WaiversUsage   []model.WaiverUsage
 }
 
 type executeScanParameters struct {
@@ -93,7 +101,12 @@ func (c *Client) initScan(ctx context.Context) (*executeScanParameters, error) {
 			return nil, err
 		}
 
-		secretsRegexRulesContent, err := getSecretsRegexRules(c.ScanParams.SecretsRegexesPath)
+		if c.ScanParams.QueryCoverage != "" {
+			c.Coverage = coverage.NewCollector()
+			inspector.EnableQueryCoverage(c.Coverage)
+		}
+
+		secretsRegexRulesContent, err := getSecretsRegexRules(c.ScanParams.SecretsRegexesPath, c.ScanParams.SecretsRulesPacks)
 		if err != nil {
 			return nil, err
 		}
@@ -114,6 +127,7 @@ func (c *Client) initScan(ctx context.Context) (*executeScanParameters, error) {
 			log.Err(err)
 			return nil, err
 		}
+		secretsInspector.SetMinConfidence(c.ScanParams.SecretsMinConfidence)
 	} else {
 		gptInspector, err = gpt.NewGptInspector(ctx,
 			querySource,
@@ -193,11 +207,14 @@ func (c *Client) executeScan(ctx context.Context) (*Results, error) {
 		return nil, err
 	}
 
+	results, waiversUsage := applyWaivers(results, c.Waivers, c.ScanParams.Path, time.Now())
+
 	return &Results{
 		Results:        results,
 		ExtractedPaths: executeScanParameters.extractedPaths,
 		Files:          files,
 		FailedQueries:  failedQueries,
+		WaiversUsage:   waiversUsage,
 	}, nil
 }
 
@@ -221,7 +238,7 @@ func getExcludeResultsMap(excludeResults []string) map[string]bool {
 	return excludeResultsMap
 }
 
-func getSecretsRegexRules(regexRulesPath string) (regexRulesContent string, err error) {
+func getSecretsRegexRules(regexRulesPath string, rulesPacksPaths []string) (regexRulesContent string, err error) {
 	if len(regexRulesPath) > 0 {
 		b, err := os.ReadFile(regexRulesPath)
 		if err != nil {
@@ -232,7 +249,23 @@ func getSecretsRegexRules(regexRulesPath string) (regexRulesContent string, err
 		regexRulesContent = assets.SecretsQueryRegexRulesJSON
 	}
 
-	return regexRulesContent, nil
+	if len(rulesPacksPaths) == 0 {
+		return regexRulesContent, nil
+	}
+
+	// rules packs are merged on top of the default or custom rules
+	rules, err := secrets.LoadRulesPacks(regexRulesContent, rulesPacksPaths)
+	if err != nil {
+		return "", err
+	}
+	if errs := secrets.ValidateRules(&rules); len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
 }
 
 func (c *Client) createQueryFilter() *source.QueryInspectorParameters {
@@ -270,16 +303,7 @@ func (c *Client) createService(
 		return nil, err
 	}
 
-	combinedParser, err := parser.NewBuilder().
-		Add(&jsonParser.Parser{}).
-		Add(&yamlParser.Parser{}).
-		Add(terraformParser.NewDefaultWithVarsPath(c.ScanParams.TerraformVarsPath)).
-		Add(&dockerParser.Parser{}).
-		Add(&protoParser.Parser{}).
-		Add(&buildahParser.Parser{}).
-		Add(&ansibleConfigParser.Parser{}).
-		Add(&ansibleHostsParser.Parser{}).
-		Build(querySource.Types, querySource.CloudProviders)
+	combinedParser, err := NewParsers(c.ScanParams.TerraformVarsPath, querySource.Types, querySource.CloudProviders)
 	if err != nil {
 		return nil, err
 	}
@@ -289,9 +313,7 @@ func (c *Client) createService(
 	}
 
 	// combinedResolver to be used to resolve files and templates
-	combinedResolver, err := resolver.NewBuilder().
-		Add(&helm.Resolver{}).
-		Build()
+	combinedResolver, err := NewResolver()
 	if err != nil {
 		return nil, err
 	}
@@ -316,6 +338,69 @@ func (c *Client) createService(
 	return services, nil
 }
 
+// NewParsers builds the parsers of all the file kinds KICS scans, for the given platforms and cloud providers
+func NewParsers(terraformVarsPath string, types, cloudProviders []string) ([]*parser.Parser, error) {
+	return parser.NewBuilder().
+		Add(&jsonParser.Parser{}).
+		Add(&yamlParser.Parser{}).
+		Add(terraformParser.NewDefaultWithVarsPath(terraformVarsPath)).
+		Add(&dockerParser.Parser{}).
+		Add(&protoParser.Parser{}).
+		Add(&buildahParser.Parser{}).
+		Add(&ansibleConfigParser.Parser{}).
+		Add(&ansibleHostsParser.Parser{}).
+		Build(types, cloudProviders)
+}
+
+// ParseFile parses the file with the first of the parsers that supports it and prepares its documents
+// as the scan does
+func ParseFile(parsers []*parser.Parser, scanID, path string) (model.FileMetadatas, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(model.FileMetadatas, 0)
+	for _, p := range parsers {
+		documents, err := p.Parse(path, content)
+		if errors.Is(err, parser.ErrNotSupportedFile) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fileSuppressions := model.GetSuppressions(string(content))
+		for _, document := range documents.Docs {
+			files = append(files, model.FileMetadata{
+				ID:                uuid.NewString(),
+				ScanID:            scanID,
+				Document:          kics.PrepareScanDocument(document, documents.Kind),
+				LineInfoDocument:  document,
+				OriginalData:      documents.Content,
+				Kind:              documents.Kind,
+				FilePath:          path,
+				Commands:          p.CommentsCommands(path, content),
+				LinesIgnore:       documents.IgnoreLines,
+				Suppressions:      fileSuppressions,
+				LinesOriginalData: utils.SplitLines(documents.Content),
+				ResolvedFiles:     documents.ResolvedFiles,
+			})
+		}
+		return files, nil
+	}
+
+	return nil, parser.ErrNotSupportedFile
+}
+
+// NewResolver builds the resolver of the Helm charts and Ansible projects
+func NewResolver() (*resolver.Resolver, error) {
+	return resolver.NewBuilder().
+		Add(&helm.Resolver{}).
+		Add(&ansibleResolver.Resolver{}).
+		Build()
+}
+
 func createGptService(
 	gptInspector *gpt.Inspector,
 	filesSource *provider.FileSystemSourceProvider,
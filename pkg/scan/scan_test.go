@@ -1,6 +1,7 @@
 package scan
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -45,7 +46,7 @@ func Test_GetSecretsRegexRules(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Client{}
 			c.ScanParams = &tt.scanParams
-			v, err := getSecretsRegexRules(c.ScanParams.SecretsRegexesPath)
+			v, err := getSecretsRegexRules(c.ScanParams.SecretsRegexesPath, c.ScanParams.SecretsRulesPacks)
 
 			require.Equal(t, tt.expectedOutput, v)
 			if tt.expectedError {
@@ -122,3 +123,20 @@ func Test_CreateQueryFilter(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod.yaml")
+	content := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\nspec:\n" +
+		"  # kics-scan ignore-line reason=\"sandbox only\" expires=2099-01-01\n  hostNetwork: true\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), os.ModePerm))
+
+	parsers, err := NewParsers("", []string{""}, []string{""})
+	require.NoError(t, err)
+
+	files, err := ParseFile(parsers, "scan", path)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Len(t, files[0].Suppressions, 1)
+	require.Equal(t, "sandbox only", files[0].Suppressions[0].Reason)
+	require.Equal(t, "2099-01-01", files[0].Suppressions[0].Expires)
+}
@@ -0,0 +1,203 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/gobwas/glob"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	waiversFileName    = "kics-waivers.yaml"
+	waiversFileVersion = 1
+)
+
+// waiverMatcher keeps a waiver with its compiled path glob and the count of results it excluded
+type waiverMatcher struct {
+	waiver  model.Waiver
+	glob    glob.Glob
+	expired bool
+	count   int
+}
+
+// getWaiversPath returns the waivers file path, when it is not provided the kics-waivers.yaml file
+// in the root of the scanned directories is used, if present
+func getWaiversPath(waiversPath string, scanPaths []string) string {
+	return getScannedFilePath(waiversPath, waiversFileName, scanPaths)
+}
+
+// getScannedFilePath returns the given path, when it is empty the file with the given name in the root of the
+// scanned directories, if present
+func getScannedFilePath(path, fileName string, scanPaths []string) string {
+	if path != "" {
+		return path
+	}
+	for _, scanPath := range scanPaths {
+		candidate := filepath.Join(scanPath, fileName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// loadWaivers reads and validates the waivers file
+func loadWaivers(waiversPath string, scanPaths []string) ([]model.Waiver, error) {
+	path := getWaiversPath(waiversPath, scanPaths)
+	if path == "" {
+		return []model.Waiver{}, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var waiversFile model.WaiversFile
+	if err := yaml.Unmarshal(content, &waiversFile); err != nil {
+		return nil, fmt.Errorf("failed to parse waivers file %s: %w", path, err)
+	}
+	if waiversFile.Version != waiversFileVersion {
+		return nil, fmt.Errorf("unsupported waivers file version %d in %s", waiversFile.Version, path)
+	}
+	if err := validateWaivers(waiversFile.Waivers); err != nil {
+		return nil, fmt.Errorf("invalid waivers file %s: %w", path, err)
+	}
+
+	log.Info().Msgf("Loaded %d waivers from %s", len(waiversFile.Waivers), path)
+	return waiversFile.Waivers, nil
+}
+
+func validateWaivers(waivers []model.Waiver) error {
+	for idx := range waivers {
+		waiver := &waivers[idx]
+		if strings.TrimSpace(waiver.Reason) == "" {
+			return fmt.Errorf("waiver %d has no reason", idx+1)
+		}
+		if waiver.SimilarityID == "" && waiver.QueryID == "" && waiver.Path == "" &&
+			waiver.ResourceName == "" && waiver.Severity == "" {
+			return fmt.Errorf("waiver %d has no matching criteria", idx+1)
+		}
+		if waiver.Expires != "" {
+			if _, err := time.Parse(model.SuppressionDateLayout, waiver.Expires); err != nil {
+				return fmt.Errorf("waiver %d has an invalid expiration date %q", idx+1, waiver.Expires)
+			}
+		}
+		if waiver.Severity != "" && !isValidSeverity(waiver.Severity) {
+			return fmt.Errorf("waiver %d has an invalid severity %q", idx+1, waiver.Severity)
+		}
+		if waiver.Path != "" {
+			if _, err := glob.Compile(filepath.ToSlash(waiver.Path), '/'); err != nil {
+				return fmt.Errorf("waiver %d has an invalid path glob %q: %w", idx+1, waiver.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func isValidSeverity(severity string) bool {
+	for _, validSeverity := range model.AllSeverities {
+		if strings.EqualFold(string(validSeverity), severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyWaivers removes the results matched by an active waiver and reports the usage of each waiver,
+// expired waivers do not exclude results
+func applyWaivers(results []model.Vulnerability, waivers []model.Waiver,
+	basePaths []string, now time.Time) ([]model.Vulnerability, []model.WaiverUsage) {
+	if len(waivers) == 0 {
+		return results, nil
+	}
+
+	matchers := make([]waiverMatcher, 0, len(waivers))
+	for idx := range waivers {
+		matcher := waiverMatcher{
+			waiver:  waivers[idx],
+			expired: waivers[idx].Expired(now),
+		}
+		if waivers[idx].Path != "" {
+			// the glob was already validated when the waivers were loaded
+			matcher.glob = glob.MustCompile(filepath.ToSlash(waivers[idx].Path), '/')
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	filtered := make([]model.Vulnerability, 0, len(results))
+	for idx := range results {
+		if matcher := findWaiver(matchers, &results[idx], basePaths); matcher != nil {
+			matcher.count++
+			log.Debug().Msgf("Excluding result SimilarityID: %s, waiver: %s", results[idx].SimilarityID, matcher.waiver.Reason)
+			continue
+		}
+		filtered = append(filtered, results[idx])
+	}
+
+	usage := make([]model.WaiverUsage, 0, len(matchers))
+	for idx := range matchers {
+		status := model.WaiverStatusUsed
+		switch {
+		case matchers[idx].expired:
+			status = model.WaiverStatusExpired
+			log.Warn().Msgf("Waiver expired on %s: %s", matchers[idx].waiver.Expires, matchers[idx].waiver.Reason)
+		case matchers[idx].count == 0:
+			status = model.WaiverStatusUnused
+			log.Warn().Msgf("Waiver did not match any result: %s", matchers[idx].waiver.Reason)
+		}
+		usage = append(usage, model.WaiverUsage{
+			Waiver:  matchers[idx].waiver,
+			Status:  status,
+			Matches: matchers[idx].count,
+		})
+	}
+	return filtered, usage
+}
+
+func findWaiver(matchers []waiverMatcher, result *model.Vulnerability, basePaths []string) *waiverMatcher {
+	for idx := range matchers {
+		if !matchers[idx].expired && matchers[idx].matches(result, basePaths) {
+			return &matchers[idx]
+		}
+	}
+	return nil
+}
+
+// matches returns true when the result meets all the waiver criteria
+func (m *waiverMatcher) matches(result *model.Vulnerability, basePaths []string) bool {
+	waiver := &m.waiver
+	if waiver.SimilarityID != "" && waiver.SimilarityID != result.SimilarityID {
+		return false
+	}
+	if waiver.QueryID != "" && !strings.EqualFold(waiver.QueryID, result.QueryID) {
+		return false
+	}
+	if waiver.ResourceName != "" && waiver.ResourceName != result.ResourceName {
+		return false
+	}
+	if waiver.Severity != "" && !strings.EqualFold(waiver.Severity, string(result.Severity)) {
+		return false
+	}
+	return m.glob == nil || matchesPath(m.glob, result.FileName, basePaths)
+}
+
+// matchesPath matches the glob against the file path and the file path relative to each scanned path
+func matchesPath(g glob.Glob, fileName string, basePaths []string) bool {
+	if g.Match(filepath.ToSlash(fileName)) {
+		return true
+	}
+	for _, basePath := range basePaths {
+		if relative, err := filepath.Rel(basePath, fileName); err == nil && !strings.HasPrefix(relative, "..") {
+			if g.Match(filepath.ToSlash(relative)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Checkmarx/kics/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+var waiversFixtures = filepath.FromSlash("../../test/fixtures/test_waivers")
+
+func Test_LoadWaivers(t *testing.T) {
+	waivers, err := loadWaivers("", []string{waiversFixtures})
+	require.NoError(t, err)
+	require.Len(t, waivers, 3)
+	require.Equal(t, "2027-01-01", waivers[0].Expires)
+	require.Equal(t, "modules/**/*.tf", waivers[0].Path)
+
+	waivers, err = loadWaivers("", []string{filepath.Join(waiversFixtures, "missing")})
+	require.NoError(t, err)
+	require.Empty(t, waivers)
+
+	_, err = loadWaivers("", []string{filepath.Join(waiversFixtures, "invalid")})
+	require.ErrorContains(t, err, "has no reason")
+
+	_, err = loadWaivers(filepath.Join(waiversFixtures, "missing.yaml"), []string{})
+	require.Error(t, err)
+}
+
+func Test_ValidateWaivers(t *testing.T) {
+	tests := []struct {
+		name    string
+		waiver  model.Waiver
+		wantErr string
+	}{
+		{name: "valid", waiver: model.Waiver{QueryID: "q1", Reason: "accepted"}},
+		{name: "no_criteria", waiver: model.Waiver{Reason: "accepted"}, wantErr: "no matching criteria"},
+		{name: "invalid_expires", waiver: model.Waiver{QueryID: "q1", Reason: "accepted", Expires: "soon"}, wantErr: "expiration date"},
+		{name: "invalid_severity", waiver: model.Waiver{Severity: "urgent", Reason: "accepted"}, wantErr: "severity"},
+		{name: "invalid_glob", waiver: model.Waiver{Path: "modules/[a", Reason: "accepted"}, wantErr: "path glob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWaivers([]model.Waiver{tt.waiver})
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_ApplyWaivers(t *testing.T) {
+	basePath := filepath.FromSlash("/repo")
+	results := []model.Vulnerability{
+		{SimilarityID: "s1", QueryID: "q1", FileName: filepath.FromSlash("/repo/modules/web/main.tf"), Severity: model.SeverityHigh},
+		{SimilarityID: "s2", QueryID: "q1", FileName: filepath.FromSlash("/repo/main.tf"), Severity: model.SeverityHigh},
+		{SimilarityID: "s3", QueryID: "q2", FileName: filepath.FromSlash("/repo/main.tf"), Severity: model.SeverityInfo,
+			ResourceName: "legacy"},
+		{SimilarityID: "s4", QueryID: "q3", FileName: filepath.FromSlash("/repo/main.tf"), Severity: model.SeverityLow},
+	}
+	waivers := []model.Waiver{
+		{QueryID: "Q1", Path: "modules/**/*.tf", Reason: "public website buckets"},
+		{Severity: "info", ResourceName: "legacy", Reason: "decommissioned"},
+		{SimilarityID: "s4", Reason: "accepted risk", Expires: "2026-01-01"},
+		{SimilarityID: "s5", Reason: "fixed"},
+	}
+
+	filtered, usage := applyWaivers(results, waivers, []string{basePath}, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	require.Len(t, filtered, 2)
+	require.Equal(t, "s2", filtered[0].SimilarityID)
+	require.Equal(t, "s4", filtered[1].SimilarityID)
+	require.Equal(t, []model.WaiverUsage{
+		{Waiver: waivers[0], Status: model.WaiverStatusUsed, Matches: 1},
+		{Waiver: waivers[1], Status: model.WaiverStatusUsed, Matches: 1},
+		{Waiver: waivers[2], Status: model.WaiverStatusExpired, Matches: 0},
+		{Waiver: waivers[3], Status: model.WaiverStatusUnused, Matches: 0},
+	}, usage)
+
+	filtered, usage = applyWaivers(results, []model.Waiver{}, []string{basePath}, time.Now())
+	require.Equal(t, results, filtered)
+	require.Nil(t, usage)
+}
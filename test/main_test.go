@@ -30,45 +30,48 @@ import (
 
 var (
 	queriesPaths = map[string]model.QueryConfig{
-		"../assets/queries/terraform/aws_bom":               {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/aws":                   {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/azure":                 {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/gcp":                   {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/gcp_bom":               {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/github":                {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/kubernetes":            {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/general":               {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/terraform/alicloud":              {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
-		"../assets/queries/crossplane/aws":                  {FileKind: []model.FileKind{model.KindYAML}, Platform: "crossplane"},
-		"../assets/queries/crossplane/azure":                {FileKind: []model.FileKind{model.KindYAML}, Platform: "crossplane"},
-		"../assets/queries/crossplane/gcp":                  {FileKind: []model.FileKind{model.KindYAML}, Platform: "crossplane"},
-		"../assets/queries/pulumi/aws":                      {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
-		"../assets/queries/pulumi/gcp":                      {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
-		"../assets/queries/pulumi/kubernetes":               {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
-		"../assets/queries/pulumi/azure":                    {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
-		"../assets/queries/k8s":                             {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "k8s"},
-		"../assets/queries/cloudFormation/aws":              {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "cloudFormation"},
-		"../assets/queries/cloudFormation/aws_bom":          {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "cloudFormation"},
-		"../assets/queries/cloudFormation/aws_sam":          {FileKind: []model.FileKind{model.KindYAML}, Platform: "cloudFormation"},
-		"../assets/queries/ansible/aws":                     {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
-		"../assets/queries/ansible/gcp":                     {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
-		"../assets/queries/ansible/azure":                   {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
-		"../assets/queries/ansible/general":                 {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
-		"../assets/queries/ansible/config":                  {FileKind: []model.FileKind{model.KindCFG}, Platform: "ansible"},
-		"../assets/queries/ansible/hosts":                   {FileKind: []model.FileKind{model.KindINI, model.KindYAML}, Platform: "ansible"},
-		"../assets/queries/dockerfile":                      {FileKind: []model.FileKind{model.KindDOCKER}, Platform: "dockerfile"},
-		"../assets/queries/dockerCompose":                   {FileKind: []model.FileKind{model.KindYAML}, Platform: "dockerCompose"},
-		"../assets/queries/openAPI/general":                 {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
-		"../assets/queries/openAPI/3.0":                     {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
-		"../assets/queries/openAPI/2.0":                     {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
-		"../assets/queries/azureResourceManager":            {FileKind: []model.FileKind{model.KindJSON}, Platform: "azureResourceManager"},
-		"../assets/queries/googleDeploymentManager/gcp":     {FileKind: []model.FileKind{model.KindYAML}, Platform: "googleDeploymentManager"},
-		"../assets/queries/googleDeploymentManager/gcp_bom": {FileKind: []model.FileKind{model.KindYAML}, Platform: "googleDeploymentManager"},
-		"../assets/queries/grpc":                            {FileKind: []model.FileKind{model.KindPROTO}, Platform: "grpc"},
-		"../assets/queries/buildah":                         {FileKind: []model.FileKind{model.KindBUILDAH}, Platform: "buildah"},
-		"../assets/queries/serverlessFW":                    {FileKind: []model.FileKind{model.KindYAML, model.KindYML}, Platform: "serverlessFW"},
-		"../assets/queries/knative":                         {FileKind: []model.FileKind{model.KindYAML}, Platform: "knative"},
-		"../assets/queries/cicd/github":                     {FileKind: []model.FileKind{model.KindYAML}, Platform: "cicd"},
+		"../assets/queries/terraform/aws_bom":                {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/aws":                    {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/azure":                  {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/azure_bom":              {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/gcp":                    {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/gcp_bom":                {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/github":                 {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/kubernetes":             {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/general":                {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/terraform/alicloud":               {FileKind: []model.FileKind{model.KindTerraform, model.KindJSON}, Platform: "terraform"},
+		"../assets/queries/crossplane/aws":                   {FileKind: []model.FileKind{model.KindYAML}, Platform: "crossplane"},
+		"../assets/queries/crossplane/azure":                 {FileKind: []model.FileKind{model.KindYAML}, Platform: "crossplane"},
+		"../assets/queries/crossplane/gcp":                   {FileKind: []model.FileKind{model.KindYAML}, Platform: "crossplane"},
+		"../assets/queries/pulumi/aws":                       {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
+		"../assets/queries/pulumi/gcp":                       {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
+		"../assets/queries/pulumi/kubernetes":                {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
+		"../assets/queries/pulumi/azure":                     {FileKind: []model.FileKind{model.KindYAML}, Platform: "pulumi"},
+		"../assets/queries/k8s":                              {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "k8s"},
+		"../assets/queries/k8s/k8s_bom":                      {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "k8s"},
+		"../assets/queries/cloudFormation/aws":               {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "cloudFormation"},
+		"../assets/queries/cloudFormation/aws_bom":           {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "cloudFormation"},
+		"../assets/queries/cloudFormation/aws_sam":           {FileKind: []model.FileKind{model.KindYAML}, Platform: "cloudFormation"},
+		"../assets/queries/ansible/aws":                      {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
+		"../assets/queries/ansible/gcp":                      {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
+		"../assets/queries/ansible/azure":                    {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
+		"../assets/queries/ansible/general":                  {FileKind: []model.FileKind{model.KindYAML}, Platform: "ansible"},
+		"../assets/queries/ansible/config":                   {FileKind: []model.FileKind{model.KindCFG}, Platform: "ansible"},
+		"../assets/queries/ansible/hosts":                    {FileKind: []model.FileKind{model.KindINI, model.KindYAML}, Platform: "ansible"},
+		"../assets/queries/dockerfile":                       {FileKind: []model.FileKind{model.KindDOCKER}, Platform: "dockerfile"},
+		"../assets/queries/dockerCompose":                    {FileKind: []model.FileKind{model.KindYAML}, Platform: "dockerCompose"},
+		"../assets/queries/dockerCompose/docker_compose_bom": {FileKind: []model.FileKind{model.KindYAML}, Platform: "dockerCompose"},
+		"../assets/queries/openAPI/general":                  {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
+		"../assets/queries/openAPI/3.0":                      {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
+		"../assets/queries/openAPI/2.0":                      {FileKind: []model.FileKind{model.KindYAML, model.KindJSON}, Platform: "openAPI"},
+		"../assets/queries/azureResourceManager":             {FileKind: []model.FileKind{model.KindJSON}, Platform: "azureResourceManager"},
+		"../assets/queries/googleDeploymentManager/gcp":      {FileKind: []model.FileKind{model.KindYAML}, Platform: "googleDeploymentManager"},
+		"../assets/queries/googleDeploymentManager/gcp_bom":  {FileKind: []model.FileKind{model.KindYAML}, Platform: "googleDeploymentManager"},
+		"../assets/queries/grpc":                             {FileKind: []model.FileKind{model.KindPROTO}, Platform: "grpc"},
+		"../assets/queries/buildah":                          {FileKind: []model.FileKind{model.KindBUILDAH}, Platform: "buildah"},
+		"../assets/queries/serverlessFW":                     {FileKind: []model.FileKind{model.KindYAML, model.KindYML}, Platform: "serverlessFW"},
+		"../assets/queries/knative":                          {FileKind: []model.FileKind{model.KindYAML}, Platform: "knative"},
+		"../assets/queries/cicd/github":                      {FileKind: []model.FileKind{model.KindYAML}, Platform: "cicd"},
 	}
 
 	issueTypes = map[string]string{
@@ -141,7 +144,10 @@ func loadQueries(tb testing.TB) []queryEntry {
 		require.Nil(tb, err)
 
 		for _, f := range fs {
-			f.Name()
+			// nested query groups (e.g. k8s/k8s_bom) are loaded through their own entry
+			if _, ok := queriesPaths[path.Join(queriesPath, f.Name())]; ok {
+				continue
+			}
 			if f.IsDir() && f.Name() != "test" {
 				queriesDir = appendQueries(queriesDir, filepath.FromSlash(path.Join(queriesPath, f.Name())), queryConfig.FileKind, queryConfig.Platform)
 			} else {